@@ -4,10 +4,12 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/sirupsen/logrus"
 
+	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/anvil"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/axm"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/eth"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/eth/filters"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/eth/tracers"
+	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/evm"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/net"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/web3"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/txpool"
@@ -23,6 +25,9 @@ const (
 	AxmNamespace    = "axm"
 	TxPoolNamespace = "txpool"
 	DebugNamespace  = "debug"
+	TraceNamespace  = "trace"
+	EvmNamespace    = "evm"
+	AnvilNamespace  = "anvil"
 
 	apiVersion = "1.0"
 )
@@ -120,5 +125,38 @@ func GetAPIs(rep *repo.Repo, api api.CoreAPI, logger logrus.FieldLogger) ([]rpc.
 		},
 	)
 
+	apis = append(apis,
+		rpc.API{
+			Namespace: TraceNamespace,
+			Version:   apiVersion,
+			Service:   tracers.NewTraceAPI(rep, api, logger),
+			Public:    true,
+		},
+	)
+
+	// the dev APIs change the chain, only available for the dev consensus
+	if rep.Config.Consensus.Type == repo.ConsensusTypeSoloDev {
+		apis = append(apis,
+			rpc.API{
+				Namespace: EvmNamespace,
+				Version:   apiVersion,
+				Service:   evm.NewDevAPI(rep, api, logger),
+				Public:    true,
+			},
+		)
+	}
+
+	// the state setters and the impersonation, only available in the dev mode
+	if rep.Config.IsDevMode() {
+		apis = append(apis,
+			rpc.API{
+				Namespace: AnvilNamespace,
+				Version:   apiVersion,
+				Service:   anvil.NewAnvilAPI(rep, api, logger),
+				Public:    true,
+			},
+		)
+	}
+
 	return apis, nil
 }
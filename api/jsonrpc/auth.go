@@ -0,0 +1,269 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+
+	"github.com/axiomesh/axiom-ledger/pkg/ratelimiter"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+const (
+	publicPermissionName = "public"
+
+	apiKeyHeader     = "X-API-Key"
+	apiKeyQueryParam = "apikey"
+
+	errcodeInvalidRequest   = -32600
+	errcodeUnauthorized     = -32001
+	errcodeMethodNotAllowed = -32601
+)
+
+var errInvalidCredentials = errors.New("invalid credentials")
+
+type permissionCtxKey struct{}
+
+// accessRule is the set of the allowed namespaces, methods and method prefixes.
+type accessRule struct {
+	all        bool
+	namespaces map[string]struct{}
+	methods    map[string]struct{}
+	prefixes   []string
+}
+
+func newAccessRule(allowed []string) *accessRule {
+	rule := &accessRule{
+		namespaces: make(map[string]struct{}),
+		methods:    make(map[string]struct{}),
+	}
+	for _, item := range allowed {
+		switch {
+		case item == "*":
+			rule.all = true
+		case strings.HasSuffix(item, "*"):
+			rule.prefixes = append(rule.prefixes, strings.TrimSuffix(item, "*"))
+		case strings.Contains(item, "_"):
+			rule.methods[item] = struct{}{}
+		default:
+			rule.namespaces[item] = struct{}{}
+		}
+	}
+	return rule
+}
+
+func (r *accessRule) allowMethod(method string) bool {
+	if r.all {
+		return true
+	}
+	if _, ok := r.methods[method]; ok {
+		return true
+	}
+	if namespace, _, ok := strings.Cut(method, "_"); ok {
+		if _, ok = r.namespaces[namespace]; ok {
+			return true
+		}
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *accessRule) allowNamespace(namespace string) bool {
+	if r.all {
+		return true
+	}
+	_, ok := r.namespaces[namespace]
+	return ok
+}
+
+type permission struct {
+	name    string
+	access  *accessRule
+	limiter *ratelimiter.JRateLimiter
+}
+
+// authenticator resolves the permission of the request by the api key or the jwt,
+// the request without credentials gets the public permission.
+type authenticator struct {
+	public    *permission
+	byKey     map[string]*permission
+	byName    map[string]*permission
+	jwtSecret []byte
+}
+
+func newAuthenticator(config repo.JsonRPCAuth) (*authenticator, error) {
+	a := &authenticator{
+		public: &permission{
+			name:   publicPermissionName,
+			access: newAccessRule(config.PublicAllowed),
+		},
+		byKey:  make(map[string]*permission, len(config.APIKeys)),
+		byName: make(map[string]*permission, len(config.APIKeys)),
+	}
+	if config.JWTSecret != "" {
+		secret, err := hexutil.Decode(config.JWTSecret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt secret: %w", err)
+		}
+		a.jwtSecret = secret
+	}
+
+	for _, key := range config.APIKeys {
+		if key.Name == "" || key.Name == publicPermissionName {
+			return nil, fmt.Errorf("invalid api key name %q", key.Name)
+		}
+		if _, ok := a.byName[key.Name]; ok {
+			return nil, fmt.Errorf("duplicate api key name %s", key.Name)
+		}
+		p := &permission{
+			name:   key.Name,
+			access: newAccessRule(key.Allowed),
+		}
+		if key.Limiter.Enable {
+			limiter, err := ratelimiter.NewJRateLimiterWithQuantum(key.Limiter.Interval.ToDuration(), key.Limiter.Capacity, key.Limiter.Quantum)
+			if err != nil {
+				return nil, fmt.Errorf("create rate limiter of api key %s failed: %w", key.Name, err)
+			}
+			p.limiter = limiter
+		}
+		a.byName[key.Name] = p
+		// the api key is optional if the key is only used by jwt
+		if key.Key != "" {
+			if _, ok := a.byKey[key.Key]; ok {
+				return nil, fmt.Errorf("duplicate key of api key %s", key.Name)
+			}
+			a.byKey[key.Key] = p
+		}
+	}
+	return a, nil
+}
+
+func (a *authenticator) permissions() []*permission {
+	permissions := []*permission{a.public}
+	for _, p := range a.byName {
+		permissions = append(permissions, p)
+	}
+	return permissions
+}
+
+// checkWebsocket returns error if any permission can not be enforced on the websocket connections.
+// The websocket calls are served by the rpc server directly and can not be checked one by one,
+// only the namespaces are checked by registering the allowed ones, and no call is rate limited.
+func (a *authenticator) checkWebsocket() error {
+	for _, p := range a.permissions() {
+		if !p.access.all && (len(p.access.methods) > 0 || len(p.access.prefixes) > 0) {
+			return fmt.Errorf("method grants of %s can not be enforced on websocket", p.name)
+		}
+		if p.limiter != nil {
+			return fmt.Errorf("rate limiter of %s can not be enforced on websocket", p.name)
+		}
+	}
+	return nil
+}
+
+func (a *authenticator) authenticate(r *http.Request) (*permission, error) {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" {
+		key = r.URL.Query().Get(apiKeyQueryParam)
+	}
+	if key != "" {
+		p, ok := a.byKey[key]
+		if !ok {
+			return nil, errors.Wrap(errInvalidCredentials, "unknown api key")
+		}
+		return p, nil
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return a.authenticateJWT(token)
+	}
+	return a.public, nil
+}
+
+func (a *authenticator) authenticateJWT(token string) (*permission, error) {
+	if len(a.jwtSecret) == 0 {
+		return nil, errors.Wrap(errInvalidCredentials, "jwt is not enabled")
+	}
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		return a.jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, errors.Wrapf(errInvalidCredentials, "invalid jwt: %v", err)
+	}
+	// the token without expiration time would be valid forever
+	if claims.ExpiresAt == nil {
+		return nil, errors.Wrap(errInvalidCredentials, "invalid jwt: missing expiration time")
+	}
+	p, ok := a.byName[claims.Subject]
+	if !ok {
+		return nil, errors.Wrapf(errInvalidCredentials, "unknown jwt subject %s", claims.Subject)
+	}
+	return p, nil
+}
+
+func withPermission(ctx context.Context, p *permission) context.Context {
+	return context.WithValue(ctx, permissionCtxKey{}, p)
+}
+
+func permissionFromContext(ctx context.Context) *permission {
+	p, _ := ctx.Value(permissionCtxKey{}).(*permission)
+	return p
+}
+
+func (cbs *ChainBrokerService) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cbs.authenticator == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		p, err := cbs.authenticator.authenticate(r)
+		if err != nil {
+			writeJsonrpcError(w, http.StatusUnauthorized, nil, false, errcodeUnauthorized, err.Error(), nil)
+			return
+		}
+
+		// the request which can not be fully parsed is rejected, otherwise the calls which are
+		// not checked may be served by the rpc server
+		calls, isBatch, err := readJsonrpcCalls(r)
+		if err != nil {
+			if errors.Is(err, errInvalidJsonrpcRequest) {
+				writeJsonrpcError(w, http.StatusBadRequest, nil, false, errcodeInvalidRequest, err.Error(), nil)
+				return
+			}
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		for _, call := range calls {
+			if !p.access.allowMethod(call.Method) {
+				writeJsonrpcError(w, http.StatusForbidden, calls, isBatch, errcodeMethodNotAllowed,
+					fmt.Sprintf("method %s is not allowed for %s", call.Method, p.name), nil)
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(withPermission(r.Context(), p)))
+	})
+}
+
+// wsAuthHandler authenticates the websocket handshake, and serves the connection by the server
+// which only registers the namespaces allowed by the permission.
+func (cbs *ChainBrokerService) wsAuthHandler(handlers map[string]http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := cbs.authenticator.authenticate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		handlers[p.name].ServeHTTP(w, r)
+	})
+}
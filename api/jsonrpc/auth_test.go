@@ -0,0 +1,331 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+var testJWTSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func testAuthConfig() repo.JsonRPCAuth {
+	return repo.JsonRPCAuth{
+		Enable:        true,
+		PublicAllowed: []string{"eth", "net"},
+		JWTSecret:     hexutil.Encode(testJWTSecret),
+		APIKeys: []repo.APIKey{
+			{
+				Name:    "tracer",
+				Key:     "tracer-key",
+				Allowed: []string{"eth", "debug_trace*"},
+			},
+			{
+				Name:    "admin",
+				Allowed: []string{"*"},
+			},
+		},
+	}
+}
+
+func signTestJWT(t *testing.T, method jwt.SigningMethod, secret []byte, subject string) string {
+	token, err := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(secret)
+	require.Nil(t, err)
+	return token
+}
+
+func TestAccessRule(t *testing.T) {
+	rule := newAccessRule([]string{"eth", "debug_traceTransaction", "txpool_get*"})
+	assert.True(t, rule.allowMethod("eth_chainId"))
+	assert.True(t, rule.allowMethod("debug_traceTransaction"))
+	assert.True(t, rule.allowMethod("txpool_getMeta"))
+	assert.False(t, rule.allowMethod("debug_traceBlockByNumber"))
+	assert.False(t, rule.allowMethod("txpool_subscribe"))
+	assert.False(t, rule.allowMethod("ethx_chainId"))
+	assert.False(t, rule.allowMethod(""))
+	assert.True(t, rule.allowNamespace("eth"))
+	assert.False(t, rule.allowNamespace("debug"))
+	assert.False(t, rule.allowNamespace("txpool"))
+
+	rule = newAccessRule([]string{"*"})
+	assert.True(t, rule.allowMethod("admin_peers"))
+	assert.True(t, rule.allowNamespace("admin"))
+
+	rule = newAccessRule(nil)
+	assert.False(t, rule.allowMethod("eth_chainId"))
+	assert.False(t, rule.allowNamespace("eth"))
+}
+
+func TestNewAuthenticator(t *testing.T) {
+	a, err := newAuthenticator(testAuthConfig())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(a.permissions()))
+	assert.Equal(t, 1, len(a.byKey))
+
+	testcases := []struct {
+		name   string
+		config func(config *repo.JsonRPCAuth)
+	}{
+		{
+			name: "invalid jwt secret",
+			config: func(config *repo.JsonRPCAuth) {
+				config.JWTSecret = "secret"
+			},
+		},
+		{
+			name: "empty name",
+			config: func(config *repo.JsonRPCAuth) {
+				config.APIKeys[0].Name = ""
+			},
+		},
+		{
+			name: "public name",
+			config: func(config *repo.JsonRPCAuth) {
+				config.APIKeys[0].Name = publicPermissionName
+			},
+		},
+		{
+			name: "duplicate name",
+			config: func(config *repo.JsonRPCAuth) {
+				config.APIKeys[1].Name = config.APIKeys[0].Name
+			},
+		},
+		{
+			name: "duplicate key",
+			config: func(config *repo.JsonRPCAuth) {
+				config.APIKeys[1].Key = config.APIKeys[0].Key
+			},
+		},
+		{
+			name: "invalid limiter",
+			config: func(config *repo.JsonRPCAuth) {
+				config.APIKeys[0].Limiter = repo.JLimiter{Enable: true}
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := testAuthConfig()
+			tc.config(&config)
+			_, err := newAuthenticator(config)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestAuthenticatorCheckWebsocket(t *testing.T) {
+	a, err := newAuthenticator(testAuthConfig())
+	require.Nil(t, err)
+	assert.ErrorContains(t, a.checkWebsocket(), "method grants of tracer")
+
+	config := testAuthConfig()
+	config.APIKeys[0].Allowed = []string{"eth", "debug"}
+	a, err = newAuthenticator(config)
+	require.Nil(t, err)
+	assert.Nil(t, a.checkWebsocket())
+
+	config.PublicAllowed = []string{"eth_chainId"}
+	a, err = newAuthenticator(config)
+	require.Nil(t, err)
+	assert.ErrorContains(t, a.checkWebsocket(), "method grants of public")
+
+	// the method grants are useless if all methods are allowed
+	config.PublicAllowed = []string{"*", "eth_chainId"}
+	a, err = newAuthenticator(config)
+	require.Nil(t, err)
+	assert.Nil(t, a.checkWebsocket())
+
+	config.APIKeys[1].Limiter = repo.JLimiter{Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 1, Enable: true}
+	a, err = newAuthenticator(config)
+	require.Nil(t, err)
+	assert.ErrorContains(t, a.checkWebsocket(), "rate limiter of admin")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+		config.Auth = testAuthConfig()
+	})
+	var servedPermission string
+	handler := cbs.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		servedPermission = permissionFromContext(r.Context()).name
+		w.WriteHeader(http.StatusOK)
+	}))
+	// serve returns the status and the error response if the request is rejected
+	serve := func(body string, setRequest func(r *http.Request)) (int, *jsonrpcErrorResponse) {
+		servedPermission = ""
+		w := postJsonrpc(handler, body, setRequest)
+		if w.Code == http.StatusOK {
+			return w.Code, nil
+		}
+		return w.Code, decodeJsonrpcError(t, w.Body.String())
+	}
+	withAPIKey := func(key string) func(r *http.Request) {
+		return func(r *http.Request) {
+			r.Header.Set(apiKeyHeader, key)
+		}
+	}
+	withJWT := func(token string) func(r *http.Request) {
+		return func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	t.Run("public", func(t *testing.T) {
+		status, _ := serve(`{"id":1,"method":"eth_chainId"}`, nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, publicPermissionName, servedPermission)
+
+		status, resp := serve(`{"id":1,"method":"debug_traceTransaction"}`, nil)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Equal(t, errcodeMethodNotAllowed, resp.Error.Code)
+		assert.Equal(t, json.RawMessage("1"), resp.ID)
+		assert.Equal(t, "", servedPermission)
+
+		// any disallowed call rejects the whole batch
+		status, resp = serve(`[{"id":1,"method":"eth_chainId"},{"id":2,"method":"admin_peers"}]`, nil)
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Equal(t, json.RawMessage("null"), resp.ID)
+	})
+
+	t.Run("api key", func(t *testing.T) {
+		status, _ := serve(`{"id":1,"method":"debug_traceTransaction"}`, withAPIKey("tracer-key"))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "tracer", servedPermission)
+
+		status, _ = serve(`{"id":1,"method":"debug_traceCall"}`, func(r *http.Request) {
+			r.URL.RawQuery = apiKeyQueryParam + "=tracer-key"
+		})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "tracer", servedPermission)
+
+		status, resp := serve(`{"id":1,"method":"net_version"}`, withAPIKey("tracer-key"))
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusForbidden, status)
+
+		status, resp = serve(`{"id":1,"method":"eth_chainId"}`, withAPIKey("unknown-key"))
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Equal(t, errcodeUnauthorized, resp.Error.Code)
+	})
+
+	t.Run("jwt", func(t *testing.T) {
+		status, _ := serve(`{"id":1,"method":"admin_peers"}`, withJWT(signTestJWT(t, jwt.SigningMethodHS256, testJWTSecret, "admin")))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "admin", servedPermission)
+
+		invalidTokens := map[string]string{
+			"wrong secret":    signTestJWT(t, jwt.SigningMethodHS256, []byte("wrong secret"), "admin"),
+			"unknown subject": signTestJWT(t, jwt.SigningMethodHS256, testJWTSecret, "unknown"),
+			"public subject":  signTestJWT(t, jwt.SigningMethodHS256, testJWTSecret, publicPermissionName),
+			"wrong method":    signTestJWT(t, jwt.SigningMethodHS512, testJWTSecret, "admin"),
+			"malformed":       "token",
+		}
+		for name, token := range invalidTokens {
+			status, resp := serve(`{"id":1,"method":"eth_chainId"}`, withJWT(token))
+			require.NotNil(t, resp, name)
+			assert.Equal(t, http.StatusUnauthorized, status, name)
+		}
+
+		// the expired token is rejected
+		expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:   "admin",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		}).SignedString(testJWTSecret)
+		require.Nil(t, err)
+		status, resp := serve(`{"id":1,"method":"eth_chainId"}`, withJWT(expired))
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusUnauthorized, status)
+
+		// the token without expiration time is rejected
+		unexpired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject: "admin",
+		}).SignedString(testJWTSecret)
+		require.Nil(t, err)
+		status, resp = serve(`{"id":1,"method":"eth_chainId"}`, withJWT(unexpired))
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusUnauthorized, status)
+		assert.Contains(t, resp.Error.Message, "missing expiration time")
+	})
+
+	t.Run("jwt disabled", func(t *testing.T) {
+		cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+			config.Auth = testAuthConfig()
+			config.Auth.JWTSecret = ""
+		})
+		w := postJsonrpc(cbs.authMiddleware(okHandler), `{"id":1,"method":"eth_chainId"}`,
+			withJWT(signTestJWT(t, jwt.SigningMethodHS256, testJWTSecret, "admin")))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("unparsed request", func(t *testing.T) {
+		bodies := map[string]string{
+			"trailing request": `{"id":1,"method":"eth_chainId"}{"id":2,"method":"admin_peers"}`,
+			"trailing garbage": `{"id":1,"method":"admin_peers"} garbage`,
+			"non-object call":  `[{"id":1,"method":"admin_peers"},1]`,
+			"null call":        `[null,{"id":1,"method":"admin_peers"}]`,
+			"null request":     `null`,
+			"truncated":        `{"id":1,"method":"admin_peers"`,
+		}
+		for name, body := range bodies {
+			status, resp := serve(body, nil)
+			require.NotNil(t, resp, name)
+			assert.Equal(t, http.StatusBadRequest, status, name)
+			assert.Equal(t, errcodeInvalidRequest, resp.Error.Code, name)
+			assert.Equal(t, "", servedPermission, name)
+		}
+
+		// the empty body has no calls to check
+		status, _ := serve("", nil)
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("auth disabled", func(t *testing.T) {
+		cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {})
+		w := postJsonrpc(cbs.authMiddleware(okHandler), `{"id":1,"method":"admin_peers"} garbage`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestWsAuthHandler(t *testing.T) {
+	cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+		config.Auth = testAuthConfig()
+	})
+	var servedPermission string
+	handlers := make(map[string]http.Handler)
+	for _, p := range cbs.authenticator.permissions() {
+		name := p.name
+		handlers[name] = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			servedPermission = name
+		})
+	}
+	handler := cbs.wsAuthHandler(handlers)
+
+	w := postJsonrpc(handler, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, publicPermissionName, servedPermission)
+
+	w = postJsonrpc(handler, "", func(r *http.Request) {
+		r.Header.Set(apiKeyHeader, "tracer-key")
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "tracer", servedPermission)
+
+	servedPermission = ""
+	w = postJsonrpc(handler, "", func(r *http.Request) {
+		r.Header.Set(apiKeyHeader, "unknown-key")
+	})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "", servedPermission)
+}
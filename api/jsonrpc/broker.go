@@ -1,18 +1,17 @@
 package jsonrpc
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"sync"
 
 	_ "github.com/ethereum/go-ethereum/eth/tracers/js"
 	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 	"github.com/rs/cors"
 	"github.com/sirupsen/logrus"
 
@@ -28,11 +27,17 @@ type ChainBrokerService struct {
 	// genesis     *repo.Genesis
 	api api.CoreAPI
 
-	server              *rpc.Server
-	wsServer            *rpc.Server
-	logger              logrus.FieldLogger
-	rateLimiterForRead  *ratelimiter.JRateLimiter
-	rateLimiterForWrite *ratelimiter.JRateLimiter
+	server   *rpc.Server
+	wsServer *rpc.Server
+	// the websocket servers of each permission if the auth is enabled
+	wsAuthServers        map[string]*rpc.Server
+	authenticator        *authenticator
+	logger               logrus.FieldLogger
+	rateLimiterForRead   *ratelimiter.JRateLimiter
+	rateLimiterForWrite  *ratelimiter.JRateLimiter
+	rateLimiterForClient *ratelimiter.JKeyedRateLimiter
+	methodLimiters       []*methodLimiter
+	rateLimitLock        sync.Mutex
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -52,15 +57,50 @@ func NewChainBrokerService(coreAPI api.CoreAPI, rep *repo.Repo) (*ChainBrokerSer
 		return nil, fmt.Errorf("create write rate limiter failed: %w", err)
 	}
 
+	var clientLimiter *ratelimiter.JKeyedRateLimiter
+	if config.JsonRPC.ClientLimiter.Enable {
+		clientLimiter, err = ratelimiter.NewJKeyedRateLimiter(config.JsonRPC.ClientLimiter.Interval.ToDuration(), config.JsonRPC.ClientLimiter.Capacity, config.JsonRPC.ClientLimiter.Quantum, maxLimitedClients)
+		if err != nil {
+			return nil, fmt.Errorf("create client rate limiter failed: %w", err)
+		}
+	}
+
+	methodLimiters, err := newMethodLimiters(config.JsonRPC.MethodLimiters)
+	if err != nil {
+		return nil, err
+	}
+
+	var auth *authenticator
+	if config.JsonRPC.Auth.Enable {
+		auth, err = newAuthenticator(config.JsonRPC.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("create jsonrpc authenticator failed: %w", err)
+		}
+	}
+
+	// the websocket calls are served by the rpc server directly, so the limiters and the grants
+	// which check the calls one by one can not be enforced on them
+	if config.Port.WebSocket != 0 {
+		if err = checkWebsocketLimiters(config.JsonRPC); err == nil && auth != nil {
+			err = auth.checkWebsocket()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w, set port.websocket to 0 to disable websocket", err)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	cbs := &ChainBrokerService{
-		logger:              logger,
-		rep:                 rep,
-		api:                 coreAPI,
-		ctx:                 ctx,
-		cancel:              cancel,
-		rateLimiterForRead:  readLimiter,
-		rateLimiterForWrite: writeLimiter,
+		logger:               logger,
+		rep:                  rep,
+		api:                  coreAPI,
+		ctx:                  ctx,
+		cancel:               cancel,
+		rateLimiterForRead:   readLimiter,
+		rateLimiterForWrite:  writeLimiter,
+		rateLimiterForClient: clientLimiter,
+		methodLimiters:       methodLimiters,
+		authenticator:        auth,
 	}
 
 	if err := cbs.init(); err != nil {
@@ -68,9 +108,12 @@ func NewChainBrokerService(coreAPI api.CoreAPI, rep *repo.Repo) (*ChainBrokerSer
 		return nil, fmt.Errorf("init chain broker service failed: %w", err)
 	}
 
-	if err := cbs.initWS(); err != nil {
-		cancel()
-		return nil, fmt.Errorf("init chain broker websocket service failed: %w", err)
+	// zero websocket port disables the websocket service
+	if config.Port.WebSocket != 0 {
+		if err := cbs.initWS(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("init chain broker websocket service failed: %w", err)
+		}
 	}
 
 	return cbs, nil
@@ -78,6 +121,7 @@ func NewChainBrokerService(coreAPI api.CoreAPI, rep *repo.Repo) (*ChainBrokerSer
 
 func (cbs *ChainBrokerService) init() error {
 	cbs.server = rpc.NewServer()
+	cbs.server.SetBatchLimits(cbs.rep.Config.JsonRPC.BatchRequestLimit, cbs.rep.Config.JsonRPC.BatchResponseMaxSize)
 
 	apis, err := GetAPIs(cbs.rep, cbs.api, cbs.logger)
 	if err != nil {
@@ -95,12 +139,16 @@ func (cbs *ChainBrokerService) init() error {
 }
 
 func (cbs *ChainBrokerService) initWS() error {
-	cbs.wsServer = rpc.NewServer()
-
 	apis, err := GetAPIs(cbs.rep, cbs.api, cbs.logger)
 	if err != nil {
 		return fmt.Errorf("get apis failed: %w", err)
 	}
+	return cbs.registerWSAPIs(apis)
+}
+
+func (cbs *ChainBrokerService) registerWSAPIs(apis []rpc.API) error {
+	cbs.wsServer = rpc.NewServer()
+	cbs.wsServer.SetBatchLimits(cbs.rep.Config.JsonRPC.BatchRequestLimit, cbs.rep.Config.JsonRPC.BatchResponseMaxSize)
 
 	// Register all the APIs exposed by the namespace services
 	for _, api := range apis {
@@ -109,18 +157,35 @@ func (cbs *ChainBrokerService) initWS() error {
 		}
 	}
 
+	if cbs.authenticator == nil {
+		return nil
+	}
+	// the websocket calls can not be checked one by one, so each permission uses the server
+	// which only registers the allowed namespaces, the permissions which need the calls to be
+	// checked are rejected by checkWebsocket
+	cbs.wsAuthServers = make(map[string]*rpc.Server)
+	for _, p := range cbs.authenticator.permissions() {
+		server := rpc.NewServer()
+		server.SetBatchLimits(cbs.rep.Config.JsonRPC.BatchRequestLimit, cbs.rep.Config.JsonRPC.BatchResponseMaxSize)
+		for _, api := range apis {
+			if !p.access.allowNamespace(api.Namespace) {
+				continue
+			}
+			if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+				return fmt.Errorf("register name %s for service %v failed: %w", api.Namespace, api.Service, err)
+			}
+		}
+		cbs.wsAuthServers[p.name] = server
+	}
+
 	return nil
 }
 
 func (cbs *ChainBrokerService) Start() error {
 	router := mux.NewRouter()
-	handler := cbs.tokenBucketMiddleware(cbs.server)
+	handler := cbs.authMiddleware(cbs.tokenBucketMiddleware(cbs.responseSizeMiddleware(cbs.server)))
 	router.Handle("/", handler)
 
-	wsRouter := mux.NewRouter()
-	wsHandler := node.NewWSHandlerStack(cbs.wsServer.WebsocketHandler([]string{"*"}), []byte(""))
-	wsRouter.Handle("/", wsHandler)
-
 	go func() {
 		cbs.logger.WithFields(logrus.Fields{
 			"port": cbs.rep.Config.Port.JsonRpc,
@@ -134,6 +199,14 @@ func (cbs *ChainBrokerService) Start() error {
 		}
 	}()
 
+	if cbs.wsServer == nil {
+		cbs.logger.Info("Websocket service is disabled")
+		return nil
+	}
+
+	wsRouter := mux.NewRouter()
+	wsRouter.Handle("/", cbs.websocketHandler())
+
 	go func() {
 		cbs.logger.WithFields(logrus.Fields{
 			"port": cbs.rep.Config.Port.WebSocket,
@@ -150,6 +223,17 @@ func (cbs *ChainBrokerService) Start() error {
 	return nil
 }
 
+func (cbs *ChainBrokerService) websocketHandler() http.Handler {
+	if cbs.authenticator == nil {
+		return node.NewWSHandlerStack(cbs.wsServer.WebsocketHandler([]string{"*"}), []byte(""))
+	}
+	handlers := make(map[string]http.Handler, len(cbs.wsAuthServers))
+	for name, server := range cbs.wsAuthServers {
+		handlers[name] = node.NewWSHandlerStack(server.WebsocketHandler([]string{"*"}), []byte(""))
+	}
+	return cbs.wsAuthHandler(handlers)
+}
+
 func (cbs *ChainBrokerService) Stop() error {
 	cbs.cancel()
 
@@ -162,66 +246,29 @@ func (cbs *ChainBrokerService) Stop() error {
 
 func (cbs *ChainBrokerService) tokenBucketMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		readJsonRpc := cbs.rep.Config.JsonRPC.ReadLimiter.Enable
-		writeJsonRpc := cbs.rep.Config.JsonRPC.WriteLimiter.Enable
-
-		if !readJsonRpc && !writeJsonRpc {
+		jsonRPCConfig := cbs.rep.Config.JsonRPC
+		p := permissionFromContext(r.Context())
+		if !jsonRPCConfig.ReadLimiter.Enable && !jsonRPCConfig.WriteLimiter.Enable && cbs.rateLimiterForClient == nil && len(cbs.methodLimiters) == 0 &&
+			(p == nil || p.limiter == nil) {
 			next.ServeHTTP(w, r)
-		} else {
-			requestBody, err := io.ReadAll(r.Body)
-			if err != nil {
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
-			// Restore the r.Body with the captured content.
-			r.Body = io.NopCloser(bytes.NewReader(requestBody))
-
-			newRequest, err := http.NewRequest(r.Method, r.URL.String(), io.NopCloser(bytes.NewReader(requestBody)))
-			if err != nil {
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
-			newRequest.Header = r.Header.Clone()
-
-			var request any
-			var isJson = true
-			if err := json.NewDecoder(io.NopCloser(bytes.NewReader(requestBody))).Decode(&request); err != nil {
-				isJson = false
-				cbs.logger.Error("tokenBucketMiddleware JSON decode error: ", err)
-			}
+			return
+		}
 
-			var rateLimiter *ratelimiter.JRateLimiter
-			if isJson {
-				switch req := request.(type) {
-				case []any:
-					for _, req := range req {
-						if reqMap, ok := req.(map[string]any); ok {
-							method, ok := reqMap["method"].(string)
-							if ok {
-								cbs.logger.Info("request method: ", method)
-								if method == "eth_sendRawTransaction" {
-									if writeJsonRpc {
-										rateLimiter = cbs.rateLimiterForWrite
-									}
-									cbs.logger.Info("tokenBucketMiddleware rateLimiter is rateLimiterForWrite writeJsonRpc:", writeJsonRpc)
-								} else if readJsonRpc {
-									rateLimiter = cbs.rateLimiterForRead
-								}
-							}
-						}
-					}
-				default:
-					if readJsonRpc {
-						rateLimiter = cbs.rateLimiterForRead
-					}
-				}
-			}
+		// the invalid request is rejected by the rpc server
+		calls, isBatch, err := readJsonrpcCalls(r)
+		if err != nil && !errors.Is(err, errInvalidJsonrpcRequest) {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
 
-			if rateLimiter != nil && rateLimiter.JLimit() {
-				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
-				return
-			}
-			next.ServeHTTP(w, newRequest)
+		if reason, retryAfter := cbs.checkRateLimit(getClientIP(r), p, calls); reason != "" {
+			cbs.logger.WithFields(logrus.Fields{
+				"reason":      reason,
+				"retry_after": retryAfter,
+			}).Debug("Rate limit exceeded")
+			writeRateLimitError(w, calls, isBatch, reason, retryAfter)
+			return
 		}
+		next.ServeHTTP(w, r)
 	})
 }
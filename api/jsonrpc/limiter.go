@@ -0,0 +1,319 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/axiomesh/axiom-ledger/pkg/ratelimiter"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+const (
+	// the max number of the client ips whose buckets are kept
+	maxLimitedClients = 10000
+
+	// the error code of the limit exceeded, which is also used by the other ethereum clients
+	errcodeLimitExceeded = -32005
+
+	// the error code of the oversized response, same as the batch response of the rpc server
+	errcodeResponseTooLarge = -32003
+)
+
+var (
+	errInvalidJsonrpcRequest = errors.New("invalid JSON-RPC request")
+	errResponseTooLarge      = errors.New("response too large")
+)
+
+type methodLimiter struct {
+	method string
+	prefix bool
+
+	// only one of them is set
+	limiter       *ratelimiter.JRateLimiter
+	clientLimiter *ratelimiter.JKeyedRateLimiter
+}
+
+func newMethodLimiters(configs []repo.JMethodLimiter) ([]*methodLimiter, error) {
+	limiters := make([]*methodLimiter, 0, len(configs))
+	for _, config := range configs {
+		if config.Method == "" || config.Method == "*" {
+			return nil, fmt.Errorf("invalid method %q of method limiter", config.Method)
+		}
+		l := &methodLimiter{
+			method: strings.TrimSuffix(config.Method, "*"),
+			prefix: strings.HasSuffix(config.Method, "*"),
+		}
+		var err error
+		if config.PerClient {
+			l.clientLimiter, err = ratelimiter.NewJKeyedRateLimiter(config.Interval.ToDuration(), config.Capacity, config.Quantum, maxLimitedClients)
+		} else {
+			l.limiter, err = ratelimiter.NewJRateLimiterWithQuantum(config.Interval.ToDuration(), config.Capacity, config.Quantum)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("create rate limiter of method %s failed: %w", config.Method, err)
+		}
+		limiters = append(limiters, l)
+	}
+	return limiters, nil
+}
+
+// checkWebsocketLimiters returns error if any limiter which takes the tokens of each call is enabled,
+// the websocket calls can not be limited one by one.
+func checkWebsocketLimiters(config repo.JsonRPC) error {
+	if config.ClientLimiter.Enable {
+		return errors.New("client limiter can not be enforced on websocket")
+	}
+	if len(config.MethodLimiters) > 0 {
+		return errors.New("method limiters can not be enforced on websocket")
+	}
+	return nil
+}
+
+func (l *methodLimiter) match(method string) bool {
+	if l.prefix {
+		return strings.HasPrefix(method, l.method)
+	}
+	return method == l.method
+}
+
+type jsonrpcCall struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+}
+
+// parseJsonrpcCalls returns the calls of the single or batch request, the body must be
+// exactly one JSON-RPC request object or an array of them.
+func parseJsonrpcCalls(body []byte) ([]*jsonrpcCall, bool, error) {
+	body = bytes.TrimLeft(body, " \t\r\n")
+	if len(body) > 0 && body[0] == '[' {
+		var calls []*jsonrpcCall
+		if err := json.Unmarshal(body, &calls); err != nil {
+			return nil, true, err
+		}
+		for i, call := range calls {
+			if call == nil {
+				return nil, true, fmt.Errorf("call %d of the batch is not an object", i)
+			}
+		}
+		return calls, true, nil
+	}
+	if len(body) == 0 || body[0] != '{' {
+		return nil, false, errors.New("request is not an object")
+	}
+	call := &jsonrpcCall{}
+	if err := json.Unmarshal(body, call); err != nil {
+		return nil, false, err
+	}
+	return []*jsonrpcCall{call}, false, nil
+}
+
+// readJsonrpcCalls reads the calls of the request and restores the request body, the body
+// which is not a valid JSON-RPC request returns the error wrapping errInvalidJsonrpcRequest,
+// and the empty body returns no calls.
+func readJsonrpcCalls(r *http.Request) ([]*jsonrpcCall, bool, error) {
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	// Restore the r.Body with the captured content.
+	r.Body = io.NopCloser(bytes.NewReader(requestBody))
+
+	if len(bytes.TrimSpace(requestBody)) == 0 {
+		return nil, false, nil
+	}
+	calls, isBatch, err := parseJsonrpcCalls(requestBody)
+	if err != nil {
+		return nil, false, errors.Wrap(errInvalidJsonrpcRequest, err.Error())
+	}
+	return calls, isBatch, nil
+}
+
+func getClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// tokenDemand is the number of tokens which a request takes from the limiter.
+type tokenDemand struct {
+	limiter *ratelimiter.JRateLimiter
+	count   int64
+	reason  string
+}
+
+func addTokenDemand(demands []*tokenDemand, limiter *ratelimiter.JRateLimiter, reason string) []*tokenDemand {
+	for _, d := range demands {
+		if d.limiter == limiter {
+			d.count++
+			return demands
+		}
+	}
+	return append(demands, &tokenDemand{limiter: limiter, count: 1, reason: reason})
+}
+
+// checkRateLimit takes the tokens of the calls, returns the limited reason and the retry after hint
+// if any of the limiters is exceeded. All the limiters are checked before any token is taken,
+// so the rejected request does not consume the tokens of the other limiters.
+func (cbs *ChainBrokerService) checkRateLimit(clientIP string, p *permission, calls []*jsonrpcCall) (string, time.Duration) {
+	var demands []*tokenDemand
+	jsonRPCConfig := cbs.rep.Config.JsonRPC
+	if jsonRPCConfig.ReadLimiter.Enable || jsonRPCConfig.WriteLimiter.Enable {
+		// the whole request takes one token, the request with any write call uses the write limiter
+		var isWrite bool
+		for _, call := range calls {
+			if call.Method == "eth_sendRawTransaction" {
+				isWrite = true
+				break
+			}
+		}
+		if isWrite && jsonRPCConfig.WriteLimiter.Enable {
+			demands = addTokenDemand(demands, cbs.rateLimiterForWrite, "write")
+		} else if !isWrite && jsonRPCConfig.ReadLimiter.Enable {
+			demands = addTokenDemand(demands, cbs.rateLimiterForRead, "read")
+		}
+	}
+
+	for _, call := range calls {
+		if p != nil && p.limiter != nil {
+			demands = addTokenDemand(demands, p.limiter, "api key "+p.name)
+		}
+		if cbs.rateLimiterForClient != nil {
+			demands = addTokenDemand(demands, cbs.rateLimiterForClient.Limiter(clientIP), "client "+clientIP)
+		}
+		for _, l := range cbs.methodLimiters {
+			if !l.match(call.Method) {
+				continue
+			}
+			if l.clientLimiter != nil {
+				demands = addTokenDemand(demands, l.clientLimiter.Limiter(clientIP), "method "+call.Method)
+			} else {
+				demands = addTokenDemand(demands, l.limiter, "method "+call.Method)
+			}
+			// only the first matched limiter is used
+			break
+		}
+	}
+
+	// the check and the take are serialized, otherwise the concurrent requests may take the tokens
+	// between them
+	cbs.rateLimitLock.Lock()
+	defer cbs.rateLimitLock.Unlock()
+	for _, d := range demands {
+		if limited, retryAfter := d.limiter.JCheckWithRetryAfter(d.count); limited {
+			return d.reason, retryAfter
+		}
+	}
+	for _, d := range demands {
+		d.limiter.TakeAvailable(d.count)
+	}
+	return "", 0
+}
+
+// writeRateLimitError responds the 429 status with the Retry-After header, and the JSON-RPC error
+// whose data carries the retry after hint in seconds.
+func writeRateLimitError(w http.ResponseWriter, calls []*jsonrpcCall, isBatch bool, reason string, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	writeJsonrpcError(w, http.StatusTooManyRequests, calls, isBatch, errcodeLimitExceeded,
+		fmt.Sprintf("rate limit exceeded: %s, retry after %ds", reason, seconds), map[string]any{"retryAfter": seconds})
+}
+
+// writeJsonrpcError responds the JSON-RPC error with the id of the single request, or null for the batch request.
+func writeJsonrpcError(w http.ResponseWriter, status int, calls []*jsonrpcCall, isBatch bool, code int, message string, data any) {
+	id := json.RawMessage("null")
+	if !isBatch && len(calls) == 1 && len(calls[0].ID) > 0 {
+		id = calls[0].ID
+	}
+	rpcErr := map[string]any{
+		"code":    code,
+		"message": message,
+	}
+	if data != nil {
+		rpcErr["data"] = data
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error":   rpcErr,
+	})
+}
+
+// responseSizeMiddleware limits the response size of the single request, the batch response is
+// limited by the rpc server, and the oversized response is replaced by the JSON-RPC error.
+func (cbs *ChainBrokerService) responseSizeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		maxSize := cbs.rep.Config.JsonRPC.BatchResponseMaxSize
+		if maxSize == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestBody, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(requestBody))
+		if trimmed := bytes.TrimLeft(requestBody, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lw := &limitedResponseWriter{ResponseWriter: w, maxSize: maxSize}
+		next.ServeHTTP(lw, r)
+		if lw.exceeded {
+			// the request is parsed only to respond the id
+			calls, _, _ := parseJsonrpcCalls(requestBody)
+			writeJsonrpcError(w, http.StatusOK, calls, false, errcodeResponseTooLarge, errResponseTooLarge.Error(), nil)
+			return
+		}
+		if lw.status != 0 {
+			w.WriteHeader(lw.status)
+		}
+		_, _ = w.Write(lw.buf.Bytes())
+	})
+}
+
+// limitedResponseWriter buffers the response until it exceeds the max size.
+type limitedResponseWriter struct {
+	http.ResponseWriter
+	maxSize  int
+	status   int
+	buf      bytes.Buffer
+	exceeded bool
+}
+
+func (w *limitedResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *limitedResponseWriter) Write(p []byte) (int, error) {
+	if w.exceeded {
+		return 0, errResponseTooLarge
+	}
+	if w.buf.Len()+len(p) > w.maxSize {
+		w.exceeded = true
+		w.buf = bytes.Buffer{}
+		return 0, errResponseTooLarge
+	}
+	return w.buf.Write(p)
+}
@@ -0,0 +1,321 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-ledger/pkg/loggers"
+	"github.com/axiomesh/axiom-ledger/pkg/ratelimiter"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+// newTestBrokerService creates the service with only the limiters and the authenticator,
+// which is enough for the middlewares.
+func newTestBrokerService(t *testing.T, setConfig func(config *repo.JsonRPC)) *ChainBrokerService {
+	config := repo.DefaultConfig()
+	config.JsonRPC.ReadLimiter.Enable = false
+	config.JsonRPC.WriteLimiter.Enable = false
+	config.JsonRPC.ClientLimiter.Enable = false
+	setConfig(&config.JsonRPC)
+	jsonRPCConfig := config.JsonRPC
+
+	readLimiter, err := ratelimiter.NewJRateLimiterWithQuantum(jsonRPCConfig.ReadLimiter.Interval.ToDuration(), jsonRPCConfig.ReadLimiter.Capacity, jsonRPCConfig.ReadLimiter.Quantum)
+	require.Nil(t, err)
+	writeLimiter, err := ratelimiter.NewJRateLimiterWithQuantum(jsonRPCConfig.WriteLimiter.Interval.ToDuration(), jsonRPCConfig.WriteLimiter.Capacity, jsonRPCConfig.WriteLimiter.Quantum)
+	require.Nil(t, err)
+	var clientLimiter *ratelimiter.JKeyedRateLimiter
+	if jsonRPCConfig.ClientLimiter.Enable {
+		clientLimiter, err = ratelimiter.NewJKeyedRateLimiter(jsonRPCConfig.ClientLimiter.Interval.ToDuration(), jsonRPCConfig.ClientLimiter.Capacity, jsonRPCConfig.ClientLimiter.Quantum, maxLimitedClients)
+		require.Nil(t, err)
+	}
+	methodLimiters, err := newMethodLimiters(jsonRPCConfig.MethodLimiters)
+	require.Nil(t, err)
+	var auth *authenticator
+	if jsonRPCConfig.Auth.Enable {
+		auth, err = newAuthenticator(jsonRPCConfig.Auth)
+		require.Nil(t, err)
+	}
+
+	return &ChainBrokerService{
+		rep:                  &repo.Repo{Config: config},
+		logger:               loggers.Logger(loggers.API),
+		rateLimiterForRead:   readLimiter,
+		rateLimiterForWrite:  writeLimiter,
+		rateLimiterForClient: clientLimiter,
+		methodLimiters:       methodLimiters,
+		authenticator:        auth,
+	}
+}
+
+type jsonrpcErrorResponse struct {
+	ID    json.RawMessage `json:"id"`
+	Error struct {
+		Code    int            `json:"code"`
+		Message string         `json:"message"`
+		Data    map[string]any `json:"data"`
+	} `json:"error"`
+}
+
+func decodeJsonrpcError(t *testing.T, body string) *jsonrpcErrorResponse {
+	resp := &jsonrpcErrorResponse{}
+	require.Nil(t, json.Unmarshal([]byte(body), resp))
+	return resp
+}
+
+// okHandler responds 200 if the request passes the middlewares.
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func postJsonrpc(handler http.Handler, body string, setRequest func(r *http.Request)) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.RemoteAddr = "10.0.0.1:12345"
+	if setRequest != nil {
+		setRequest(r)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestParseJsonrpcCalls(t *testing.T) {
+	calls, isBatch, err := parseJsonrpcCalls([]byte(` {"jsonrpc":"2.0","id":1,"method":"eth_call"}`))
+	assert.Nil(t, err)
+	assert.False(t, isBatch)
+	assert.Equal(t, 1, len(calls))
+	assert.Equal(t, "eth_call", calls[0].Method)
+	assert.Equal(t, json.RawMessage("1"), calls[0].ID)
+
+	calls, isBatch, err = parseJsonrpcCalls([]byte(`[{"id":1,"method":"eth_call"},{"id":2,"method":"eth_chainId"}]`))
+	assert.Nil(t, err)
+	assert.True(t, isBatch)
+	assert.Equal(t, 2, len(calls))
+	assert.Equal(t, "eth_chainId", calls[1].Method)
+
+	_, _, err = parseJsonrpcCalls([]byte(`{"method":`))
+	assert.NotNil(t, err)
+	_, _, err = parseJsonrpcCalls([]byte(`[{"method":"eth_call"},1]`))
+	assert.NotNil(t, err)
+}
+
+func TestTokenBucketMiddleware(t *testing.T) {
+	t.Run("read limiter", func(t *testing.T) {
+		cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+			config.ReadLimiter = repo.JLimiter{Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 1, Enable: true}
+		})
+		handler := cbs.tokenBucketMiddleware(okHandler)
+
+		w := postJsonrpc(handler, `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = postJsonrpc(handler, `{"jsonrpc":"2.0","id":2,"method":"eth_chainId"}`, nil)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "3600", w.Header().Get("Retry-After"))
+		resp := decodeJsonrpcError(t, w.Body.String())
+		assert.Equal(t, json.RawMessage("2"), resp.ID)
+		assert.Equal(t, errcodeLimitExceeded, resp.Error.Code)
+		assert.Contains(t, resp.Error.Message, "read")
+
+		// the write limiter is not enabled
+		w = postJsonrpc(handler, `{"jsonrpc":"2.0","id":3,"method":"eth_sendRawTransaction"}`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("client limiter", func(t *testing.T) {
+		cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+			config.ClientLimiter = repo.JLimiter{Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 2, Enable: true}
+		})
+		handler := cbs.tokenBucketMiddleware(okHandler)
+
+		// each call of the batch takes one token
+		w := postJsonrpc(handler, `[{"id":1,"method":"eth_chainId"},{"id":2,"method":"eth_chainId"}]`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = postJsonrpc(handler, `[{"id":3,"method":"eth_chainId"}]`, nil)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		resp := decodeJsonrpcError(t, w.Body.String())
+		assert.Equal(t, json.RawMessage("null"), resp.ID)
+		assert.Contains(t, resp.Error.Message, "client 10.0.0.1")
+
+		// the other clients have their own buckets
+		w = postJsonrpc(handler, `{"id":4,"method":"eth_chainId"}`, func(r *http.Request) {
+			r.RemoteAddr = "10.0.0.2:12345"
+		})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("method limiter", func(t *testing.T) {
+		cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+			config.MethodLimiters = []repo.JMethodLimiter{
+				{Method: "debug_trace*", Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 1},
+				{Method: "debug_traceTransaction", Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 100},
+			}
+		})
+		handler := cbs.tokenBucketMiddleware(okHandler)
+
+		w := postJsonrpc(handler, `{"id":1,"method":"debug_traceTransaction"}`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		// only the first matched limiter is used
+		w = postJsonrpc(handler, `{"id":2,"method":"debug_traceBlockByNumber"}`, nil)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, decodeJsonrpcError(t, w.Body.String()).Error.Message, "method debug_traceBlockByNumber")
+		// the other methods are not limited
+		w = postJsonrpc(handler, `{"id":3,"method":"eth_chainId"}`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejected request does not take tokens", func(t *testing.T) {
+		cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+			config.ReadLimiter = repo.JLimiter{Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 1, Enable: true}
+			config.ClientLimiter = repo.JLimiter{Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 3, Enable: true}
+			config.MethodLimiters = []repo.JMethodLimiter{
+				{Method: "eth_call", Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 1},
+			}
+		})
+		handler := cbs.tokenBucketMiddleware(okHandler)
+
+		// the method limiter rejects the second eth_call after the read and client limiters are checked
+		w := postJsonrpc(handler, `[{"id":1,"method":"eth_call"},{"id":2,"method":"eth_call"}]`, nil)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, decodeJsonrpcError(t, w.Body.String()).Error.Message, "method eth_call")
+		assert.EqualValues(t, 1, cbs.rateLimiterForRead.Available())
+		assert.EqualValues(t, 3, cbs.rateLimiterForClient.Limiter("10.0.0.1").Available())
+		assert.EqualValues(t, 1, cbs.methodLimiters[0].limiter.Available())
+
+		w = postJsonrpc(handler, `{"id":3,"method":"eth_call"}`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.EqualValues(t, 0, cbs.rateLimiterForRead.Available())
+		assert.EqualValues(t, 2, cbs.rateLimiterForClient.Limiter("10.0.0.1").Available())
+		assert.EqualValues(t, 0, cbs.methodLimiters[0].limiter.Available())
+	})
+
+	t.Run("no limiter", func(t *testing.T) {
+		cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {})
+		handler := cbs.tokenBucketMiddleware(okHandler)
+		for i := 0; i < 3; i++ {
+			w := postJsonrpc(handler, `{"id":1,"method":"eth_chainId"}`, nil)
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+	})
+}
+
+func TestCheckWebsocketLimiters(t *testing.T) {
+	config := repo.DefaultConfig().JsonRPC
+	assert.Nil(t, checkWebsocketLimiters(config))
+
+	config.ClientLimiter.Enable = true
+	assert.ErrorContains(t, checkWebsocketLimiters(config), "client limiter")
+
+	config.ClientLimiter.Enable = false
+	config.MethodLimiters = []repo.JMethodLimiter{
+		{Method: "eth_call", Interval: repo.Duration(time.Hour), Quantum: 1, Capacity: 1},
+	}
+	assert.ErrorContains(t, checkWebsocketLimiters(config), "method limiters")
+}
+
+func TestNewChainBrokerServiceWithWebsocket(t *testing.T) {
+	config := repo.DefaultConfig()
+	config.JsonRPC.ClientLimiter.Enable = true
+	_, err := NewChainBrokerService(nil, &repo.Repo{Config: config})
+	assert.ErrorContains(t, err, "client limiter can not be enforced on websocket")
+
+	config = repo.DefaultConfig()
+	config.JsonRPC.Auth = testAuthConfig()
+	_, err = NewChainBrokerService(nil, &repo.Repo{Config: config})
+	assert.ErrorContains(t, err, "method grants of tracer can not be enforced on websocket")
+}
+
+func TestResponseSizeMiddleware(t *testing.T) {
+	cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+		config.BatchResponseMaxSize = 16
+	})
+	// the handler responds the result of the size in the method name
+	handler := cbs.responseSizeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls, _, err := readJsonrpcCalls(r)
+		require.Nil(t, err)
+		size, err := strconv.Atoi(strings.TrimPrefix(calls[0].Method, "test_"))
+		require.Nil(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		for i := 0; i < size; i++ {
+			_, _ = w.Write([]byte("a"))
+		}
+	}))
+
+	w := postJsonrpc(handler, `{"id":1,"method":"test_16"}`, nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, strings.Repeat("a", 16), w.Body.String())
+
+	w = postJsonrpc(handler, `{"id":2,"method":"test_17"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	resp := decodeJsonrpcError(t, w.Body.String())
+	assert.Equal(t, json.RawMessage("2"), resp.ID)
+	assert.Equal(t, errcodeResponseTooLarge, resp.Error.Code)
+
+	// the batch response is limited by the rpc server
+	w = postJsonrpc(handler, ` [{"id":3,"method":"test_17"}]`, nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, strings.Repeat("a", 17), w.Body.String())
+
+	// zero means no limit
+	cbs.rep.Config.JsonRPC.BatchResponseMaxSize = 0
+	w = postJsonrpc(handler, `{"id":4,"method":"test_17"}`, nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, strings.Repeat("a", 17), w.Body.String())
+}
+
+func TestWriteRateLimitError(t *testing.T) {
+	testcases := []struct {
+		name       string
+		calls      []*jsonrpcCall
+		isBatch    bool
+		retryAfter time.Duration
+		expectID   json.RawMessage
+		expectWait int64
+	}{
+		{
+			name:       "single request",
+			calls:      []*jsonrpcCall{{ID: json.RawMessage(`"a"`), Method: "eth_call"}},
+			retryAfter: 1500 * time.Millisecond,
+			expectID:   json.RawMessage(`"a"`),
+			expectWait: 2,
+		},
+		{
+			name:       "batch request",
+			calls:      []*jsonrpcCall{{ID: json.RawMessage("1"), Method: "eth_call"}},
+			isBatch:    true,
+			retryAfter: 3 * time.Second,
+			expectID:   json.RawMessage("null"),
+			expectWait: 3,
+		},
+		{
+			name:       "unparsed request",
+			retryAfter: 50 * time.Millisecond,
+			expectID:   json.RawMessage("null"),
+			expectWait: 1,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writeRateLimitError(w, tc.calls, tc.isBatch, "read", tc.retryAfter)
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, strconv.FormatInt(tc.expectWait, 10), w.Header().Get("Retry-After"))
+
+			resp := decodeJsonrpcError(t, w.Body.String())
+			assert.Equal(t, tc.expectID, resp.ID)
+			assert.Equal(t, errcodeLimitExceeded, resp.Error.Code)
+			assert.Equal(t, fmt.Sprintf("rate limit exceeded: read, retry after %ds", tc.expectWait), resp.Error.Message)
+			assert.EqualValues(t, tc.expectWait, resp.Error.Data["retryAfter"])
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package anvil
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+// AnvilAPI is the anvil_ prefixed set of APIs which write the state and impersonate the accounts
+// in the dev mode, it is compatible with the same APIs of Anvil.
+type AnvilAPI struct {
+	rep    *repo.Repo
+	api    api.CoreAPI
+	logger logrus.FieldLogger
+}
+
+func NewAnvilAPI(rep *repo.Repo, api api.CoreAPI, logger logrus.FieldLogger) *AnvilAPI {
+	return &AnvilAPI{rep: rep, api: api, logger: logger}
+}
+
+// SetBalance sets the balance of the account.
+func (api *AnvilAPI) SetBalance(address common.Address, balance hexutil.Big) error {
+	api.logger.Debugf("anvil_setBalance, address: %s, balance: %s", address, balance.String())
+
+	return api.api.Dev().SetBalance(types.NewAddress(address.Bytes()), balance.ToInt())
+}
+
+// SetCode sets the code of the account.
+func (api *AnvilAPI) SetCode(address common.Address, code hexutil.Bytes) error {
+	api.logger.Debugf("anvil_setCode, address: %s, code size: %d", address, len(code))
+
+	return api.api.Dev().SetCode(types.NewAddress(address.Bytes()), code)
+}
+
+// SetStorageAt sets the value of the storage slot of the account, returns true if the value is written.
+func (api *AnvilAPI) SetStorageAt(address common.Address, slot common.Hash, value common.Hash) (bool, error) {
+	api.logger.Debugf("anvil_setStorageAt, address: %s, slot: %s, value: %s", address, slot, value)
+
+	if err := api.api.Dev().SetStorageAt(types.NewAddress(address.Bytes()), slot.Bytes(), value.Bytes()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetNonce sets the nonce of the account.
+func (api *AnvilAPI) SetNonce(address common.Address, nonce hexutil.Uint64) error {
+	api.logger.Debugf("anvil_setNonce, address: %s, nonce: %d", address, nonce)
+
+	return api.api.Dev().SetNonce(types.NewAddress(address.Bytes()), uint64(nonce))
+}
+
+// ImpersonateAccount accepts the txs sent by eth_sendTransaction from the account without the signature.
+func (api *AnvilAPI) ImpersonateAccount(address common.Address) error {
+	api.logger.Debugf("anvil_impersonateAccount, address: %s", address)
+
+	return api.api.Dev().ImpersonateAccount(types.NewAddress(address.Bytes()))
+}
+
+// StopImpersonatingAccount stops accepting the unsigned txs from the account.
+func (api *AnvilAPI) StopImpersonatingAccount(address common.Address) error {
+	api.logger.Debugf("anvil_stopImpersonatingAccount, address: %s", address)
+
+	return api.api.Dev().StopImpersonatingAccount(types.NewAddress(address.Bytes()))
+}
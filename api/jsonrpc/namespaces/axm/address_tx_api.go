@@ -0,0 +1,103 @@
+package axm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/axiomesh/axiom-kit/types"
+	rpctypes "github.com/axiomesh/axiom-ledger/api/jsonrpc/types"
+)
+
+// AddressTxsArgs represents the block range and the page of axm_getTransactionsByAddress,
+// the whole chain is queried if the block range is omitted and pages start from 0.
+type AddressTxsArgs struct {
+	FromBlock *rpctypes.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpctypes.BlockNumber `json:"toBlock"`
+	Page      hexutil.Uint64        `json:"page"`
+	PageSize  hexutil.Uint64        `json:"pageSize"`
+}
+
+type AddressTx struct {
+	TxHash      common.Hash    `json:"transactionHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxIndex     hexutil.Uint64 `json:"transactionIndex"`
+	Direction   string         `json:"direction"`
+}
+
+type AddressTxsResult struct {
+	Transactions []*AddressTx   `json:"transactions"`
+	Page         hexutil.Uint64 `json:"page"`
+	PageSize     hexutil.Uint64 `json:"pageSize"`
+	HasMore      bool           `json:"hasMore"`
+}
+
+// GetTransactionsByAddress returns a page of the transactions sent from or to the address,
+// ordered by block number and transaction index.
+func (api *AxmAPI) GetTransactionsByAddress(address common.Address, args *AddressTxsArgs) (*AddressTxsResult, error) {
+	api.logger.Debugf("axm_getTransactionsByAddress, address: %s, args: %+v", address.String(), args)
+
+	if args == nil {
+		args = &AddressTxsArgs{}
+	}
+	meta, err := api.api.Chain().Meta()
+	if err != nil {
+		return nil, err
+	}
+	begin, end := api.rep.GenesisConfig.EpochInfo.StartBlock, meta.Height
+	if args.FromBlock != nil {
+		if begin, err = resolveBlockNumber(*args.FromBlock, meta.Height); err != nil {
+			return nil, err
+		}
+	}
+	if args.ToBlock != nil {
+		if end, err = resolveBlockNumber(*args.ToBlock, meta.Height); err != nil {
+			return nil, err
+		}
+	}
+	if end > meta.Height {
+		end = meta.Height
+	}
+	if begin > end {
+		return nil, errors.New("invalid block range params")
+	}
+
+	pageSizeLimit := api.rep.Config.JsonRPC.QueryLimit.AddressTxsPageSizeLimit
+	pageSize := uint64(args.PageSize)
+	if pageSize == 0 {
+		pageSize = pageSizeLimit
+	}
+	if pageSize > pageSizeLimit {
+		return nil, fmt.Errorf("page size needs to be less than or equal to %d", pageSizeLimit)
+	}
+	offset := uint64(args.Page) * pageSize
+	if pageSize != 0 && offset/pageSize != uint64(args.Page) {
+		return nil, errors.New("invalid page params")
+	}
+
+	// query one more tx to know whether there is a next page
+	txs, err := api.api.Broker().GetTransactionsByAddress(types.NewAddress(address.Bytes()), begin, end, offset, pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	res := &AddressTxsResult{
+		Transactions: make([]*AddressTx, 0, len(txs)),
+		Page:         args.Page,
+		PageSize:     hexutil.Uint64(pageSize),
+		HasMore:      uint64(len(txs)) > pageSize,
+	}
+	if res.HasMore {
+		txs = txs[:pageSize]
+	}
+	for _, tx := range txs {
+		res.Transactions = append(res.Transactions, &AddressTx{
+			TxHash:      tx.TxHash.ETHHash(),
+			BlockNumber: hexutil.Uint64(tx.BlockNumber),
+			TxIndex:     hexutil.Uint64(tx.TxIndex),
+			Direction:   tx.Direction,
+		})
+	}
+	return res, nil
+}
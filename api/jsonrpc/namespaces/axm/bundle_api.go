@@ -0,0 +1,36 @@
+package axm
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/eth"
+)
+
+// SendBundle adds the signed transactions as a bundle to the txpool of this node, the transactions are placed
+// consecutively in one block proposed by this node or not at all, and the bundle is dropped once the chain
+// height reaches maxBlock. The reverted bundle transactions are reported with the bundle id in the receipts
+// returned by this node.
+func (api *AxmAPI) SendBundle(txs []hexutil.Bytes, maxBlock hexutil.Uint64) (common.Hash, error) {
+	if len(txs) == 0 {
+		return common.Hash{}, errors.New("bundle has no transaction")
+	}
+	bundleTxs := make([]*types.Transaction, 0, len(txs))
+	for _, data := range txs {
+		tx, err := eth.DecodeRawTransaction(api.rep, api.api, data)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		bundleTxs = append(bundleTxs, tx)
+	}
+
+	bundleID, err := api.api.Broker().HandleBundle(bundleTxs, uint64(maxBlock))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	api.logger.Debugf("Receive new bundle: %s, size: %d", bundleID, len(bundleTxs))
+	return common.HexToHash(bundleID), nil
+}
@@ -0,0 +1,67 @@
+package axm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/axiom-kit/types"
+	rpctypes "github.com/axiomesh/axiom-ledger/api/jsonrpc/types"
+	"github.com/axiomesh/axiom-ledger/internal/indexer"
+)
+
+// GetInternalTransactionsByHash returns the flattened call tree of the transaction,
+// the first entry is the top call of the transaction.
+func (api *AxmAPI) GetInternalTransactionsByHash(hash common.Hash) ([]*indexer.InternalTx, error) {
+	api.logger.Debugf("axm_getInternalTransactionsByHash, hash: %s", hash.String())
+
+	return api.api.Broker().GetInternalTxsByHash(types.NewHash(hash.Bytes()))
+}
+
+// GetInternalTransactionsByAddress returns the internal calls sent from or to the address
+// in the block range, including the calls into system contracts.
+func (api *AxmAPI) GetInternalTransactionsByAddress(address common.Address, fromBlock, toBlock *rpctypes.BlockNumber) ([]*indexer.InternalTx, error) {
+	api.logger.Debugf("axm_getInternalTransactionsByAddress, address: %s, fromBlock: %v, toBlock: %v", address.String(), fromBlock, toBlock)
+
+	meta, err := api.api.Chain().Meta()
+	if err != nil {
+		return nil, err
+	}
+	begin, end := meta.Height, meta.Height
+	if fromBlock != nil {
+		if begin, err = resolveBlockNumber(*fromBlock, meta.Height); err != nil {
+			return nil, err
+		}
+	}
+	if toBlock != nil {
+		if end, err = resolveBlockNumber(*toBlock, meta.Height); err != nil {
+			return nil, err
+		}
+	}
+	if end > meta.Height {
+		end = meta.Height
+	}
+	if begin > end {
+		return nil, errors.New("invalid block range params")
+	}
+	rangeLimit := api.rep.Config.JsonRPC.QueryLimit.InternalTxBlockRangeLimit
+	if end-begin >= rangeLimit {
+		return nil, fmt.Errorf("query block range needs to be less than or equal to %d", rangeLimit)
+	}
+
+	return api.api.Broker().GetInternalTxsByAddress(types.NewAddress(address.Bytes()), begin, end)
+}
+
+func resolveBlockNumber(number rpctypes.BlockNumber, latest uint64) (uint64, error) {
+	switch {
+	case number == rpctypes.LatestBlockNumber || number == rpctypes.PendingBlockNumber:
+		return latest, nil
+	case number == rpctypes.EarliestBlockNumber:
+		return 0, nil
+	case number < 0:
+		return 0, fmt.Errorf("invalid block number %d", number)
+	default:
+		return uint64(number), nil
+	}
+}
@@ -0,0 +1,28 @@
+package axm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/eth"
+)
+
+// SendPrivateRawTransaction adds the signed transaction to the txpool of this node only, the transaction is
+// never broadcast to other nodes and is only included in the block proposed by this node. If maxBlock is set,
+// the transaction is dropped once the chain height reaches it.
+func (api *AxmAPI) SendPrivateRawTransaction(data hexutil.Bytes, maxBlock *hexutil.Uint64) (common.Hash, error) {
+	tx, err := eth.DecodeRawTransaction(api.rep, api.api, data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	api.logger.Debugf("Receive new private tx: %s", tx.GetHash().String())
+
+	var deadline uint64
+	if maxBlock != nil {
+		deadline = uint64(*maxBlock)
+	}
+	if err = api.api.Broker().HandlePrivateTransaction(tx, deadline); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.GetHash().ETHHash(), nil
+}
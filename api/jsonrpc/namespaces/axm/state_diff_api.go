@@ -0,0 +1,134 @@
+package axm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	rpctypes "github.com/axiomesh/axiom-ledger/api/jsonrpc/types"
+	"github.com/axiomesh/axiom-ledger/internal/ledger"
+)
+
+type AccountState struct {
+	Balance  *hexutil.Big   `json:"balance"`
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	CodeHash hexutil.Bytes  `json:"codeHash"`
+}
+
+// StorageDiff is a changed storage slot, the empty value means the slot is not set.
+type StorageDiff struct {
+	Key  hexutil.Bytes `json:"key"`
+	Pre  hexutil.Bytes `json:"pre"`
+	Post hexutil.Bytes `json:"post"`
+}
+
+// AccountDiff is a changed account, pre is null for the created account
+// and post is null for the destructed account.
+type AccountDiff struct {
+	Address common.Address `json:"address"`
+	Pre     *AccountState  `json:"pre"`
+	Post    *AccountState  `json:"post"`
+	Storage []*StorageDiff `json:"storage"`
+}
+
+type BlockStateDiff struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	StateRoot   common.Hash    `json:"stateRoot"`
+	Accounts    []*AccountDiff `json:"accounts"`
+}
+
+// GetBlockStateDiff returns the accounts and storage slots changed by the block with their values
+// before and after the block, only the recent blocks are available.
+func (api *AxmAPI) GetBlockStateDiff(blockNumber rpctypes.BlockNumber) (*BlockStateDiff, error) {
+	api.logger.Debugf("axm_getBlockStateDiff, blockNumber: %v", blockNumber)
+
+	meta, err := api.api.Chain().Meta()
+	if err != nil {
+		return nil, err
+	}
+	height, err := resolveBlockNumber(blockNumber, meta.Height)
+	if err != nil {
+		return nil, err
+	}
+	if height > meta.Height {
+		return nil, fmt.Errorf("block %d is not found, the latest block is %d", height, meta.Height)
+	}
+	blockHeader, err := api.api.Broker().GetBlockHeaderByNumber(height)
+	if err != nil {
+		return nil, err
+	}
+
+	stateLedger := api.api.Broker().GetViewStateLedger()
+	journal, err := stateLedger.GetStateJournal(height)
+	if err != nil {
+		var historyErr *ledger.HistoryUnavailableError
+		if errors.As(err, &historyErr) {
+			return nil, fmt.Errorf("state diff of block %d is not available, the available range is from %d to %d", height, historyErr.Min, historyErr.Max)
+		}
+		if errors.Is(err, ledger.ErrSnapshotDisabled) {
+			return nil, errors.New("state diff requires snapshot enabled")
+		}
+		return nil, err
+	}
+	// the new values are read from the state at the block
+	view, err := stateLedger.NewView(blockHeader, height == meta.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &BlockStateDiff{
+		BlockNumber: hexutil.Uint64(height),
+		BlockHash:   blockHeader.Hash().ETHHash(),
+		StateRoot:   blockHeader.StateRoot.ETHHash(),
+		Accounts:    make([]*AccountDiff, 0, len(journal.Journals)),
+	}
+	for _, entry := range journal.Journals {
+		if !entry.AccountChanged {
+			continue
+		}
+		diff := &AccountDiff{
+			Address: entry.Address.ETHAddress(),
+			Storage: make([]*StorageDiff, 0, len(entry.PrevStates)),
+		}
+		if entry.PrevAccount != nil {
+			diff.Pre = &AccountState{
+				Balance:  (*hexutil.Big)(new(big.Int).Set(entry.PrevAccount.Balance)),
+				Nonce:    hexutil.Uint64(entry.PrevAccount.Nonce),
+				CodeHash: entry.PrevAccount.CodeHash,
+			}
+		}
+		account := view.GetAccount(entry.Address)
+		if account != nil {
+			diff.Post = &AccountState{
+				Balance:  (*hexutil.Big)(account.GetBalance()),
+				Nonce:    hexutil.Uint64(account.GetNonce()),
+				CodeHash: account.CodeHash(),
+			}
+		}
+		for key, prev := range entry.PrevStates {
+			var post []byte
+			if account != nil {
+				_, post = account.GetState([]byte(key))
+			}
+			diff.Storage = append(diff.Storage, &StorageDiff{
+				Key:  []byte(key),
+				Pre:  prev,
+				Post: post,
+			})
+		}
+		sort.Slice(diff.Storage, func(i, j int) bool {
+			return bytes.Compare(diff.Storage[i].Key, diff.Storage[j].Key) < 0
+		})
+		res.Accounts = append(res.Accounts, diff)
+	}
+	sort.Slice(res.Accounts, func(i, j int) bool {
+		return bytes.Compare(res.Accounts[i].Address.Bytes(), res.Accounts[j].Address.Bytes()) < 0
+	})
+	return res, nil
+}
@@ -96,6 +96,9 @@ func (api *BlockChainAPI) GetBalance(address common.Address, blockNrOrHash *rpct
 	}
 
 	balance := stateLedger.GetBalance(types.NewAddress(address.Bytes()))
+	if err := stateLedger.ForkError(); err != nil {
+		return nil, err
+	}
 	api.logger.Debugf("balance: %d", balance)
 
 	return (*ethhexutil.Big)(balance), nil
@@ -254,6 +257,9 @@ func (api *BlockChainAPI) GetCode(address common.Address, blockNrOrHash *rpctype
 	}
 
 	code := stateLedger.GetCode(types.NewAddress(address.Bytes()))
+	if err := stateLedger.ForkError(); err != nil {
+		return nil, err
+	}
 
 	return code, nil
 }
@@ -281,6 +287,9 @@ func (api *BlockChainAPI) GetStorageAt(address common.Address, key string, block
 	}
 
 	ok, val := stateLedger.GetState(types.NewAddress(address.Bytes()), hash.Bytes())
+	if err := stateLedger.ForkError(); err != nil {
+		return nil, err
+	}
 	if !ok {
 		return nil, nil
 	}
@@ -364,6 +373,10 @@ func DoCall(ctx context.Context, blockNrOrHash *rpctypes.BlockNumberOrHash, over
 	if evm.Cancelled() {
 		return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
 	}
+	// the call is executed on the missing state if the forked state failed to fetch
+	if err := stateLedger.ForkError(); err != nil {
+		return nil, err
+	}
 	if err != nil {
 		// logger.Errorf("err: %w (supplied gas %d)", err, msg.GasLimit)
 		return result, err
@@ -420,6 +433,9 @@ func (api *BlockChainAPI) EstimateGas(args types.CallArgs, blockNrOrHash *rpctyp
 			return 0, err
 		}
 		balance := stateLedger.GetBalance(types.NewAddress(args.From.Bytes()))
+		if err := stateLedger.ForkError(); err != nil {
+			return 0, err
+		}
 		api.logger.Debugf("balance: %d", balance)
 		available := new(big.Int).Set(balance)
 		if args.Value != nil {
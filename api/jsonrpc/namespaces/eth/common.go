@@ -57,11 +57,44 @@ func getStateLedgerAt(api api.CoreAPI, blockNrOrHash *rpctypes.BlockNumberOrHash
 	enableSnapshot := blockHeader.Number == meta.Height
 	lg, err := api.Broker().GetViewStateLedger().NewView(blockHeader, enableSnapshot)
 	if err != nil {
+		var historyErr *ledger.HistoryUnavailableError
+		if errors.As(err, &historyErr) {
+			return nil, newHistoryUnavailableError(historyErr)
+		}
 		return nil, fmt.Errorf("GetViewStateLedger error: %v", err)
 	}
 	return lg, nil
 }
 
+// historyUnavailableError is an API error that reports the range of blocks whose state
+// is kept by the node, clients can retry on an archive node if the block is out of range.
+type historyUnavailableError struct {
+	error
+	oldestBlock uint64
+	latestBlock uint64
+}
+
+// ErrorCode returns the JSON error code for a missing historical state.
+func (e *historyUnavailableError) ErrorCode() int {
+	return -32000
+}
+
+// ErrorData returns the available block range.
+func (e *historyUnavailableError) ErrorData() any {
+	return map[string]hexutil.Uint64{
+		"oldestBlock": hexutil.Uint64(e.oldestBlock),
+		"latestBlock": hexutil.Uint64(e.latestBlock),
+	}
+}
+
+func newHistoryUnavailableError(err *ledger.HistoryUnavailableError) *historyUnavailableError {
+	return &historyUnavailableError{
+		error:       fmt.Errorf("state at block %d is not available, the available range is from %d to %d", err.Number, err.Min, err.Max),
+		oldestBlock: err.Min,
+		latestBlock: err.Max,
+	}
+}
+
 // NewRPCTransaction returns a transaction that will serialize to the RPC representation
 func NewRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *rpctypes.RPCTransaction {
 	from := common.BytesToAddress(tx.GetFrom().Bytes())
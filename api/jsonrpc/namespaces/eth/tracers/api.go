@@ -71,6 +71,13 @@ type TraceCallConfig struct {
 	BlockOverrides *BlockOverrides
 }
 
+// txTraceResult is the result of a single transaction trace.
+type txTraceResult struct {
+	TxHash common.Hash `json:"txHash"`           // transaction hash
+	Result any         `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
 var errTxNotFound = errors.New("transaction not found")
 
 func (api *TracerAPI) TraceTransaction(hash common.Hash, config *TraceConfig) (any, error) {
@@ -123,6 +130,90 @@ func (api *TracerAPI) TraceTransaction(hash common.Hash, config *TraceConfig) (a
 	return api.traceTx(msg, txctx, vmctx, *statedb, config)
 }
 
+// TraceBlockByNumber returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *TracerAPI) TraceBlockByNumber(number rpctypes.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+	if number == rpctypes.PendingBlockNumber || number == rpctypes.LatestBlockNumber {
+		meta, err := api.api.Chain().Meta()
+		if err != nil {
+			return nil, err
+		}
+		number = rpctypes.BlockNumber(meta.Height)
+	}
+	blockHeader, err := api.api.Broker().GetBlockHeaderByNumber(uint64(number))
+	if err != nil {
+		return nil, err
+	}
+	return api.traceBlock(blockHeader, config)
+}
+
+// TraceBlockByHash returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *TracerAPI) TraceBlockByHash(hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+	blockHeader, err := api.api.Broker().GetBlockHeaderByHash(types.NewHash(hash.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	return api.traceBlock(blockHeader, config)
+}
+
+// traceBlock re-executes all the transactions contained within a block on top of
+// the parent state once, and returns the trace result of every transaction.
+func (api *TracerAPI) traceBlock(blockHeader *types.BlockHeader, config *TraceConfig) ([]*txTraceResult, error) {
+	if blockHeader.Number == api.rep.GenesisConfig.EpochInfo.StartBlock {
+		return nil, errors.New("genesis is not traceable")
+	}
+
+	blockTxList, err := api.api.Broker().GetBlockTxList(blockHeader.Number)
+	if err != nil {
+		return nil, err
+	}
+	if len(blockTxList) == 0 {
+		return []*txTraceResult{}, nil
+	}
+	block := &types.Block{
+		Header:       blockHeader,
+		Transactions: blockTxList,
+	}
+
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	// the state of the first transaction is the parent state, the block context is shared by all txs
+	_, vmctx, parentState, err := api.api.Broker().StateAtTransaction(block, 0, reexec)
+	if err != nil {
+		return nil, err
+	}
+	statedb := *parentState
+
+	var (
+		blockHash   = block.Hash().ETHHash()
+		blockNumber = new(big.Int).SetUint64(block.Height())
+		results     = make([]*txTraceResult, len(blockTxList))
+	)
+	for i, tx := range blockTxList {
+		txHash := tx.GetHash().ETHHash()
+		txctx := &tracers.Context{
+			BlockHash:   blockHash,
+			BlockNumber: blockNumber,
+			TxIndex:     i,
+			TxHash:      txHash,
+		}
+		res, err := api.traceTx(executor.TransactionToMessage(tx), txctx, vmctx, statedb, config)
+		if err != nil {
+			results[i] = &txTraceResult{TxHash: txHash, Error: err.Error()}
+		} else {
+			results[i] = &txTraceResult{TxHash: txHash, Result: res}
+		}
+
+		// keep the same behavior as the executor, the sender nonce is always increased
+		statedb.SetNonce(tx.GetFrom(), tx.GetNonce()+1)
+		statedb.Finalise()
+	}
+	return results, nil
+}
+
 func (api *TracerAPI) traceTx(message *core.Message, txctx *tracers.Context, vmctx vm.BlockContext, statedb ledger.StateLedger, config *TraceConfig) (any, error) {
 	var (
 		tracer    tracers.Tracer
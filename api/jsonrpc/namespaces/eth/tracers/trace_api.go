@@ -0,0 +1,447 @@
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/axiom-kit/types"
+	rpctypes "github.com/axiomesh/axiom-ledger/api/jsonrpc/types"
+	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+const (
+	flatCallTracerName = "flatCallTracer"
+	prestateTracerName = "prestateTracer"
+	muxTracerName      = "muxTracer"
+
+	traceTypeTrace     = "trace"
+	traceTypeVmTrace   = "vmTrace"
+	traceTypeStateDiff = "stateDiff"
+)
+
+// TraceAPI provides the OpenEthereum compatible trace namespace,
+// which is built on the tracers of the debug namespace.
+type TraceAPI struct {
+	tracer *TracerAPI
+	rep    *repo.Repo
+	api    api.CoreAPI
+	logger logrus.FieldLogger
+}
+
+func NewTraceAPI(rep *repo.Repo, api api.CoreAPI, logger logrus.FieldLogger) *TraceAPI {
+	return &TraceAPI{tracer: NewTracerAPI(rep, api, logger), rep: rep, api: api, logger: logger}
+}
+
+// TraceFilterArgs represents the arguments of trace_filter.
+type TraceFilterArgs struct {
+	FromBlock   *rpctypes.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpctypes.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address      `json:"fromAddress"`
+	ToAddress   []common.Address      `json:"toAddress"`
+	After       *uint64               `json:"after"`
+	Count       *uint64               `json:"count"`
+}
+
+// TraceResults is the result of trace_replayTransaction, the trace types
+// not requested are null.
+type TraceResults struct {
+	Output          hexutil.Bytes                    `json:"output"`
+	StateDiff       map[common.Address]*stateDiffAcc `json:"stateDiff"`
+	Trace           []json.RawMessage                `json:"trace"`
+	VmTrace         json.RawMessage                  `json:"vmTrace"`
+	TransactionHash *common.Hash                     `json:"transactionHash,omitempty"`
+}
+
+// stateDiffAcc is the OpenEthereum style state diff of an account, each field is
+// "=" if unchanged, or an object keyed by "+" (born), "-" (died) or "*" (changed).
+type stateDiffAcc struct {
+	Balance any                 `json:"balance"`
+	Nonce   any                 `json:"nonce"`
+	Code    any                 `json:"code"`
+	Storage map[common.Hash]any `json:"storage"`
+}
+
+type stateDiffChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// prestateAccount is the account format of prestateTracer.
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Nonce   uint64                      `json:"nonce,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+type prestateDiff struct {
+	Pre  map[common.Address]*prestateAccount `json:"pre"`
+	Post map[common.Address]*prestateAccount `json:"post"`
+}
+
+// flatCallFrame holds the fields of a flatCallTracer frame used for filtering.
+type flatCallFrame struct {
+	Action struct {
+		From *common.Address `json:"from"`
+		To   *common.Address `json:"to"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+		Code    hexutil.Bytes   `json:"code"`
+		Output  hexutil.Bytes   `json:"output"`
+	} `json:"result"`
+}
+
+// Block returns the parity style traces of all the transactions in the block.
+func (api *TraceAPI) Block(number rpctypes.BlockNumber) ([]json.RawMessage, error) {
+	api.logger.Debugf("trace_block, number: %d", number)
+	blockHeader, err := api.getBlockHeader(number)
+	if err != nil {
+		return nil, err
+	}
+	return api.traceBlock(blockHeader)
+}
+
+// Transaction returns the parity style traces of the transaction.
+func (api *TraceAPI) Transaction(hash common.Hash) ([]json.RawMessage, error) {
+	api.logger.Debugf("trace_transaction, hash: %s", hash.String())
+	res, err := api.tracer.TraceTransaction(hash, flatCallTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, errTxNotFound
+	}
+	return decodeFlatTraces(res)
+}
+
+// ReplayTransaction replays the transaction and returns the requested trace types,
+// which are any of trace, vmTrace and stateDiff.
+func (api *TraceAPI) ReplayTransaction(hash common.Hash, traceTypes []string) (*TraceResults, error) {
+	api.logger.Debugf("trace_replayTransaction, hash: %s, trace types: %v", hash.String(), traceTypes)
+	wanted := make(map[string]bool)
+	for _, typ := range traceTypes {
+		switch typ {
+		case traceTypeTrace, traceTypeVmTrace, traceTypeStateDiff:
+			wanted[typ] = true
+		default:
+			return nil, fmt.Errorf("unsupported trace type %q", typ)
+		}
+	}
+
+	// the flat call tracer is always needed to get the output
+	muxConfig := map[string]json.RawMessage{
+		flatCallTracerName: json.RawMessage(`{"convertParityErrors":true}`),
+	}
+	if wanted[traceTypeVmTrace] {
+		muxConfig[parityVmTracerName] = nil
+	}
+	if wanted[traceTypeStateDiff] {
+		muxConfig[prestateTracerName] = json.RawMessage(`{"diffMode":true}`)
+	}
+	tracerConfig, err := json.Marshal(muxConfig)
+	if err != nil {
+		return nil, err
+	}
+	tracerName := muxTracerName
+	res, err := api.tracer.TraceTransaction(hash, &TraceConfig{Tracer: &tracerName, TracerConfig: tracerConfig})
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, errTxNotFound
+	}
+	raw, ok := res.(json.RawMessage)
+	if !ok {
+		return nil, errors.New("unexpected trace result")
+	}
+	var muxRes map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &muxRes); err != nil {
+		return nil, err
+	}
+
+	results := &TraceResults{TransactionHash: &hash}
+	traces, err := decodeFlatTraces(muxRes[flatCallTracerName])
+	if err != nil {
+		return nil, err
+	}
+	if len(traces) > 0 {
+		var frame flatCallFrame
+		if err := json.Unmarshal(traces[0], &frame); err != nil {
+			return nil, err
+		}
+		if frame.Result != nil {
+			results.Output = frame.Result.Output
+			if frame.Result.Address != nil {
+				results.Output = frame.Result.Code
+			}
+		}
+	}
+	if results.Output == nil {
+		results.Output = hexutil.Bytes{}
+	}
+	if wanted[traceTypeTrace] {
+		results.Trace = traces
+	}
+	if wanted[traceTypeVmTrace] {
+		results.VmTrace = muxRes[parityVmTracerName]
+	}
+	if wanted[traceTypeStateDiff] {
+		var diff prestateDiff
+		if err := json.Unmarshal(muxRes[prestateTracerName], &diff); err != nil {
+			return nil, err
+		}
+		results.StateDiff = toParityStateDiff(&diff)
+	}
+	return results, nil
+}
+
+// Filter returns the parity style traces matching the given from and to addresses
+// in the block range.
+func (api *TraceAPI) Filter(args TraceFilterArgs) ([]json.RawMessage, error) {
+	api.logger.Debugf("trace_filter, args: %+v", args)
+	meta, err := api.api.Chain().Meta()
+	if err != nil {
+		return nil, err
+	}
+	begin, end := meta.Height, meta.Height
+	if args.FromBlock != nil {
+		begin = api.resolveBlockNumber(*args.FromBlock, meta.Height)
+	}
+	if args.ToBlock != nil {
+		end = api.resolveBlockNumber(*args.ToBlock, meta.Height)
+	}
+	if end > meta.Height {
+		end = meta.Height
+	}
+	if begin > end {
+		return nil, errors.New("invalid block range params")
+	}
+	rangeLimit := api.rep.Config.JsonRPC.QueryLimit.TraceFilterBlockRangeLimit
+	if end-begin >= rangeLimit {
+		return nil, fmt.Errorf("query block range needs to be less than or equal to %d", rangeLimit)
+	}
+
+	fromAddresses := make(map[common.Address]struct{}, len(args.FromAddress))
+	for _, addr := range args.FromAddress {
+		fromAddresses[addr] = struct{}{}
+	}
+	toAddresses := make(map[common.Address]struct{}, len(args.ToAddress))
+	for _, addr := range args.ToAddress {
+		toAddresses[addr] = struct{}{}
+	}
+
+	var (
+		after   uint64
+		count   uint64 = math.MaxUint64
+		skipped uint64
+		results = make([]json.RawMessage, 0)
+	)
+	if args.After != nil {
+		after = *args.After
+	}
+	if args.Count != nil {
+		count = *args.Count
+	}
+	for height := begin; height <= end && uint64(len(results)) < count; height++ {
+		blockHeader, err := api.api.Broker().GetBlockHeaderByNumber(height)
+		if err != nil {
+			return nil, err
+		}
+		traces, err := api.traceBlock(blockHeader)
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			var frame flatCallFrame
+			if err := json.Unmarshal(trace, &frame); err != nil {
+				return nil, err
+			}
+			if !matchTraceAddress(&frame, fromAddresses, toAddresses) {
+				continue
+			}
+			if skipped < after {
+				skipped++
+				continue
+			}
+			results = append(results, trace)
+			if uint64(len(results)) >= count {
+				break
+			}
+		}
+	}
+	return results, nil
+}
+
+func (api *TraceAPI) traceBlock(blockHeader *types.BlockHeader) ([]json.RawMessage, error) {
+	if blockHeader.Number == api.rep.GenesisConfig.EpochInfo.StartBlock {
+		return []json.RawMessage{}, nil
+	}
+	txResults, err := api.tracer.traceBlock(blockHeader, flatCallTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	traces := make([]json.RawMessage, 0, len(txResults))
+	for _, txResult := range txResults {
+		if txResult.Error != "" {
+			// the tx is not executed by evm(e.g. invalid nonce), so it has no call frame
+			api.logger.Warnf("trace tx %s failed: %s", txResult.TxHash.String(), txResult.Error)
+			continue
+		}
+		txTraces, err := decodeFlatTraces(txResult.Result)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, txTraces...)
+	}
+	return traces, nil
+}
+
+func (api *TraceAPI) getBlockHeader(number rpctypes.BlockNumber) (*types.BlockHeader, error) {
+	meta, err := api.api.Chain().Meta()
+	if err != nil {
+		return nil, err
+	}
+	return api.api.Broker().GetBlockHeaderByNumber(api.resolveBlockNumber(number, meta.Height))
+}
+
+func (api *TraceAPI) resolveBlockNumber(number rpctypes.BlockNumber, latest uint64) uint64 {
+	switch number {
+	case rpctypes.LatestBlockNumber, rpctypes.PendingBlockNumber:
+		return latest
+	case rpctypes.EarliestBlockNumber:
+		return api.rep.GenesisConfig.EpochInfo.StartBlock
+	default:
+		return uint64(number)
+	}
+}
+
+func flatCallTraceConfig() *TraceConfig {
+	tracerName := flatCallTracerName
+	return &TraceConfig{Tracer: &tracerName, TracerConfig: json.RawMessage(`{"convertParityErrors":true}`)}
+}
+
+func decodeFlatTraces(res any) ([]json.RawMessage, error) {
+	raw, ok := res.(json.RawMessage)
+	if !ok {
+		return nil, errors.New("unexpected trace result")
+	}
+	var traces []json.RawMessage
+	if err := json.Unmarshal(raw, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+func matchTraceAddress(frame *flatCallFrame, fromAddresses, toAddresses map[common.Address]struct{}) bool {
+	if len(fromAddresses) > 0 {
+		if frame.Action.From == nil {
+			return false
+		}
+		if _, ok := fromAddresses[*frame.Action.From]; !ok {
+			return false
+		}
+	}
+	if len(toAddresses) > 0 {
+		to := frame.Action.To
+		if to == nil && frame.Result != nil {
+			// contract creation
+			to = frame.Result.Address
+		}
+		if to == nil {
+			return false
+		}
+		if _, ok := toAddresses[*to]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// toParityStateDiff converts the diff mode result of prestateTracer to the OpenEthereum state diff.
+// The pre state only contains modified accounts and slots, and the post state only the modified fields.
+func toParityStateDiff(diff *prestateDiff) map[common.Address]*stateDiffAcc {
+	res := make(map[common.Address]*stateDiffAcc)
+	for addr, pre := range diff.Pre {
+		post, ok := diff.Post[addr]
+		if !ok {
+			// account is self destructed
+			acc := &stateDiffAcc{
+				Balance: map[string]any{"-": bigOrZero(pre.Balance)},
+				Nonce:   map[string]any{"-": hexutil.Uint64(pre.Nonce)},
+				Code:    map[string]any{"-": bytesOrEmpty(pre.Code)},
+				Storage: make(map[common.Hash]any),
+			}
+			for key, val := range pre.Storage {
+				acc.Storage[key] = map[string]any{"-": val}
+			}
+			res[addr] = acc
+			continue
+		}
+
+		acc := &stateDiffAcc{
+			Balance: "=",
+			Nonce:   "=",
+			Code:    "=",
+			Storage: make(map[common.Hash]any),
+		}
+		if post.Balance != nil {
+			acc.Balance = map[string]any{"*": &stateDiffChange{From: bigOrZero(pre.Balance), To: post.Balance}}
+		}
+		if post.Nonce != 0 {
+			acc.Nonce = map[string]any{"*": &stateDiffChange{From: hexutil.Uint64(pre.Nonce), To: hexutil.Uint64(post.Nonce)}}
+		}
+		if post.Code != nil {
+			acc.Code = map[string]any{"*": &stateDiffChange{From: bytesOrEmpty(pre.Code), To: post.Code}}
+		}
+		for key, val := range pre.Storage {
+			acc.Storage[key] = map[string]any{"*": &stateDiffChange{From: val, To: post.Storage[key]}}
+		}
+		for key, val := range post.Storage {
+			if _, ok := pre.Storage[key]; !ok {
+				acc.Storage[key] = map[string]any{"*": &stateDiffChange{From: common.Hash{}, To: val}}
+			}
+		}
+		res[addr] = acc
+	}
+
+	for addr, post := range diff.Post {
+		if _, ok := diff.Pre[addr]; ok {
+			continue
+		}
+		// account is created
+		acc := &stateDiffAcc{
+			Balance: map[string]any{"+": bigOrZero(post.Balance)},
+			Nonce:   map[string]any{"+": hexutil.Uint64(post.Nonce)},
+			Code:    map[string]any{"+": bytesOrEmpty(post.Code)},
+			Storage: make(map[common.Hash]any),
+		}
+		for key, val := range post.Storage {
+			acc.Storage[key] = map[string]any{"+": val}
+		}
+		res[addr] = acc
+	}
+	return res
+}
+
+func bigOrZero(b *hexutil.Big) *hexutil.Big {
+	if b == nil {
+		return (*hexutil.Big)(big.NewInt(0))
+	}
+	return b
+}
+
+func bytesOrEmpty(b hexutil.Bytes) hexutil.Bytes {
+	if b == nil {
+		return hexutil.Bytes{}
+	}
+	return b
+}
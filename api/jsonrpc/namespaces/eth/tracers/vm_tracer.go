@@ -0,0 +1,242 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+const parityVmTracerName = "parityVmTracer"
+
+func init() {
+	tracers.DefaultDirectory.Register(parityVmTracerName, newParityVmTracer, false)
+}
+
+// vmTrace is the OpenEthereum style vm trace of a single call frame.
+type vmTrace struct {
+	Code hexutil.Bytes  `json:"code"`
+	Ops  []*vmOperation `json:"ops"`
+}
+
+type vmOperation struct {
+	Cost uint64               `json:"cost"`
+	Ex   *vmExecutedOperation `json:"ex"`
+	Pc   uint64               `json:"pc"`
+	Op   string               `json:"op"`
+	Sub  *vmTrace             `json:"sub"`
+
+	// scope and pre-execution info needed to fill in Ex after the opcode executed
+	opcode  vm.OpCode
+	scope   *vm.ScopeContext
+	memOff  int64
+	memSize int64
+}
+
+type vmExecutedOperation struct {
+	Mem   *vmMemoryDiff  `json:"mem"`
+	Push  []string       `json:"push"`
+	Store *vmStorageDiff `json:"store"`
+	Used  uint64         `json:"used"`
+}
+
+type vmMemoryDiff struct {
+	Data hexutil.Bytes `json:"data"`
+	Off  int64         `json:"off"`
+}
+
+type vmStorageDiff struct {
+	Key string `json:"key"`
+	Val string `json:"val"`
+}
+
+type vmFrame struct {
+	trace   *vmTrace
+	pending *vmOperation
+}
+
+// parityVmTracer records every executed opcode with the stack items pushed,
+// the memory and storage written and the gas left, in the OpenEthereum vmTrace format.
+type parityVmTracer struct {
+	root      *vmTrace
+	frames    []*vmFrame
+	interrupt atomic.Bool
+	reason    error
+}
+
+func newParityVmTracer(_ *tracers.Context, _ json.RawMessage) (tracers.Tracer, error) {
+	return &parityVmTracer{}, nil
+}
+
+func (t *parityVmTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *parityVmTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *parityVmTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.root = &vmTrace{Ops: []*vmOperation{}}
+	t.frames = []*vmFrame{{trace: t.root}}
+}
+
+func (t *parityVmTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	if len(t.frames) == 0 {
+		return
+	}
+	t.frames[0].close()
+	t.frames = nil
+}
+
+func (t *parityVmTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.interrupt.Load() || len(t.frames) == 0 {
+		return
+	}
+	sub := &vmTrace{Ops: []*vmOperation{}}
+	if parent := t.frames[len(t.frames)-1]; parent.pending != nil {
+		parent.pending.Sub = sub
+	}
+	t.frames = append(t.frames, &vmFrame{trace: sub})
+}
+
+func (t *parityVmTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.frames) <= 1 {
+		return
+	}
+	t.frames[len(t.frames)-1].close()
+	t.frames = t.frames[:len(t.frames)-1]
+}
+
+func (t *parityVmTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.interrupt.Load() || err != nil || len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	// the previous opcode of this frame has been executed, collect its outputs
+	frame.finalize()
+
+	if frame.trace.Code == nil {
+		frame.trace.Code = scope.Contract.Code
+	}
+	operation := &vmOperation{
+		Cost:   cost,
+		Pc:     pc,
+		Op:     op.String(),
+		opcode: op,
+		scope:  scope,
+		Ex:     &vmExecutedOperation{Push: []string{}},
+	}
+	if gas > cost {
+		operation.Ex.Used = gas - cost
+	}
+
+	stack := scope.Stack.Data()
+	back := func(n int) *big.Int {
+		return stack[len(stack)-1-n].ToBig()
+	}
+	switch {
+	case op == vm.SSTORE && len(stack) >= 2:
+		operation.Ex.Store = &vmStorageDiff{
+			Key: hexutil.EncodeBig(back(0)),
+			Val: hexutil.EncodeBig(back(1)),
+		}
+	case op == vm.MSTORE && len(stack) >= 1:
+		operation.memOff, operation.memSize = back(0).Int64(), 32
+	case op == vm.MSTORE8 && len(stack) >= 1:
+		operation.memOff, operation.memSize = back(0).Int64(), 1
+	case (op == vm.CALLDATACOPY || op == vm.CODECOPY || op == vm.RETURNDATACOPY || op == vm.MCOPY) && len(stack) >= 3:
+		operation.memOff, operation.memSize = back(0).Int64(), back(2).Int64()
+	case op == vm.EXTCODECOPY && len(stack) >= 4:
+		operation.memOff, operation.memSize = back(1).Int64(), back(3).Int64()
+	case (op == vm.CALL || op == vm.CALLCODE) && len(stack) >= 7:
+		operation.memOff, operation.memSize = back(5).Int64(), back(6).Int64()
+	case (op == vm.DELEGATECALL || op == vm.STATICCALL) && len(stack) >= 6:
+		operation.memOff, operation.memSize = back(4).Int64(), back(5).Int64()
+	}
+
+	frame.trace.Ops = append(frame.trace.Ops, operation)
+	frame.pending = operation
+}
+
+func (t *parityVmTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if len(t.frames) == 0 {
+		return
+	}
+	// the faulted opcode has no execution result
+	frame := t.frames[len(t.frames)-1]
+	if frame.pending != nil && frame.pending.Pc == pc {
+		frame.pending.Ex = nil
+		frame.pending = nil
+	}
+}
+
+// GetResult returns the json-encoded vm trace of the top call frame.
+func (t *parityVmTracer) GetResult() (json.RawMessage, error) {
+	res, err := json.Marshal(t.root)
+	if err != nil {
+		return nil, err
+	}
+	return res, t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *parityVmTracer) Stop(err error) {
+	t.reason = err
+	t.interrupt.Store(true)
+}
+
+// finalize fills in the execution result of the pending opcode of the frame.
+func (f *vmFrame) finalize() {
+	operation := f.pending
+	if operation == nil {
+		return
+	}
+	f.pending = nil
+
+	stack := operation.scope.Stack.Data()
+	pushed := pushedStackItems(operation.opcode)
+	if pushed > len(stack) {
+		pushed = len(stack)
+	}
+	for i := len(stack) - pushed; i < len(stack); i++ {
+		operation.Ex.Push = append(operation.Ex.Push, hexutil.EncodeBig(stack[i].ToBig()))
+	}
+
+	if operation.memSize > 0 {
+		data, err := tracers.GetMemoryCopyPadded(operation.scope.Memory, operation.memOff, operation.memSize)
+		if err == nil {
+			operation.Ex.Mem = &vmMemoryDiff{Data: data, Off: operation.memOff}
+		}
+	}
+	operation.scope = nil
+}
+
+// close drops the scope of the last opcode of the frame, the interpreter has
+// released the stack and memory of the frame and the halting opcodes push nothing.
+func (f *vmFrame) close() {
+	if f.pending != nil {
+		f.pending.scope = nil
+		f.pending = nil
+	}
+}
+
+// pushedStackItems returns the number of stack items shown as pushed by the opcode,
+// DUPn and SWAPn show all the items they touched.
+func pushedStackItems(op vm.OpCode) int {
+	switch {
+	case op.IsPush():
+		return 1
+	case op >= vm.DUP1 && op <= vm.DUP16:
+		return int(op-vm.DUP1) + 2
+	case op >= vm.SWAP1 && op <= vm.SWAP16:
+		return int(op-vm.SWAP1) + 2
+	}
+	switch op {
+	case vm.STOP, vm.POP, vm.MSTORE, vm.MSTORE8, vm.SSTORE, vm.TSTORE, vm.JUMP, vm.JUMPI, vm.JUMPDEST,
+		vm.LOG0, vm.LOG1, vm.LOG2, vm.LOG3, vm.LOG4, vm.RETURN, vm.REVERT, vm.SELFDESTRUCT, vm.INVALID,
+		vm.CALLDATACOPY, vm.CODECOPY, vm.EXTCODECOPY, vm.RETURNDATACOPY, vm.MCOPY:
+		return 0
+	}
+	return 1
+}
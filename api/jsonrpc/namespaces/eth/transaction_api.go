@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/axiomesh/axiom-kit/types"
 	rpctypes "github.com/axiomesh/axiom-ledger/api/jsonrpc/types"
+	consensuscommon "github.com/axiomesh/axiom-ledger/internal/consensus/common"
 	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
 	"github.com/axiomesh/axiom-ledger/internal/executor/system/access"
 	syscommon "github.com/axiomesh/axiom-ledger/internal/executor/system/common"
@@ -154,6 +156,9 @@ func (api *TransactionAPI) GetTransactionCount(address common.Address, blockNrOr
 	}
 
 	nonce := stateLedger.GetNonce(types.NewAddress(address.Bytes()))
+	if err := stateLedger.ForkError(); err != nil {
+		return nil, err
+	}
 
 	return (*hexutil.Uint64)(&nonce), nil
 }
@@ -225,14 +230,90 @@ func (api *TransactionAPI) GetTransactionReceipt(hash common.Hash) (ret map[stri
 		return nil, err
 	}
 
+	fields := formatReceipt(tx, receipt, common.BytesToHash(meta.BlockHash.Bytes()), meta.BlockHeight, meta.Index)
+	fillBundleFields(api.api, fields, tx)
+
+	api.logger.Debugf("eth_getTransactionReceipt: %v", fields)
+
+	return fields, nil
+}
+
+// GetBlockReceipts returns the receipts of all transactions in the block identified by number, hash or tag.
+func (api *TransactionAPI) GetBlockReceipts(blockNrOrHash rpctypes.BlockNumberOrHash) (ret []map[string]any, err error) {
+	defer func(start time.Time) {
+		invokeReadOnlyDuration.Observe(time.Since(start).Seconds())
+		queryTotalCounter.Inc()
+		if err != nil {
+			queryFailedCounter.Inc()
+		}
+	}(time.Now())
+
+	api.logger.Debugf("eth_getBlockReceipts, block: %s", blockNrOrHash.String())
+
+	var blockHeader *types.BlockHeader
+	if blockNum, ok := blockNrOrHash.Number(); ok {
+		if blockNum == rpctypes.PendingBlockNumber || blockNum == rpctypes.LatestBlockNumber {
+			meta, err := api.api.Chain().Meta()
+			if err != nil {
+				return nil, err
+			}
+			blockNum = rpctypes.BlockNumber(meta.Height)
+		}
+		blockHeader, err = api.api.Broker().GetBlockHeaderByNumber(uint64(blockNum))
+	} else if blockHash, ok := blockNrOrHash.Hash(); ok {
+		blockHeader, err = api.api.Broker().GetBlockHeaderByHash(types.NewHash(blockHash.Bytes()))
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	if err != nil {
+		if errors.Is(err, ledger.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	txs, err := api.api.Broker().GetBlockTxList(blockHeader.Number)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := api.api.Broker().GetReceipts(blockHeader.Number)
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) != len(receipts) {
+		return nil, fmt.Errorf("receipts length %d mismatch txs length %d of block %d", len(receipts), len(txs), blockHeader.Number)
+	}
+
+	blockHash := blockHeader.Hash().ETHHash()
+	ret = make([]map[string]any, 0, len(receipts))
+	for i, receipt := range receipts {
+		fields := formatReceipt(txs[i], receipt, blockHash, blockHeader.Number, uint64(i))
+		fillBundleFields(api.api, fields, txs[i])
+		ret = append(ret, fields)
+	}
+	return ret, nil
+}
+
+// fillBundleFields adds the bundle info of the tx, which is persisted with the block by the node which batched the bundle.
+func fillBundleFields(api api.CoreAPI, fields map[string]any, tx *types.Transaction) {
+	bundleTx, err := api.Broker().GetBundleTx(tx.GetHash())
+	if err != nil {
+		return
+	}
+	fields["bundleId"] = bundleTx.BundleID
+	fields["bundleReverted"] = bundleTx.Reverted
+}
+
+// formatReceipt formats the receipt of the tx the same as eth_getTransactionReceipt.
+func formatReceipt(tx *types.Transaction, receipt *types.Receipt, blockHash common.Hash, blockNumber uint64, index uint64) map[string]any {
 	fields := map[string]any{
 		"type":              hexutil.Uint(tx.GetType()),
 		"cumulativeGasUsed": hexutil.Uint64(receipt.CumulativeGasUsed),
-		"transactionHash":   hash,
+		"transactionHash":   tx.GetHash().ETHHash(),
 		"gasUsed":           hexutil.Uint64(receipt.GasUsed),
-		"blockHash":         common.BytesToHash(meta.BlockHash.Bytes()),
-		"blockNumber":       hexutil.Uint64(meta.BlockHeight),
-		"transactionIndex":  hexutil.Uint64(meta.Index),
+		"blockHash":         blockHash,
+		"blockNumber":       hexutil.Uint64(blockNumber),
+		"transactionIndex":  hexutil.Uint64(index),
 		"from":              common.BytesToAddress(tx.GetFrom().Bytes()),
 		"effectiveGasPrice": (*hexutil.Big)(receipt.EffectiveGasPrice),
 	}
@@ -281,9 +362,7 @@ func (api *TransactionAPI) GetTransactionReceipt(hash common.Hash) (ret map[stri
 		fields["to"] = common.BytesToAddress(tx.GetTo().Bytes())
 	}
 
-	api.logger.Debugf("eth_getTransactionReceipt: %v", fields)
-
-	return fields, nil
+	return fields
 }
 
 // SendRawTransaction send a raw Ethereum transaction.
@@ -296,44 +375,143 @@ func (api *TransactionAPI) SendRawTransaction(data hexutil.Bytes) (ret common.Ha
 		}
 	}(time.Now())
 
+	tx, err := DecodeRawTransaction(api.rep, api.api, data)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	api.logger.Debugf("Receive new eth tx: %s", tx.GetHash().String())
+
+	return sendTransaction(api.api, tx)
+}
+
+// SendTransaction sends the unsigned transaction of the impersonated account, which is only available
+// in the dev mode. The nonce, gas and fees are filled if they are omitted.
+func (api *TransactionAPI) SendTransaction(args types.CallArgs) (ret common.Hash, err error) {
+	defer func(start time.Time) {
+		invokeSendRawTxDuration.Observe(time.Since(start).Seconds())
+		queryTotalCounter.Inc()
+		if err != nil {
+			queryFailedCounter.Inc()
+		}
+	}(time.Now())
+
+	api.logger.Debugf("eth_sendTransaction, args: %s", args)
+
 	if api.rep.StartArgs.ReadonlyMode {
-		return [32]byte{}, errors.New("readonly mode cannot process tx")
+		return common.Hash{}, errors.New("readonly mode cannot process tx")
+	}
+	if !api.rep.Config.IsDevMode() {
+		return common.Hash{}, errors.New("eth_sendTransaction is only supported for the impersonated accounts in the dev mode")
+	}
+	if args.From == nil {
+		return common.Hash{}, errors.New("from is required")
+	}
+	from := types.NewAddress(args.From.Bytes())
+	if !api.api.Dev().IsImpersonated(from) {
+		return common.Hash{}, fmt.Errorf("account %s is not impersonated", args.From)
+	}
+
+	inner := &types.DynamicFeeTx{
+		ChainID: new(big.Int).SetUint64(api.rep.GenesisConfig.ChainID),
+		To:      args.To,
+		Value:   big.NewInt(0),
+	}
+	if args.Value != nil {
+		inner.Value = args.Value.ToInt()
+	}
+	if args.Input != nil {
+		inner.Data = *args.Input
+	} else if args.Data != nil {
+		inner.Data = *args.Data
+	}
+	if args.AccessList != nil {
+		inner.AccessList = *args.AccessList
+	}
+	if args.Nonce != nil {
+		inner.Nonce = uint64(*args.Nonce)
+	} else {
+		inner.Nonce = api.api.TxPool().GetPendingTxCountByAccount(from.String())
+	}
+
+	minGasPrice := api.api.ChainState().EpochInfo.FinanceParams.MinGasPrice.ToBigInt()
+	switch {
+	case args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil:
+		inner.GasFeeCap, inner.GasTipCap = minGasPrice, minGasPrice
+		if args.MaxFeePerGas != nil {
+			inner.GasFeeCap = args.MaxFeePerGas.ToInt()
+		}
+		if args.MaxPriorityFeePerGas != nil {
+			inner.GasTipCap = args.MaxPriorityFeePerGas.ToInt()
+		}
+	case args.GasPrice != nil:
+		inner.GasFeeCap, inner.GasTipCap = args.GasPrice.ToInt(), args.GasPrice.ToInt()
+	default:
+		inner.GasFeeCap, inner.GasTipCap = minGasPrice, minGasPrice
+	}
+
+	if args.Gas != nil {
+		inner.Gas = uint64(*args.Gas)
+	} else {
+		gas, err := NewBlockChainAPI(api.rep, api.api, api.logger).EstimateGas(args, nil)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("estimate gas failed: %w", err)
+		}
+		inner.Gas = uint64(gas)
+	}
+
+	tx := consensuscommon.NewImpersonatedTx(*args.From, inner)
+	if err = validateTransaction(api.rep, api.api, tx); err != nil {
+		return common.Hash{}, err
+	}
+	api.logger.Debugf("Receive new impersonated tx: %s", tx.GetHash().String())
+
+	return sendTransaction(api.api, tx)
+}
+
+// DecodeRawTransaction decodes the raw tx and checks whether the tx can be sent to the consensus.
+func DecodeRawTransaction(rep *repo.Repo, api api.CoreAPI, data hexutil.Bytes) (*types.Transaction, error) {
+	if rep.StartArgs.ReadonlyMode {
+		return nil, errors.New("readonly mode cannot process tx")
 	}
 
 	tx := &types.Transaction{}
 	if err := tx.Unmarshal(data); err != nil {
-		return [32]byte{}, err
+		return nil, err
+	}
+	if err := validateTransaction(rep, api, tx); err != nil {
+		return nil, err
 	}
+	return tx, nil
+}
 
-	if api.rep.Config.Access.EnableWhitelist {
+// validateTransaction checks whether the tx can be sent to the consensus.
+func validateTransaction(rep *repo.Repo, api api.CoreAPI, tx *types.Transaction) error {
+	if rep.Config.Access.EnableWhitelist {
 		from := tx.GetFrom()
 		if from == nil {
-			return [32]byte{}, errors.New("verify tx err")
+			return errors.New("verify tx err")
 		}
-		stateLedger, err := getStateLedgerAt(api.api, nil) // use the latest block
+		stateLedger, err := getStateLedgerAt(api, nil) // use the latest block
 		if err != nil {
-			return [32]byte{}, err
+			return err
 		}
 
 		whitelistContract := access.WhitelistBuildConfig.Build(syscommon.NewViewVMContext(stateLedger))
 		if err = whitelistContract.Verify(from.ETHAddress()); err != nil {
-			return [32]byte{}, err
+			return err
 		}
 	}
 
-	if err = checkTransaction(tx); err != nil {
-		return [32]byte{}, fmt.Errorf("check transaction fail for %s", err.Error())
+	if err := checkTransaction(tx); err != nil {
+		return fmt.Errorf("check transaction fail for %s", err.Error())
 	}
 
-	if ready, status := api.api.Broker().ConsensusReady(); !ready {
-		if api.rep.Config.JsonRPC.RejectTxsIfConsensusAbnormal {
-			return [32]byte{}, fmt.Errorf("the system is temporarily unavailable %s, tx: %s", status, tx.GetHash().String())
+	if ready, status := api.Broker().ConsensusReady(); !ready {
+		if rep.Config.JsonRPC.RejectTxsIfConsensusAbnormal {
+			return fmt.Errorf("the system is temporarily unavailable %s, tx: %s", status, tx.GetHash().String())
 		}
-	} else {
-		api.logger.Debugf("Receive new eth tx: %s", tx.GetHash().String())
 	}
-
-	return sendTransaction(api.api, tx)
+	return nil
 }
 
 func getTxByBlockInfoAndIndex[T uint64 | *types.Hash](api api.CoreAPI, getHeaderFn func(input T) (*types.BlockHeader, error), input T, idx hexutil.Uint) (*rpctypes.RPCTransaction, error) {
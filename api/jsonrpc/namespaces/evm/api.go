@@ -0,0 +1,122 @@
+package evm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+// Quantity is the number param of the dev APIs, which accepts both the JSON number and the hex string
+// like the other dev nodes.
+type Quantity uint64
+
+func (q *Quantity) UnmarshalJSON(input []byte) error {
+	if len(input) > 0 && input[0] == '"' {
+		var s string
+		if err := json.Unmarshal(input, &s); err != nil {
+			return err
+		}
+		v, err := hexutil.DecodeUint64(s)
+		if err != nil {
+			// also accept the decimal string
+			if v, err = strconv.ParseUint(s, 10, 64); err != nil {
+				return fmt.Errorf("invalid quantity %s", s)
+			}
+		}
+		*q = Quantity(v)
+		return nil
+	}
+	var v uint64
+	if err := json.Unmarshal(input, &v); err != nil {
+		return fmt.Errorf("invalid quantity %s", string(input))
+	}
+	*q = Quantity(v)
+	return nil
+}
+
+// DevAPI is the evm_ prefixed set of APIs which control the block production of the solo_dev consensus,
+// it is compatible with the dev APIs of Hardhat and Ganache.
+type DevAPI struct {
+	rep    *repo.Repo
+	api    api.CoreAPI
+	logger logrus.FieldLogger
+}
+
+func NewDevAPI(rep *repo.Repo, api api.CoreAPI, logger logrus.FieldLogger) *DevAPI {
+	return &DevAPI{rep: rep, api: api, logger: logger}
+}
+
+// Mine mines a block with the pending transactions, the block uses the timestamp in seconds if it is given.
+func (api *DevAPI) Mine(timestamp *Quantity) (string, error) {
+	api.logger.Debugf("evm_mine, timestamp: %v", timestamp)
+
+	var ts int64
+	if timestamp != nil {
+		ts = int64(*timestamp)
+	}
+	if err := api.api.Dev().Mine(ts); err != nil {
+		return "", err
+	}
+	return "0x0", nil
+}
+
+// IncreaseTime moves the time of the next blocks forward by the seconds, returns the total seconds moved.
+func (api *DevAPI) IncreaseTime(seconds Quantity) (int64, error) {
+	api.logger.Debugf("evm_increaseTime, seconds: %d", seconds)
+
+	return api.api.Dev().IncreaseTime(int64(seconds))
+}
+
+// SetNextBlockTimestamp sets the timestamp in seconds of the next block, the following blocks continue from it.
+func (api *DevAPI) SetNextBlockTimestamp(timestamp Quantity) error {
+	api.logger.Debugf("evm_setNextBlockTimestamp, timestamp: %d", timestamp)
+
+	return api.api.Dev().SetNextBlockTimestamp(int64(timestamp))
+}
+
+// Snapshot saves the current chain state, returns the snapshot id used by evm_revert.
+func (api *DevAPI) Snapshot() (hexutil.Uint64, error) {
+	api.logger.Debug("evm_snapshot")
+
+	id, err := api.api.Dev().Snapshot()
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(id), nil
+}
+
+// Revert rolls back the chain to the snapshot, the snapshot and the later snapshots can not be used again,
+// returns false if the snapshot does not exist.
+func (api *DevAPI) Revert(id Quantity) (bool, error) {
+	api.logger.Debugf("evm_revert, id: %d", id)
+
+	return api.api.Dev().Revert(uint64(id))
+}
+
+// SetAutomine enables or disables mining a block for each transaction.
+func (api *DevAPI) SetAutomine(enable bool) (bool, error) {
+	api.logger.Debugf("evm_setAutomine, enable: %v", enable)
+
+	if err := api.api.Dev().SetAutomine(enable); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetIntervalMining sets the interval in milliseconds of mining the pending transactions when the automine
+// is disabled, 0 disables the interval mining.
+func (api *DevAPI) SetIntervalMining(interval Quantity) (bool, error) {
+	api.logger.Debugf("evm_setIntervalMining, interval: %d", interval)
+
+	if err := api.api.Dev().SetIntervalMining(time.Duration(interval) * time.Millisecond); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -6,14 +6,16 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/samber/lo"
 	"github.com/sirupsen/logrus"
 
-	"github.com/axiomesh/axiom-kit/txpool"
+	commonpool "github.com/axiomesh/axiom-kit/txpool"
 	"github.com/axiomesh/axiom-kit/types"
 	"github.com/axiomesh/axiom-ledger/api/jsonrpc/namespaces/eth"
 	rpctypes "github.com/axiomesh/axiom-ledger/api/jsonrpc/types"
 	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	"github.com/axiomesh/axiom-ledger/internal/txpool"
 	"github.com/axiomesh/axiom-ledger/pkg/repo"
 )
 
@@ -51,12 +53,12 @@ func (api *TxPoolAPI) PendingTxCountFrom(addr common.Address) (any, error) {
 // convert account's txMeta to <nonce:RPCTransaction>
 // pending: All currently processable transactions, store in txpool with priorityIndex field
 // queued: Queued but non-processable transactions, store in txpool with parkingLotIndex field
-func (api *TxPoolAPI) formatTxMeta(meta *txpool.AccountMeta[types.Transaction, *types.Transaction]) ([]PoolTxContent, []PoolTxContent) {
+func (api *TxPoolAPI) formatTxMeta(meta *commonpool.AccountMeta[types.Transaction, *types.Transaction]) ([]PoolTxContent, []PoolTxContent) {
 	if len(meta.Txs) == 0 {
 		return nil, nil
 	}
 
-	format := func(info *txpool.TxInfo[types.Transaction, *types.Transaction]) PoolTxContent {
+	format := func(info *commonpool.TxInfo[types.Transaction, *types.Transaction]) PoolTxContent {
 		return PoolTxContent{
 			Nonce:       info.Tx.GetNonce(),
 			Transaction: eth.NewRPCTransaction(info.Tx, common.Hash{}, 0, 0),
@@ -68,7 +70,7 @@ func (api *TxPoolAPI) formatTxMeta(meta *txpool.AccountMeta[types.Transaction, *
 
 	pending := make([]PoolTxContent, 0)
 	queued := make([]PoolTxContent, 0)
-	lo.ForEach(meta.Txs, func(poolTx *txpool.TxInfo[types.Transaction, *types.Transaction], _ int) {
+	lo.ForEach(meta.Txs, func(poolTx *commonpool.TxInfo[types.Transaction, *types.Transaction], _ int) {
 		nonce := poolTx.Tx.GetNonce()
 		if nonce > meta.PendingNonce {
 			queued = append(queued, format(poolTx))
@@ -102,7 +104,7 @@ func (api *TxPoolAPI) SimpleContent() (any, error) {
 func (api *TxPoolAPI) getContent() ContentResponse {
 	data := api.api.TxPool().GetMeta(true)
 
-	txpoolMeta, ok := data.(*txpool.Meta[types.Transaction, *types.Transaction])
+	txpoolMeta, ok := data.(*commonpool.Meta[types.Transaction, *types.Transaction])
 	if !ok {
 		api.logger.Errorf("failed to get txpool meta")
 		return ContentResponse{}
@@ -134,7 +136,7 @@ func (api *TxPoolAPI) getContent() ContentResponse {
 func (api *TxPoolAPI) getSimpleContent() SimpleContentResponse {
 	data := api.api.TxPool().GetMeta(false)
 
-	txpoolMeta, ok := data.(*txpool.Meta[types.Transaction, *types.Transaction])
+	txpoolMeta, ok := data.(*commonpool.Meta[types.Transaction, *types.Transaction])
 	if !ok {
 		api.logger.Errorf("failed to get txpool meta")
 		return SimpleContentResponse{}
@@ -144,7 +146,7 @@ func (api *TxPoolAPI) getSimpleContent() SimpleContentResponse {
 	for account, accMeta := range txpoolMeta.Accounts {
 		pending := make([]TxByNonce, 0)
 		queued := make([]TxByNonce, 0)
-		lo.ForEach(accMeta.SimpleTxs, func(info *txpool.TxSimpleInfo, index int) {
+		lo.ForEach(accMeta.SimpleTxs, func(info *commonpool.TxSimpleInfo, index int) {
 			if info.Nonce > accMeta.PendingNonce {
 				queued = append(queued, TxByNonce{Nonce: info.Nonce, TxHash: info.Hash})
 			} else {
@@ -175,7 +177,7 @@ func (api *TxPoolAPI) ContentFrom(addr common.Address) (any, error) {
 		return nil, ErrNotStarted
 	}
 	data := api.api.TxPool().GetAccountMeta(addr.String(), true)
-	accountMeta, ok := data.(*txpool.AccountMeta[types.Transaction, *types.Transaction])
+	accountMeta, ok := data.(*commonpool.AccountMeta[types.Transaction, *types.Transaction])
 	if !ok {
 		err := errors.New("failed to get account meta")
 		api.logger.Error(err)
@@ -235,15 +237,90 @@ func (api *TxPoolAPI) Status() (any, error) {
 		return nil, ErrNotStarted
 	}
 	data := api.api.TxPool().GetMeta(false)
-	meta, ok := data.(*txpool.Meta[types.Transaction, *types.Transaction])
+	meta, ok := data.(*commonpool.Meta[types.Transaction, *types.Transaction])
 	if !ok {
 		err := errors.New("failed to get txpool meta")
 		api.logger.Error(err)
 		return nil, err
 	}
-	return StatusResponse{
-		Pending: meta.ReadyTxCount,
-		Queued:  meta.NotReadyTxCount,
-		Total:   meta.TxCount,
+	res := StatusResponse{
+		Pending:  meta.ReadyTxCount,
+		Queued:   meta.NotReadyTxCount,
+		Total:    meta.TxCount,
+		PoolSize: meta.TxCountLimit,
+	}
+	if quota := api.api.TxPool().GetQuota(); quota != nil {
+		res.AccountSlots = quota.AccountSlots
+		res.AccountQueue = quota.AccountQueue
+		res.GlobalQueue = quota.GlobalQueue
+		res.Evicted = quota.EvictedTxCount
+	}
+	return res, nil
+}
+
+// Dropped creates a subscription that is triggered each time transactions are dropped from the
+// transaction pool, the notification contains the reason why the transaction is dropped.
+// It is subscribed over websocket by txpool_subscribe with the subscription name "dropped",
+// e.g. {"jsonrpc":"2.0","id":1,"method":"txpool_subscribe","params":["dropped"]}, and the
+// notifications are sent by txpool_subscription.
+func (api *TxPoolAPI) Dropped(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		droppedTxsCh := make(chan []*txpool.DroppedTx, 128)
+		droppedTxsSub := api.api.Feed().SubscribeDroppedTxEvent(droppedTxsCh)
+
+		for {
+			select {
+			case droppedTxs := <-droppedTxsCh:
+				for _, tx := range droppedTxs {
+					err := notifier.Notify(rpcSub.ID, DroppedTxResponse{
+						Hash:       tx.Hash,
+						From:       tx.Account,
+						Nonce:      tx.Nonce,
+						Reason:     string(tx.Reason),
+						ReplacedBy: tx.ReplacedBy,
+						Timestamp:  tx.Timestamp,
+					})
+					if err != nil {
+						api.logger.Warn("notifier notify error", err)
+					}
+				}
+			case <-rpcSub.Err():
+				droppedTxsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				droppedTxsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// GetTxStatus returns the status of the transaction: pending, queued, batched, replaced, dropped, committed or unknown.
+func (api *TxPoolAPI) GetTxStatus(hash common.Hash) (any, error) {
+	txHash := types.NewHash(hash.Bytes())
+	if meta, err := api.api.Broker().GetTransactionMeta(txHash); err == nil && meta != nil {
+		return TxStatusResponse{
+			Status:      string(txpool.TxStatusCommitted),
+			BlockNumber: meta.BlockHeight,
+		}, nil
+	}
+
+	if !api.api.TxPool().IsStarted() {
+		return nil, ErrNotStarted
+	}
+	status := api.api.TxPool().GetTxStatus(txHash.String())
+	return TxStatusResponse{
+		Status:     string(status.Status),
+		Reason:     string(status.Reason),
+		ReplacedBy: status.ReplacedBy,
 	}, nil
 }
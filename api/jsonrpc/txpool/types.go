@@ -65,4 +65,26 @@ type StatusResponse struct {
 	Pending uint64 `json:"pending"`
 	Queued  uint64 `json:"queued"`
 	Total   uint64 `json:"total"`
+
+	PoolSize     uint64 `json:"poolSize,omitempty"`
+	AccountSlots uint64 `json:"accountSlots,omitempty"`
+	AccountQueue uint64 `json:"accountQueue,omitempty"`
+	GlobalQueue  uint64 `json:"globalQueue,omitempty"`
+	Evicted      uint64 `json:"evicted,omitempty"`
+}
+
+type DroppedTxResponse struct {
+	Hash       string `json:"hash"`
+	From       string `json:"from"`
+	Nonce      uint64 `json:"nonce"`
+	Reason     string `json:"reason"`
+	ReplacedBy string `json:"replacedBy,omitempty"`
+	Timestamp  int64  `json:"timestamp"`
+}
+
+type TxStatusResponse struct {
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+	ReplacedBy  string `json:"replacedBy,omitempty"`
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
 }
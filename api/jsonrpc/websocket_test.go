@@ -0,0 +1,124 @@
+package jsonrpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+type mockDroppedService struct{}
+
+func (s *mockDroppedService) Dropped(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		_ = notifier.Notify(sub.ID, "0x1")
+	}()
+	return sub, nil
+}
+
+func TestWebsocketSubscribeDropped(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	err := server.RegisterName("txpool", &mockDroppedService{})
+	require.Nil(t, err)
+
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+
+	client, err := rpc.DialWebsocket(context.Background(), "ws"+strings.TrimPrefix(httpServer.URL, "http"), "")
+	require.Nil(t, err)
+	defer client.Close()
+
+	// same as {"method":"txpool_subscribe","params":["dropped"]}
+	ch := make(chan string, 1)
+	sub, err := client.Subscribe(context.Background(), "txpool", ch, "dropped")
+	require.Nil(t, err)
+	defer sub.Unsubscribe()
+
+	select {
+	case res := <-ch:
+		assert.Equal(t, "0x1", res)
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("dropped notification timeout")
+	}
+}
+
+type mockChainIDService struct{}
+
+func (s *mockChainIDService) ChainId() string {
+	return "0x1"
+}
+
+type mockTraceService struct{}
+
+func (s *mockTraceService) TraceTransaction() string {
+	return "trace"
+}
+
+func TestWebsocketAuth(t *testing.T) {
+	cbs := newTestBrokerService(t, func(config *repo.JsonRPC) {
+		config.Auth = testAuthConfig()
+		config.Auth.APIKeys[0].Allowed = []string{"eth", "debug"}
+	})
+	require.Nil(t, cbs.authenticator.checkWebsocket())
+	err := cbs.registerWSAPIs([]rpc.API{
+		{Namespace: "eth", Service: &mockChainIDService{}},
+		{Namespace: "debug", Service: &mockTraceService{}},
+	})
+	require.Nil(t, err)
+
+	httpServer := httptest.NewServer(cbs.websocketHandler())
+	defer httpServer.Close()
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	dial := func(t *testing.T, options ...rpc.ClientOption) *rpc.Client {
+		client, err := rpc.DialOptions(context.Background(), url, options...)
+		require.Nil(t, err)
+		t.Cleanup(client.Close)
+		return client
+	}
+
+	t.Run("public", func(t *testing.T) {
+		client := dial(t)
+		var chainID, trace string
+		assert.Nil(t, client.Call(&chainID, "eth_chainId"))
+		assert.Equal(t, "0x1", chainID)
+		assert.ErrorContains(t, client.Call(&trace, "debug_traceTransaction"), "does not exist")
+	})
+
+	t.Run("api key", func(t *testing.T) {
+		client := dial(t, rpc.WithHeader(apiKeyHeader, "tracer-key"))
+		var chainID, trace string
+		assert.Nil(t, client.Call(&chainID, "eth_chainId"))
+		assert.Equal(t, "0x1", chainID)
+		assert.Nil(t, client.Call(&trace, "debug_traceTransaction"))
+		assert.Equal(t, "trace", trace)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		_, err := rpc.DialOptions(context.Background(), url, rpc.WithHeader(apiKeyHeader, "unknown-key"))
+		assert.NotNil(t, err)
+
+		unexpired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject: "admin",
+		}).SignedString(testJWTSecret)
+		require.Nil(t, err)
+		_, err = rpc.DialOptions(context.Background(), url, rpc.WithHeader("Authorization", "Bearer "+unexpired))
+		assert.NotNil(t, err)
+	})
+}
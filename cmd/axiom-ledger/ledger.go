@@ -81,6 +81,30 @@ var ledgerGetEpochStateArgs = struct {
 	Epoch uint64
 }{}
 
+var ledgerExportArgs = struct {
+	From       uint64
+	To         uint64
+	OutputFile string
+}{}
+
+var ledgerImportArgs = struct {
+	File string
+}{}
+
+var ledgerReplayArgs = struct {
+	From uint64
+	To   uint64
+}{}
+
+var ledgerSnapshotDumpArgs = struct {
+	TargetBlockNumber uint64
+	OutputFile        string
+}{}
+
+var ledgerSnapshotRestoreArgs = struct {
+	File string
+}{}
+
 var ledgerCMD = &cli.Command{
 	Name:  "ledger",
 	Usage: "The ledger manage commands",
@@ -179,6 +203,109 @@ var ledgerCMD = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "export",
+			Usage:  "Export blocks and receipts in the range to a block archive file",
+			Action: exportBlocks,
+			Flags: []cli.Flag{
+				&cli.Uint64Flag{
+					Name:        "from",
+					Usage:       "first block number to export, must be greater than 0",
+					Value:       1,
+					Destination: &ledgerExportArgs.From,
+					Required:    false,
+				},
+				&cli.Uint64Flag{
+					Name:        "to",
+					Usage:       "last block number to export, default is the latest block height",
+					Destination: &ledgerExportArgs.To,
+					Required:    false,
+				},
+				&cli.StringFlag{
+					Name:        "out",
+					Aliases:     []string{"o"},
+					Usage:       "block archive file path, must not exist",
+					Destination: &ledgerExportArgs.OutputFile,
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:   "import",
+			Usage:  "Import blocks from a block archive file, the blocks are re-executed and verified against the exported state root and block hash",
+			Action: importBlocks,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:        "file",
+					Aliases:     []string{"f"},
+					Usage:       "block archive file path",
+					Destination: &ledgerImportArgs.File,
+					Required:    true,
+				},
+				common.KeystorePasswordFlag(),
+			},
+		},
+		{
+			Name:   "replay",
+			Usage:  "Re-execute the blocks in the range and report the mismatches against the persisted blocks and receipts, the ledger is not modified",
+			Action: replayBlocks,
+			Flags: []cli.Flag{
+				&cli.Uint64Flag{
+					Name:        "from",
+					Usage:       "first block number to replay, must be greater than 0",
+					Value:       1,
+					Destination: &ledgerReplayArgs.From,
+					Required:    false,
+				},
+				&cli.Uint64Flag{
+					Name:        "to",
+					Usage:       "last block number to replay, default is the latest block height",
+					Destination: &ledgerReplayArgs.To,
+					Required:    false,
+				},
+			},
+		},
+		{
+			Name:  "snapshot",
+			Usage: "The state snapshot manage commands",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "dump",
+					Usage:  "Dump the account and storage tries at the target block to a state archive file",
+					Action: dumpSnapshot,
+					Flags: []cli.Flag{
+						&cli.Uint64Flag{
+							Name:        "target-block-number",
+							Aliases:     []string{"b"},
+							Usage:       "block number of the state, default is the latest block height",
+							Destination: &ledgerSnapshotDumpArgs.TargetBlockNumber,
+							Required:    false,
+						},
+						&cli.StringFlag{
+							Name:        "out",
+							Aliases:     []string{"o"},
+							Usage:       "state archive file path, must not exist",
+							Destination: &ledgerSnapshotDumpArgs.OutputFile,
+							Required:    true,
+						},
+					},
+				},
+				{
+					Name:   "restore",
+					Usage:  "Restore the state from a state archive file into an empty repo, the state root is verified against the dumped block header, then start the node with --snapshot",
+					Action: restoreSnapshot,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:        "file",
+							Aliases:     []string{"f"},
+							Usage:       "state archive file path",
+							Destination: &ledgerSnapshotRestoreArgs.File,
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:   "import-accounts",
 			Usage:  "used after generating the genesis block, where large number of accounts with preset balances are inserted into ledger. This process aims to assist testers in initializing accounts prior to conducting tests. The file should contain one Ethereum Hexadecimal Address per line.",
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	"github.com/axiomesh/axiom-kit/fileutil"
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/cmd/axiom-ledger/common"
+	"github.com/axiomesh/axiom-ledger/internal/app"
+	consensuscommon "github.com/axiomesh/axiom-ledger/internal/consensus/common"
+	"github.com/axiomesh/axiom-ledger/internal/executor"
+	"github.com/axiomesh/axiom-ledger/internal/ledger"
+	"github.com/axiomesh/axiom-ledger/internal/ledger/blockarchive"
+	"github.com/axiomesh/axiom-ledger/pkg/loggers"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+const archiveProgressInterval = 1000
+
+func exportBlocks(ctx *cli.Context) error {
+	r, err := common.PrepareRepo(ctx)
+	if err != nil {
+		return err
+	}
+	logger := loggers.Logger(loggers.App)
+
+	if fileutil.Exist(ledgerExportArgs.OutputFile) {
+		return errors.Errorf("output file %s already exists", ledgerExportArgs.OutputFile)
+	}
+
+	chainLedger, err := ledger.NewChainLedger(r, "")
+	if err != nil {
+		return fmt.Errorf("init chain ledger failed: %w", err)
+	}
+	from, to := ledgerExportArgs.From, ledgerExportArgs.To
+	latestHeight := chainLedger.GetChainMeta().Height
+	if to == 0 {
+		to = latestHeight
+	}
+	// the genesis block is generated from the genesis config and can not be re-executed
+	if from == 0 || from > to || to > latestHeight {
+		return errors.Errorf("invalid block range [%d, %d], the valid range is from 1 to %d", from, to, latestHeight)
+	}
+	genesisHeader, err := chainLedger.GetBlockHeader(r.GenesisConfig.EpochInfo.StartBlock)
+	if err != nil {
+		return fmt.Errorf("get genesis block failed: %w", err)
+	}
+
+	f, err := os.OpenFile(ledgerExportArgs.OutputFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := blockarchive.NewWriter(f, &blockarchive.Header{
+		ChainID:     r.GenesisConfig.ChainID,
+		GenesisHash: genesisHeader.Hash(),
+		From:        from,
+		To:          to,
+	})
+	if err != nil {
+		return err
+	}
+	for height := from; height <= to; height++ {
+		block, err := chainLedger.GetBlock(height)
+		if err != nil {
+			return fmt.Errorf("get block %d failed: %w", height, err)
+		}
+		receipts, err := chainLedger.GetBlockReceipts(height)
+		if err != nil {
+			return fmt.Errorf("get receipts of block %d failed: %w", height, err)
+		}
+		if err := w.WriteBlock(block, receipts); err != nil {
+			return err
+		}
+		if (height-from+1)%archiveProgressInterval == 0 {
+			logger.Infof("exported blocks to height %d", height)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	logger.Infof("export blocks from %d to %d into %s successfully", from, to, ledgerExportArgs.OutputFile)
+	return nil
+}
+
+func importBlocks(ctx *cli.Context) error {
+	r, err := common.PrepareRepoWithKeystore(ctx)
+	if err != nil {
+		return err
+	}
+	logger := loggers.Logger(loggers.App)
+
+	if r.Config.Executor.Type == repo.ExecTypeDev {
+		return errors.New("import blocks is not supported by the dev executor")
+	}
+
+	f, err := os.Open(ledgerImportArgs.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader, err := blockarchive.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("open block archive failed: %w", err)
+	}
+	header := reader.Header()
+	if header.ChainID != r.GenesisConfig.ChainID {
+		return errors.Errorf("chain id of block archive %d is not matched with the local chain id %d", header.ChainID, r.GenesisConfig.ChainID)
+	}
+
+	// the blocks are executed offline, so consensus and network are not needed
+	r.StartArgs.ReadonlyMode = true
+	appCtx, cancel := context.WithCancel(ctx.Context)
+	axm, err := app.NewAxiomLedgerWithoutConsensus(r, appCtx, cancel)
+	if err != nil {
+		return fmt.Errorf("init axiom-ledger failed: %w", err)
+	}
+	defer func() {
+		if err := axm.Stop(); err != nil {
+			logger.Errorf("stop axiom-ledger failed: %v", err)
+		}
+	}()
+
+	blockExecutor, ok := axm.BlockExecutor.(*executor.BlockExecutor)
+	if !ok {
+		return errors.New("block executor does not support rollback")
+	}
+
+	chainLedger := axm.ViewLedger.ChainLedger
+	genesisHeader, err := chainLedger.GetBlockHeader(r.GenesisConfig.EpochInfo.StartBlock)
+	if err != nil {
+		return fmt.Errorf("get genesis block failed: %w", err)
+	}
+	if genesisHeader.Hash().String() != header.GenesisHash.String() {
+		return errors.Errorf("genesis block of block archive %s is not matched with the local genesis block %s", header.GenesisHash, genesisHeader.Hash())
+	}
+	latestHeight := chainLedger.GetChainMeta().Height
+	if header.From > latestHeight+1 {
+		return errors.Errorf("block archive starts from block %d, but the local ledger is at block %d", header.From, latestHeight)
+	}
+
+	logger.Infof("start importing blocks from %d to %d, local ledger is at block %d", header.From, header.To, latestHeight)
+	imported := 0
+	for {
+		block, receipts, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// the existing blocks must be the same as the archive
+		if block.Height() <= latestHeight {
+			localHeader, err := chainLedger.GetBlockHeader(block.Height())
+			if err != nil {
+				return fmt.Errorf("get block %d failed: %w", block.Height(), err)
+			}
+			if localHeader.Hash().String() != block.Hash().String() {
+				return errors.Errorf("block %d of block archive is not matched with the local block, expect %s, got %s", block.Height(), localHeader.Hash(), block.Hash())
+			}
+			localReceipts, err := chainLedger.GetBlockReceipts(block.Height())
+			if err != nil {
+				return fmt.Errorf("get receipts of block %d failed: %w", block.Height(), err)
+			}
+			if err := verifyArchivedReceipts(block.Height(), receipts, localReceipts); err != nil {
+				return err
+			}
+			continue
+		}
+
+		executedBlock := &types.Block{
+			Header: &types.BlockHeader{
+				Epoch:          block.Header.Epoch,
+				Number:         block.Header.Number,
+				Timestamp:      block.Header.Timestamp,
+				ProposerNodeID: block.Header.ProposerNodeID,
+			},
+			Transactions: block.Transactions,
+		}
+		axm.BlockExecutor.ExecuteBlock(&consensuscommon.CommitEvent{Block: executedBlock})
+
+		// the block is persisted by the executor, roll it back if it is not the same as the archive
+		if err := verifyImportedBlock(chainLedger, block, receipts, executedBlock); err != nil {
+			if rollbackErr := blockExecutor.Rollback(block.Height() - 1); rollbackErr != nil {
+				return fmt.Errorf("%w, and rollback to block %d failed: %v", err, block.Height()-1, rollbackErr)
+			}
+			return fmt.Errorf("%w, the ledger has been rolled back to block %d", err, block.Height()-1)
+		}
+		imported++
+		if imported%archiveProgressInterval == 0 {
+			logger.Infof("imported blocks to height %d", block.Height())
+		}
+	}
+
+	logger.Infof("import %d blocks from %s successfully, latest block height is %d", imported, ledgerImportArgs.File, chainLedger.GetChainMeta().Height)
+	return nil
+}
+
+func verifyImportedBlock(chainLedger ledger.ChainLedger, block *types.Block, receipts []*types.Receipt, executedBlock *types.Block) error {
+	if executedBlock.Header.StateRoot.String() != block.Header.StateRoot.String() {
+		return errors.Errorf("state root of block %d is not matched, expect %s, got %s", block.Height(), block.Header.StateRoot, executedBlock.Header.StateRoot)
+	}
+	if executedBlock.Hash().String() != block.Hash().String() {
+		return errors.Errorf("hash of block %d is not matched, expect %s, got %s", block.Height(), block.Hash(), executedBlock.Hash())
+	}
+	executedReceipts, err := chainLedger.GetBlockReceipts(block.Height())
+	if err != nil {
+		return fmt.Errorf("get receipts of block %d failed: %w", block.Height(), err)
+	}
+	return verifyArchivedReceipts(block.Height(), receipts, executedReceipts)
+}
+
+func verifyArchivedReceipts(height uint64, archived []*types.Receipt, local []*types.Receipt) error {
+	if len(archived) != len(local) {
+		return errors.Errorf("receipts count of block %d is not matched, expect %d, got %d", height, len(local), len(archived))
+	}
+	for i := range archived {
+		if archived[i].Hash().String() != local[i].Hash().String() {
+			return errors.Errorf("receipt %d of block %d is not matched, expect %s, got %s", i, height, local[i].Hash(), archived[i].Hash())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/cmd/axiom-ledger/common"
+	"github.com/axiomesh/axiom-ledger/internal/chainstate"
+	"github.com/axiomesh/axiom-ledger/internal/executor"
+	syscommon "github.com/axiomesh/axiom-ledger/internal/executor/system/common"
+	"github.com/axiomesh/axiom-ledger/internal/executor/system/framework"
+	"github.com/axiomesh/axiom-ledger/internal/executor/system/framework/solidity/node_manager"
+	"github.com/axiomesh/axiom-ledger/internal/ledger"
+	"github.com/axiomesh/axiom-ledger/pkg/loggers"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+func replayBlocks(ctx *cli.Context) error {
+	r, err := common.PrepareRepo(ctx)
+	if err != nil {
+		return err
+	}
+	logger := loggers.Logger(loggers.App)
+
+	if r.Config.Executor.Type == repo.ExecTypeDev {
+		return errors.New("replay blocks is not supported by the dev executor")
+	}
+
+	from, to := ledgerReplayArgs.From, ledgerReplayArgs.To
+	if from == 0 {
+		return errors.New("the genesis block cannot be replayed, from must be greater than 0")
+	}
+	// the blocks are re-executed on the state of the parent block, all the writes are dropped after replaying
+	lg, err := ledger.NewReplayLedger(r, from-1)
+	if err != nil {
+		return err
+	}
+	latestHeight := lg.ChainLedger.GetChainMeta().Height
+	if to == 0 {
+		to = latestHeight
+	}
+	if from > to || to > latestHeight {
+		return errors.Errorf("invalid block range [%d, %d], the valid range is from 1 to %d", from, to, latestHeight)
+	}
+
+	// the epoch of the replaying block is read from the parent state, and will be updated by executor when turning into new epoch
+	parentHeader, err := lg.ChainLedger.GetBlockHeader(from - 1)
+	if err != nil {
+		return fmt.Errorf("get block %d failed: %w", from-1, err)
+	}
+	view, err := lg.StateLedger.NewView(parentHeader, false)
+	if err != nil {
+		return fmt.Errorf("get state at block %d failed: %w", from-1, err)
+	}
+	epochInfo, err := framework.EpochManagerBuildConfig.Build(syscommon.NewViewVMContext(view)).CurrentEpoch()
+	if err != nil {
+		return fmt.Errorf("get current epoch info: %w", err)
+	}
+	chainState := chainstate.NewChainState("", nil, nil, func(nodeID uint64) (*node_manager.NodeInfo, error) {
+		return nil, errors.New("node info is not available in replay")
+	}, func(p2pID string) (uint64, error) {
+		return 0, errors.New("node info is not available in replay")
+	}, func(epoch uint64) (*types.EpochInfo, error) {
+		return nil, errors.New("epoch info is not available in replay")
+	})
+	if err := chainState.UpdateByEpochInfo(epochInfo.ToTypesEpoch(), nil); err != nil {
+		return err
+	}
+	exec, err := executor.New(r, lg, chainState)
+	if err != nil {
+		return fmt.Errorf("init executor failed: %w", err)
+	}
+
+	logger.Infof("start replaying blocks from %d to %d", from, to)
+	mismatched := 0
+	for height := from; height <= to; height++ {
+		block, err := lg.ChainLedger.GetBlock(height)
+		if err != nil {
+			return fmt.Errorf("get block %d failed: %w", height, err)
+		}
+		receipts, err := lg.ChainLedger.GetBlockReceipts(height)
+		if err != nil {
+			return fmt.Errorf("get receipts of block %d failed: %w", height, err)
+		}
+		replayed, replayedReceipts, err := exec.ReplayBlock(block)
+		if err != nil {
+			return err
+		}
+
+		diffs := diffReplayedBlock(block, receipts, replayed, replayedReceipts)
+		if len(diffs) == 0 {
+			if (height-from+1)%archiveProgressInterval == 0 {
+				logger.Infof("replayed blocks to height %d", height)
+			}
+			continue
+		}
+		mismatched++
+		for _, diff := range diffs {
+			logger.Warnf("block %d mismatch: %s", height, diff)
+		}
+		// the following blocks are executed on a different state, so there is no need to compare them
+		if replayed.Header.StateRoot.String() != block.Header.StateRoot.String() {
+			return errors.Errorf("state diverges at block %d, replayed %d blocks", height, height-from+1)
+		}
+	}
+
+	if mismatched != 0 {
+		return errors.Errorf("replay blocks from %d to %d finished, %d blocks are mismatched", from, to, mismatched)
+	}
+	logger.Infof("replay blocks from %d to %d successfully, all the blocks are matched", from, to)
+	return nil
+}
+
+// diffReplayedBlock compares the execution results of the replayed block with the persisted one.
+func diffReplayedBlock(block *types.Block, receipts []*types.Receipt, replayed *types.Block, replayedReceipts []*types.Receipt) []string {
+	var diffs []string
+	if replayed.Header.StateRoot.String() != block.Header.StateRoot.String() {
+		diffs = append(diffs, fmt.Sprintf("state root expect %s, got %s", block.Header.StateRoot, replayed.Header.StateRoot))
+	}
+	if replayed.Header.ReceiptRoot.String() != block.Header.ReceiptRoot.String() {
+		diffs = append(diffs, fmt.Sprintf("receipt root expect %s, got %s", block.Header.ReceiptRoot, replayed.Header.ReceiptRoot))
+	}
+	if replayed.Header.GasUsed != block.Header.GasUsed {
+		diffs = append(diffs, fmt.Sprintf("gas used expect %d, got %d", block.Header.GasUsed, replayed.Header.GasUsed))
+	}
+	if len(replayedReceipts) != len(receipts) {
+		return append(diffs, fmt.Sprintf("receipt count expect %d, got %d", len(receipts), len(replayedReceipts)))
+	}
+
+	for i, receipt := range receipts {
+		replayedReceipt := replayedReceipts[i]
+		if receipt.Hash().String() == replayedReceipt.Hash().String() {
+			continue
+		}
+		prefix := fmt.Sprintf("receipt of tx %s", receipt.TxHash)
+		if replayedReceipt.Status != receipt.Status {
+			diffs = append(diffs, fmt.Sprintf("%s status expect %v, got %v", prefix, receipt.Status, replayedReceipt.Status))
+		}
+		if replayedReceipt.GasUsed != receipt.GasUsed {
+			diffs = append(diffs, fmt.Sprintf("%s gas used expect %d, got %d", prefix, receipt.GasUsed, replayedReceipt.GasUsed))
+		}
+		if !bytes.Equal(replayedReceipt.Ret, receipt.Ret) {
+			diffs = append(diffs, fmt.Sprintf("%s return data expect %x, got %x", prefix, receipt.Ret, replayedReceipt.Ret))
+		}
+		if len(replayedReceipt.EvmLogs) != len(receipt.EvmLogs) {
+			diffs = append(diffs, fmt.Sprintf("%s log count expect %d, got %d", prefix, len(receipt.EvmLogs), len(replayedReceipt.EvmLogs)))
+		}
+		if replayedReceipt.ContractAddress.String() != receipt.ContractAddress.String() {
+			diffs = append(diffs, fmt.Sprintf("%s contract address expect %s, got %s", prefix, receipt.ContractAddress, replayedReceipt.ContractAddress))
+		}
+		diffs = append(diffs, fmt.Sprintf("%s hash expect %s, got %s", prefix, receipt.Hash(), replayedReceipt.Hash()))
+	}
+	return diffs
+}
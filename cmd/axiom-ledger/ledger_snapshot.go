@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/urfave/cli/v2"
+
+	"github.com/axiomesh/axiom-bft/common/consensus"
+	"github.com/axiomesh/axiom-kit/fileutil"
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/cmd/axiom-ledger/common"
+	syscommon "github.com/axiomesh/axiom-ledger/internal/executor/system/common"
+	"github.com/axiomesh/axiom-ledger/internal/executor/system/framework"
+	"github.com/axiomesh/axiom-ledger/internal/executor/system/framework/solidity/node_manager"
+	"github.com/axiomesh/axiom-ledger/internal/ledger"
+	"github.com/axiomesh/axiom-ledger/internal/ledger/blockarchive"
+	"github.com/axiomesh/axiom-ledger/internal/storagemgr"
+	"github.com/axiomesh/axiom-ledger/pkg/loggers"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+const snapshotProgressInterval = 1000000
+
+func dumpSnapshot(ctx *cli.Context) error {
+	r, err := common.PrepareRepo(ctx)
+	if err != nil {
+		return err
+	}
+	logger := loggers.Logger(loggers.App)
+
+	if fileutil.Exist(ledgerSnapshotDumpArgs.OutputFile) {
+		return errors.Errorf("output file %s already exists", ledgerSnapshotDumpArgs.OutputFile)
+	}
+
+	chainLedger, err := ledger.NewChainLedger(r, "")
+	if err != nil {
+		return fmt.Errorf("init chain ledger failed: %w", err)
+	}
+	targetBlockNumber := ledgerSnapshotDumpArgs.TargetBlockNumber
+	if targetBlockNumber == 0 {
+		targetBlockNumber = chainLedger.GetChainMeta().Height
+	}
+	blockHeader, err := chainLedger.GetBlockHeader(targetBlockNumber)
+	if err != nil {
+		return fmt.Errorf("get block failed: %w", err)
+	}
+
+	stateLedger, err := ledger.NewStateLedger(r, "")
+	if err != nil {
+		return fmt.Errorf("init state ledger failed: %w", err)
+	}
+	if r.Config.Ledger.EnablePrune {
+		minHeight, maxHeight := stateLedger.GetHistoryRange()
+		if targetBlockNumber < minHeight || targetBlockNumber > maxHeight {
+			return errors.Errorf("This is a prune node, target-block-number %d must be within valid range, which is from %d to %d\n", targetBlockNumber, minHeight, maxHeight)
+		}
+	}
+
+	// the epoch and the validators at the target block are recorded for the node started from the snapshot
+	view, err := stateLedger.NewView(blockHeader, false)
+	if err != nil {
+		return fmt.Errorf("get state at block %d failed: %w", targetBlockNumber, err)
+	}
+	epochInfo, err := framework.EpochManagerBuildConfig.Build(syscommon.NewViewVMContext(view)).CurrentEpoch()
+	if err != nil {
+		return fmt.Errorf("get current epoch info: %w", err)
+	}
+	nodeInfos, _, err := framework.NodeManagerBuildConfig.Build(syscommon.NewViewVMContext(view)).GetActiveValidatorSet()
+	if err != nil {
+		return fmt.Errorf("get node info: %w", err)
+	}
+	snapshotMeta := &ledger.SnapshotMeta{
+		BlockHeader: blockHeader,
+		EpochInfo:   epochInfo.ToTypesEpoch(),
+		Nodes: &consensus.QuorumValidators{
+			Validators: lo.Map(nodeInfos, func(info node_manager.NodeInfo, _ int) *consensus.QuorumValidator {
+				return &consensus.QuorumValidator{
+					Id:     info.ID,
+					PeerId: info.P2PID,
+				}
+			}),
+		},
+	}
+	meta, err := snapshotMeta.Marshal()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(ledgerSnapshotDumpArgs.OutputFile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w, err := blockarchive.NewStateWriter(f, r.GenesisConfig.ChainID, meta)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("start dumping state at height %d, state root: %s", targetBlockNumber, blockHeader.StateRoot)
+	count := 0
+	if err := stateLedger.ExportTrie(blockHeader, func(key, value []byte) error {
+		count++
+		if count%snapshotProgressInterval == 0 {
+			logger.Infof("dumped %d state entries", count)
+		}
+		return w.Put(key, value)
+	}); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+
+	logger.Infof("dump %d state entries at height %d into %s successfully", count, targetBlockNumber, ledgerSnapshotDumpArgs.OutputFile)
+	return nil
+}
+
+func restoreSnapshot(ctx *cli.Context) error {
+	r, err := common.PrepareRepo(ctx)
+	if err != nil {
+		return err
+	}
+	logger := loggers.Logger(loggers.App)
+
+	if r.Config.Ledger.EnableArchive {
+		return errors.New("cannot restore snapshot with archive mode enabled, the state before the snapshot block is not available")
+	}
+	stateStoragePath := repo.GetStoragePath(r.RepoRoot, storagemgr.Ledger)
+	if fileutil.Exist(stateStoragePath) {
+		return errors.Errorf("state storage %s already exists, snapshot can only be restored into an empty repo", stateStoragePath)
+	}
+
+	f, err := os.Open(ledgerSnapshotRestoreArgs.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	reader, err := blockarchive.NewStateReader(f)
+	if err != nil {
+		return fmt.Errorf("open state archive failed: %w", err)
+	}
+	if reader.ChainID() != r.GenesisConfig.ChainID {
+		return errors.Errorf("chain id of state archive %d is not matched with the local chain id %d", reader.ChainID(), r.GenesisConfig.ChainID)
+	}
+	snapshotMeta := &ledger.SnapshotMeta{}
+	if err := snapshotMeta.Unmarshal(reader.Meta()); err != nil {
+		return fmt.Errorf("unmarshal snapshot meta failed: %w", err)
+	}
+
+	if err := verifySnapshotBlockHeader(r, snapshotMeta.BlockHeader); err != nil {
+		return err
+	}
+
+	stateStorage, err := storagemgr.OpenWithMetrics(stateStoragePath, storagemgr.Ledger)
+	if err != nil {
+		return fmt.Errorf("create stateDB: %w", err)
+	}
+	// the partially restored state must not be used to start the node
+	restored := false
+	defer func() {
+		_ = stateStorage.Close()
+		if !restored {
+			if err := os.RemoveAll(stateStoragePath); err != nil {
+				logger.Errorf("remove state storage %s failed: %v", stateStoragePath, err)
+			}
+		}
+	}()
+
+	logger.Infof("start restoring state at height %d, state root: %s", snapshotMeta.BlockHeader.Number, snapshotMeta.BlockHeader.StateRoot)
+	batch := stateStorage.NewBatch()
+	count := 0
+	for {
+		key, value, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		batch.Put(key, value)
+		if batch.Size() > maxBatchSize {
+			batch.Commit()
+			batch.Reset()
+		}
+		count++
+		if count%snapshotProgressInterval == 0 {
+			logger.Infof("restored %d state entries", count)
+		}
+	}
+	if err := ledger.PutTrieSnapshotMeta(batch, snapshotMeta); err != nil {
+		return err
+	}
+	batch.Commit()
+
+	// the storage is shared with the state ledger by path
+	stateLedger, err := ledger.NewStateLedger(r, "")
+	if err != nil {
+		return fmt.Errorf("init state ledger failed: %w", err)
+	}
+	verified, err := stateLedger.VerifyTrie(snapshotMeta.BlockHeader)
+	if err != nil {
+		return fmt.Errorf("verify state trie failed: %w", err)
+	}
+	if !verified {
+		return errors.Errorf("state root %s of block %d is not matched with the restored state", snapshotMeta.BlockHeader.StateRoot, snapshotMeta.BlockHeader.Number)
+	}
+	restored = true
+
+	logger.Infof("restore %d state entries at height %d successfully, please start the node with --snapshot", count, snapshotMeta.BlockHeader.Number)
+	return nil
+}
+
+// verifySnapshotBlockHeader checks the snapshot block header against the local block at the same height,
+// the header in the state archive is trusted only if the local chain does not reach the height.
+func verifySnapshotBlockHeader(r *repo.Repo, header *types.BlockHeader) error {
+	if !fileutil.Exist(repo.GetStoragePath(r.RepoRoot, storagemgr.BlockChain)) {
+		return nil
+	}
+	chainLedger, err := ledger.NewChainLedger(r, "")
+	if err != nil {
+		return fmt.Errorf("init chain ledger failed: %w", err)
+	}
+	defer chainLedger.Close()
+
+	if chainLedger.GetChainMeta().Height < header.Number {
+		return nil
+	}
+	localHeader, err := chainLedger.GetBlockHeader(header.Number)
+	if err != nil {
+		return fmt.Errorf("get block %d failed: %w", header.Number, err)
+	}
+	if localHeader.Hash().String() != header.Hash().String() {
+		return errors.Errorf("block %d of state archive is not matched with the local block, expect %s, got %s", header.Number, localHeader.Hash(), header.Hash())
+	}
+	if localHeader.StateRoot.String() != header.StateRoot.String() {
+		return errors.Errorf("state root of block %d is not matched with the local block, expect %s, got %s", header.Number, localHeader.StateRoot, header.StateRoot)
+	}
+	return nil
+}
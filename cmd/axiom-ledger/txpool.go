@@ -2,17 +2,25 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/time/rate"
 
 	"github.com/axiomesh/axiom-kit/fileutil"
 	"github.com/axiomesh/axiom-kit/types"
 	"github.com/axiomesh/axiom-ledger/cmd/axiom-ledger/common"
+	"github.com/axiomesh/axiom-ledger/internal/app"
+	"github.com/axiomesh/axiom-ledger/internal/ledger"
 	"github.com/axiomesh/axiom-ledger/internal/storagemgr"
 	"github.com/axiomesh/axiom-ledger/internal/txpool"
 	"github.com/axiomesh/axiom-ledger/pkg/repo"
@@ -20,6 +28,53 @@ import (
 
 var decodeTxPoolPath string
 
+var txRecordsFilterArgs = struct {
+	Remote   bool
+	From     cli.StringSlice
+	Hash     cli.StringSlice
+	NonceMin uint64
+	NonceMax uint64
+}{}
+
+var txRecordsReplayArgs = struct {
+	RPC     string
+	Rate    float64
+	Timeout time.Duration
+}{}
+
+var txRecordsFilterFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:        "remote",
+		Usage:       "use the records of the remote txs instead of the local txs",
+		Destination: &txRecordsFilterArgs.Remote,
+		Required:    false,
+	},
+	&cli.StringSliceFlag{
+		Name:        "from",
+		Usage:       "only the txs sent by the given accounts",
+		Destination: &txRecordsFilterArgs.From,
+		Required:    false,
+	},
+	&cli.StringSliceFlag{
+		Name:        "hash",
+		Usage:       "only the txs with the given hashes",
+		Destination: &txRecordsFilterArgs.Hash,
+		Required:    false,
+	},
+	&cli.Uint64Flag{
+		Name:        "nonce-min",
+		Usage:       "only the txs whose nonce is not lower than the given nonce",
+		Destination: &txRecordsFilterArgs.NonceMin,
+		Required:    false,
+	},
+	&cli.Uint64Flag{
+		Name:        "nonce-max",
+		Usage:       "only the txs whose nonce is not higher than the given nonce",
+		Destination: &txRecordsFilterArgs.NonceMax,
+		Required:    false,
+	},
+}
+
 var txpoolCMD = &cli.Command{
 	Name:  "txpool",
 	Usage: "The txpool manage commands",
@@ -27,7 +82,7 @@ var txpoolCMD = &cli.Command{
 		{
 			Name:  "txrecords",
 			Usage: "Get all txs in txrecords",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.StringFlag{
 					Name:        "path",
 					Aliases:     []string{"p"},
@@ -35,20 +90,118 @@ var txpoolCMD = &cli.Command{
 					Destination: &decodeTxPoolPath,
 					Required:    false,
 				},
-			},
+			}, txRecordsFilterFlags...),
 			Action: getAllTxRecords,
+			Subcommands: []*cli.Command{
+				{
+					Name:   "rewrite",
+					Usage:  "Rewrite txrecords with the filtered txs, the undecodable, duplicate and committed txs are dropped, the node must be stopped",
+					Flags:  txRecordsFilterFlags,
+					Action: rewriteTxRecords,
+				},
+				{
+					Name:  "replay",
+					Usage: "Resubmit the filtered txs in txrecords to the node by eth_sendRawTransaction",
+					Flags: append([]cli.Flag{
+						&cli.StringFlag{
+							Name:        "rpc",
+							Usage:       "json rpc address of the node, e.g. http://127.0.0.1:8881",
+							Destination: &txRecordsReplayArgs.RPC,
+							Required:    true,
+						},
+						&cli.Float64Flag{
+							Name:        "rate",
+							Usage:       "max txs submitted per second",
+							Destination: &txRecordsReplayArgs.Rate,
+							Value:       100,
+							Required:    false,
+						},
+						&cli.DurationFlag{
+							Name:        "timeout",
+							Usage:       "timeout of each request",
+							Destination: &txRecordsReplayArgs.Timeout,
+							Value:       5 * time.Second,
+							Required:    false,
+						},
+					}, txRecordsFilterFlags...),
+					Action: replayTxRecords,
+				},
+			},
 		},
 	},
 }
 
+type txRecord struct {
+	raw []byte
+	tx  *types.Transaction
+}
+
+func getTxRecordsPath(r *repo.Repo) (string, error) {
+	fileName := txpool.TxRecordsFile
+	if txRecordsFilterArgs.Remote {
+		fileName = txpool.TxRemoteRecordsFile
+	}
+	p := path.Join(repo.GetStoragePath(r.RepoRoot), storagemgr.TxPool, fileName)
+	if !fileutil.Exist(p) {
+		return "", fmt.Errorf("axiom-ledger is not starting, please run axiom-ledger first, " + p)
+	}
+	return p, nil
+}
+
+// loadTxRecords returns the txs matched by the filter flags, and the number of the undecodable records.
+func loadTxRecords(ctx *cli.Context, p string) ([]*txRecord, int, error) {
+	raws, err := txpool.GetAllTxRecords(p)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	from := make(map[string]struct{})
+	for _, addr := range txRecordsFilterArgs.From.Value() {
+		from[strings.ToLower(addr)] = struct{}{}
+	}
+	hashes := make(map[string]struct{})
+	for _, hash := range txRecordsFilterArgs.Hash.Value() {
+		hashes[strings.ToLower(hash)] = struct{}{}
+	}
+
+	var (
+		records   []*txRecord
+		undecoded int
+		checkMax  = ctx.IsSet("nonce-max")
+		checkFrom = len(from) > 0
+		checkHash = len(hashes) > 0
+	)
+	for _, raw := range raws {
+		tx := &types.Transaction{}
+		if err = tx.RbftUnmarshal(raw); err != nil {
+			undecoded++
+			continue
+		}
+		if checkFrom {
+			if _, ok := from[strings.ToLower(tx.RbftGetFrom())]; !ok {
+				continue
+			}
+		}
+		if checkHash {
+			if _, ok := hashes[strings.ToLower(tx.RbftGetTxHash())]; !ok {
+				continue
+			}
+		}
+		if tx.GetNonce() < txRecordsFilterArgs.NonceMin || (checkMax && tx.GetNonce() > txRecordsFilterArgs.NonceMax) {
+			continue
+		}
+		records = append(records, &txRecord{raw: raw, tx: tx})
+	}
+	return records, undecoded, nil
+}
+
 func getAllTxRecords(ctx *cli.Context) error {
 	r, err := common.PrepareRepo(ctx)
 	if err != nil {
 		return err
 	}
-	p := path.Join(repo.GetStoragePath(r.RepoRoot), storagemgr.TxPool, txpool.TxRecordsFile)
-	if !fileutil.Exist(p) {
-		err = fmt.Errorf("axiom-ledger is not starting, please run axiom-ledger first, " + p)
+	p, err := getTxRecordsPath(r)
+	if err != nil {
 		return err
 	}
 
@@ -80,19 +233,14 @@ func getAllTxRecords(ctx *cli.Context) error {
 		_ = file.Close()
 	}()
 
-	records, err := txpool.GetAllTxRecords(p)
+	now := time.Now()
+	records, _, err := loadTxRecords(ctx, p)
 	if err != nil {
 		return err
 	}
 
-	now := time.Now()
 	for _, record := range records {
-		tx := &types.Transaction{}
-		err = tx.RbftUnmarshal(record)
-		if err != nil {
-			continue
-		}
-		data, err := tx.MarshalJSON()
+		data, err := record.tx.MarshalJSON()
 		if err != nil {
 			return err
 		}
@@ -110,3 +258,113 @@ func getAllTxRecords(ctx *cli.Context) error {
 
 	return nil
 }
+
+func rewriteTxRecords(ctx *cli.Context) error {
+	r, err := common.PrepareRepo(ctx)
+	if err != nil {
+		return err
+	}
+	p, err := getTxRecordsPath(r)
+	if err != nil {
+		return err
+	}
+	records, undecoded, err := loadTxRecords(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if err = app.PrepareAxiomLedger(r); err != nil {
+		return err
+	}
+	lg, err := ledger.NewLedger(r)
+	if err != nil {
+		return fmt.Errorf("init ledger failed, please stop the node first: %w", err)
+	}
+	defer lg.Close()
+
+	var (
+		duplicated int
+		committed  int
+		seen       = make(map[string]struct{})
+		nonces     = make(map[string]uint64)
+		kept       = make([][]byte, 0, len(records))
+	)
+	for _, record := range records {
+		hash := record.tx.RbftGetTxHash()
+		if _, ok := seen[hash]; ok {
+			duplicated++
+			continue
+		}
+		seen[hash] = struct{}{}
+
+		from := record.tx.RbftGetFrom()
+		nonce, ok := nonces[from]
+		if !ok {
+			nonce = lg.StateLedger.GetNonce(types.NewAddressByStr(from))
+			nonces[from] = nonce
+		}
+		if record.tx.GetNonce() < nonce {
+			committed++
+			continue
+		}
+		kept = append(kept, record.raw)
+	}
+
+	if err = txpool.WriteTxRecords(p, kept); err != nil {
+		return err
+	}
+	fmt.Printf("success rewrite txrecords %s, kept: %d, dropped undecodable: %d, duplicated: %d, committed: %d\n",
+		p, len(kept), undecoded, duplicated, committed)
+	return nil
+}
+
+func replayTxRecords(ctx *cli.Context) error {
+	r, err := common.PrepareRepo(ctx)
+	if err != nil {
+		return err
+	}
+	p, err := getTxRecordsPath(r)
+	if err != nil {
+		return err
+	}
+	records, _, err := loadTxRecords(ctx, p)
+	if err != nil {
+		return err
+	}
+	if txRecordsReplayArgs.Rate <= 0 {
+		return errors.New("rate must be positive")
+	}
+
+	client, err := rpc.DialContext(ctx.Context, txRecordsReplayArgs.RPC)
+	if err != nil {
+		return fmt.Errorf("dial %s failed: %w", txRecordsReplayArgs.RPC, err)
+	}
+	defer client.Close()
+
+	limiter := rate.NewLimiter(rate.Limit(txRecordsReplayArgs.Rate), 1)
+	now := time.Now()
+	var failed int
+	for _, record := range records {
+		if err = limiter.Wait(ctx.Context); err != nil {
+			return err
+		}
+		if err = sendRawTransaction(ctx.Context, client, record.tx); err != nil {
+			failed++
+			fmt.Printf("failed to replay tx %s[from: %s, nonce: %d]: %s\n", record.tx.RbftGetTxHash(), record.tx.RbftGetFrom(), record.tx.GetNonce(), err)
+		}
+	}
+	fmt.Printf("success replay txrecords, total: %d, succeeded: %d, failed: %d, cost: %s\n",
+		len(records), len(records)-failed, failed, time.Since(now))
+	return nil
+}
+
+func sendRawTransaction(ctx context.Context, client *rpc.Client, tx *types.Transaction) error {
+	data, err := tx.Marshal()
+	if err != nil {
+		return err
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, txRecordsReplayArgs.Timeout)
+	defer cancel()
+	var hash string
+	return client.CallContext(reqCtx, &hash, "eth_sendRawTransaction", hexutil.Bytes(data))
+}
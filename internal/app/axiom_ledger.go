@@ -54,9 +54,14 @@ type AxiomLedger struct {
 	BloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	Indexer       *indexer.ChainIndexer
 
-	epochStore kv.Storage
-	snapMeta   *snapMeta
-	StopCh     chan error
+	InternalTxIndexer *indexer.InternalTxIndexer
+	// accounts whose unsigned txs are accepted, only set with the solo_dev consensus
+	ImpersonatedAccounts *common.ImpersonatedAccounts
+
+	epochStore   kv.Storage
+	snapMeta     *snapMeta
+	snapProgress *snapSyncProgress
+	StopCh       chan error
 }
 
 func NewAxiomLedger(rep *repo.Repo, ctx context.Context, cancel context.CancelFunc) (*AxiomLedger, error) {
@@ -89,6 +94,11 @@ func NewAxiomLedger(rep *repo.Repo, ctx context.Context, cancel context.CancelFu
 		txpoolConf := txpool2.Config{
 			Logger:                 loggers.Logger(loggers.TxPool),
 			PoolSize:               poolConf.PoolSize,
+			AccountSlots:           poolConf.AccountSlots,
+			AccountQueue:           poolConf.AccountQueue,
+			GlobalQueue:            poolConf.GlobalQueue,
+			MaxBundles:             poolConf.MaxBundles,
+			MaxBundleBlockDistance: poolConf.MaxBundleBlockDistance,
 			ToleranceTime:          poolConf.ToleranceTime.ToDuration(),
 			ToleranceRemoveTime:    poolConf.ToleranceRemoveTime.ToDuration(),
 			ToleranceNonceGap:      poolConf.ToleranceNonceGap,
@@ -96,6 +106,7 @@ func NewAxiomLedger(rep *repo.Repo, ctx context.Context, cancel context.CancelFu
 			GetAccountNonce:        fn,
 			GetAccountBalance:      getBalanceFn,
 			EnableLocalsPersist:    poolConf.EnableLocalsPersist,
+			EnableRemotesPersist:   poolConf.EnableRemotesPersist,
 			RepoRoot:               rep.RepoRoot,
 			RotateTxLocalsInterval: poolConf.RotateTxLocalsInterval.ToDuration(),
 			PriceLimit:             priceLimit.ToBigInt().Uint64(),
@@ -106,6 +117,15 @@ func NewAxiomLedger(rep *repo.Repo, ctx context.Context, cancel context.CancelFu
 		if err != nil {
 			return nil, fmt.Errorf("new txpool failed: %w", err)
 		}
+		if blockExecutor, ok := axm.BlockExecutor.(*executor.BlockExecutor); ok {
+			if bundleTracker, ok := axm.TxPool.(executor.BundleTracker); ok {
+				blockExecutor.SetBundleTracker(bundleTracker)
+			}
+		}
+
+		if rep.Config.IsImpersonationSupported() {
+			axm.ImpersonatedAccounts = common.NewImpersonatedAccounts()
+		}
 
 		genesisBlockHeader, err := axm.ViewLedger.ChainLedger.GetBlockHeader(axm.Repo.GenesisConfig.EpochInfo.StartBlock)
 		if err != nil {
@@ -130,6 +150,14 @@ func NewAxiomLedger(rep *repo.Repo, ctx context.Context, cancel context.CancelFu
 			common.WithGetAccountNonceFunc(func(address *types.Address) uint64 {
 				return axm.ViewLedger.NewView().StateLedger.GetNonce(address)
 			}),
+			common.WithRollbackFunc(func(height uint64) error {
+				blockExecutor, ok := axm.BlockExecutor.(*executor.BlockExecutor)
+				if !ok {
+					return errors.New("block executor does not support rollback")
+				}
+				return blockExecutor.Rollback(height)
+			}),
+			common.WithImpersonatedAccounts(axm.ImpersonatedAccounts),
 			common.WithBlockSync(axm.Sync),
 			common.WithEpochStore(axm.epochStore),
 			common.WithNotifyStopCh(func(err error) {
@@ -169,9 +197,13 @@ func NewAxiomLedgerWithoutConsensus(rep *repo.Repo, ctx context.Context, cancel
 	var vl *ledger.Ledger
 	// 0. load ledger
 	var snap *snapMeta
+	var snapProgress *snapSyncProgress
 	verifiedCh := make(chan bool, 1)
 
 	if rep.StartArgs.SnapshotMode {
+		if rep.Config.Ledger.EnableArchive {
+			return nil, errors.New("cannot start snap mode with archive mode enabled, the state before the snapshot block is unavailable")
+		}
 		stateLg, err := storagemgr.OpenWithMetrics(repo.GetStoragePath(rep.RepoRoot, storagemgr.Ledger), storagemgr.Ledger)
 		if err != nil {
 			return nil, err
@@ -205,28 +237,45 @@ func NewAxiomLedgerWithoutConsensus(rep *repo.Repo, ctx context.Context, cancel
 			return nil, fmt.Errorf("snap-sync generate snapshot failed: %w", err)
 		}
 
-		// 3. verify whether trie snapshot is legal (async with snap sync)
-		go func(resultCh chan bool) {
-			now := time.Now()
-			verified, err := vl.StateLedger.VerifyTrie(meta.BlockHeader)
-			if err != nil {
-				resultCh <- false
-				return
-			}
-			logger.WithFields(logrus.Fields{
-				"cost":   time.Since(now),
-				"height": meta.BlockHeader.Number,
-				"result": verified,
-			}).Info("end verify trie snapshot")
-			resultCh <- verified
-		}(verifiedCh)
-
-		// 4. load snap meta of peers, epoch, etc.
+		// 3. load snap meta of peers, epoch, etc.
 		snap, err = loadSnapMeta(vl, meta.BlockHeader, rep.P2PKeystore.P2PID(), rep.SyncArgs)
 		if err != nil {
 			return nil, err
 		}
 
+		// 4. load snap sync progress persisted by the interrupted snap sync
+		snapProgress, err = loadSnapSyncProgress(rep.RepoRoot, snap)
+		if err != nil {
+			return nil, fmt.Errorf("load snap sync progress: %w", err)
+		}
+
+		// 5. verify whether trie snapshot is legal (async with snap sync)
+		if snapProgress.trieVerified() {
+			logger.WithFields(logrus.Fields{
+				"height": meta.BlockHeader.Number,
+			}).Info("trie snapshot has been verified")
+			verifiedCh <- true
+		} else {
+			go func(resultCh chan bool) {
+				now := time.Now()
+				verified, err := vl.StateLedger.VerifyTrie(meta.BlockHeader)
+				if err != nil {
+					resultCh <- false
+					return
+				}
+				logger.WithFields(logrus.Fields{
+					"cost":   time.Since(now),
+					"height": meta.BlockHeader.Number,
+					"result": verified,
+				}).Info("end verify trie snapshot")
+				if verified {
+					if err := snapProgress.markTrieVerified(); err != nil {
+						logger.Errorf("persist trie verified progress failed: %v", err)
+					}
+				}
+				resultCh <- verified
+			}(verifiedCh)
+		}
 	} else {
 		rwLdg, err = ledger.NewLedger(rep)
 		if err != nil {
@@ -298,9 +347,10 @@ func NewAxiomLedgerWithoutConsensus(rep *repo.Repo, ctx context.Context, cancel
 		Network:    net,
 		Sync:       syncMgr,
 
-		snapMeta:   snap,
-		epochStore: epochStore,
-		StopCh:     make(chan error, 1),
+		snapMeta:     snap,
+		snapProgress: snapProgress,
+		epochStore:   epochStore,
+		StopCh:       make(chan error, 1),
 	}
 
 	// start p2p network
@@ -321,6 +371,7 @@ func NewAxiomLedgerWithoutConsensus(rep *repo.Repo, ctx context.Context, cancel
 				axm.logger.WithFields(logrus.Fields{
 					"start height":  latestHeight,
 					"target height": axm.snapMeta.snapBlockHeader.Number,
+					"resumed":       axm.snapProgress.resumed,
 				}).Info("start snap sync")
 
 				// 1. prepare snap sync info(including epoch state which will be persisted、last sync checkpoint)
@@ -347,16 +398,38 @@ func NewAxiomLedgerWithoutConsensus(rep *repo.Repo, ctx context.Context, cancel
 					"duration":      time.Since(start),
 				}).Info("end snap sync")
 			}
+
+			// snap sync is finished, the progress is useless
+			if err = repo.RemoveSnapSyncProgress(axm.Repo.RepoRoot); err != nil {
+				return nil, err
+			}
 		}
 
 		axm.ViewLedger.SnapMeta.Store(ledger.SnapInfo{Status: false, SnapBlockHeader: nil})
 	}
 
+	if rep.Config.Ledger.EnableInternalTxIndexer {
+		internalTxStorage, err := storagemgr.OpenWithMetrics(repo.GetStoragePath(rep.RepoRoot, storagemgr.InternalTx), storagemgr.InternalTx)
+		if err != nil {
+			return nil, err
+		}
+		axm.InternalTxIndexer = indexer.NewInternalTxIndexer(internalTxStorage)
+		// drop the internal txs of the blocks which have been rolled back
+		if err := axm.InternalTxIndexer.Rollback(rwLdg.ChainLedger.GetChainMeta().Height); err != nil {
+			return nil, fmt.Errorf("rollback internal tx indexer: %w", err)
+		}
+	}
+
 	var txExec executor.Executor
 	if rep.Config.Executor.Type == repo.ExecTypeDev {
 		txExec, err = devexecutor.New(loggers.Logger(loggers.Executor))
 	} else {
-		txExec, err = executor.New(rep, rwLdg, axm.ChainState)
+		var blockExecutor *executor.BlockExecutor
+		blockExecutor, err = executor.New(rep, rwLdg, axm.ChainState)
+		if err == nil && axm.InternalTxIndexer != nil {
+			blockExecutor.SetInternalTxIndexer(axm.InternalTxIndexer)
+		}
+		txExec = blockExecutor
 	}
 	if err != nil {
 		return nil, fmt.Errorf("create BlockExecutor: %w", err)
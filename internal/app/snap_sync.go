@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/axiomesh/axiom-ledger/internal/executor/system/framework/solidity/node_manager"
@@ -78,6 +79,38 @@ func loadSnapMeta(lg *ledger.Ledger, header *types.BlockHeader, selfPeerId strin
 	}, nil
 }
 
+// snapSyncProgress wraps the snap sync progress persisted in the repo,
+// it is read by the snap sync task and updated by the trie verification concurrently.
+type snapSyncProgress struct {
+	lock     sync.Mutex
+	repoRoot string
+	resumed  bool
+	progress *repo.SnapSyncProgress
+}
+
+// loadSnapSyncProgress resumes the persisted progress if it belongs to the same snap target,
+// otherwise a fresh progress is persisted.
+func loadSnapSyncProgress(repoRoot string, meta *snapMeta) (*snapSyncProgress, error) {
+	progress, resumed, err := repo.LoadSnapSyncProgress(repoRoot, meta.snapBlockHeader.Number, meta.snapBlockHeader.Hash().String(), meta.snapPersistEpoch)
+	if err != nil {
+		return nil, err
+	}
+	return &snapSyncProgress{repoRoot: repoRoot, resumed: resumed, progress: progress}, nil
+}
+
+func (p *snapSyncProgress) trieVerified() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.progress.TrieVerified
+}
+
+func (p *snapSyncProgress) markTrieVerified() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.progress.TrieVerified = true
+	return repo.WriteSnapSyncProgress(p.repoRoot, p.progress)
+}
+
 func (axm *AxiomLedger) prepareSnapSync(latestHeight uint64) (*common.PrepareData, *consensus.SignedCheckpoint, error) {
 	// 1. switch to snapshot mode
 	err := axm.Sync.SwitchMode(common.SyncModeSnapshot)
@@ -228,5 +261,6 @@ func (axm *AxiomLedger) genSnapSyncParams(peers []*common.Node, startHeight, tar
 		TargetHeight:     targetHeight,
 		QuorumCheckpoint: quorumCkpt,
 		EpochChanges:     epochChanges,
+		Resumed:          axm.snapProgress.resumed,
 	}
 }
@@ -0,0 +1,41 @@
+package components
+
+import (
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+var impersonationS = big.NewInt(1)
+
+// ImpersonationSignature returns the signature of the unsigned tx sent by the impersonated account,
+// which is v = 0, r = sender and s = 1 like the other dev nodes.
+func ImpersonationSignature(from ethcommon.Address) (v, r, s *big.Int) {
+	return big.NewInt(0), new(big.Int).SetBytes(from.Bytes()), new(big.Int).Set(impersonationS)
+}
+
+// ImpersonationSender returns the sender carried by the impersonation signature of the tx.
+func ImpersonationSender(tx *types.Transaction) (ethcommon.Address, bool) {
+	v, r, s := tx.GetRawSignature()
+	if v == nil || r == nil || s == nil || v.Sign() != 0 || s.Cmp(impersonationS) != 0 || r.BitLen() > 8*ethcommon.AddressLength {
+		return ethcommon.Address{}, false
+	}
+	return ethcommon.BigToAddress(r), true
+}
+
+// RestoreImpersonatedSender sets the sender of the decoded impersonated tx, which can not be recovered
+// from the signature. It is only safe for the txs accepted by the solo_dev node, which rejects any other
+// tx carrying the impersonation signature.
+func RestoreImpersonatedSender(tx *types.Transaction) bool {
+	from, ok := ImpersonationSender(tx)
+	if !ok {
+		return false
+	}
+	// FromCallArgs is the only way to set the sender without the signature
+	inner := tx.Inner
+	tx.FromCallArgs(types.CallArgs{From: &from})
+	tx.Inner = inner
+	return true
+}
@@ -55,6 +55,13 @@ func VerifyInsufficientBalance[T any, Constraint types.TXConstraint[T]](tx *T, g
 	return nil
 }
 
+// BaseFee returns the base fee of the executed blocks, the executor charges the effective
+// gas price of the txs under it, the txpool and the precheck must use it to calculate the
+// effective tip of the txs.
+func BaseFee() *big.Int {
+	return big.NewInt(0)
+}
+
 func CalcTxsMerkleRoot(txs []*types.Transaction) (*types.Hash, error) {
 	hash, err := calcMerkleRoot(lo.Map(txs, func(item *types.Transaction, index int) merkletree.Content {
 		return item.GetHash()
@@ -30,6 +30,8 @@ type Config struct {
 	GetBlockHeaderFunc func(height uint64) (*types.BlockHeader, error)
 	GetAccountBalance  func(address string) *big.Int
 	GetAccountNonce    func(address *types.Address) uint64
+	Rollback           func(height uint64) error
+	Impersonated       *ImpersonatedAccounts
 	NotifyStop         func(err error)
 	EpochStore         kv.Storage
 }
@@ -114,6 +116,18 @@ func WithGetAccountNonceFunc(f func(address *types.Address) uint64) Option {
 	}
 }
 
+func WithRollbackFunc(f func(height uint64) error) Option {
+	return func(config *Config) {
+		config.Rollback = f
+	}
+}
+
+func WithImpersonatedAccounts(accounts *ImpersonatedAccounts) Option {
+	return func(config *Config) {
+		config.Impersonated = accounts
+	}
+}
+
 func WithEpochStore(epochStore kv.Storage) Option {
 	return func(config *Config) {
 		config.EpochStore = epochStore
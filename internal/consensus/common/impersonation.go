@@ -0,0 +1,75 @@
+package common
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/components"
+)
+
+// ImpersonatedAccounts is the set of the accounts whose unsigned txs are accepted in the dev mode.
+type ImpersonatedAccounts struct {
+	lock     sync.RWMutex
+	accounts map[string]struct{}
+}
+
+func NewImpersonatedAccounts() *ImpersonatedAccounts {
+	return &ImpersonatedAccounts{
+		accounts: make(map[string]struct{}),
+	}
+}
+
+func (a *ImpersonatedAccounts) Add(address string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.accounts[strings.ToLower(address)] = struct{}{}
+}
+
+func (a *ImpersonatedAccounts) Remove(address string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.accounts, strings.ToLower(address))
+}
+
+func (a *ImpersonatedAccounts) Has(address string) bool {
+	if a == nil {
+		return false
+	}
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	_, ok := a.accounts[strings.ToLower(address)]
+	return ok
+}
+
+// NewImpersonatedTx creates the unsigned tx sent by the impersonated account. The signature is replaced
+// by r = sender and s = 1 like the other dev nodes, which keeps the tx hashes of the senders distinct,
+// the sender can not be recovered from the signature and is restored by components.RestoreImpersonatedSender
+// once the tx is decoded.
+func NewImpersonatedTx(from ethcommon.Address, inner *types.DynamicFeeTx) *types.Transaction {
+	inner.V, inner.R, inner.S = components.ImpersonationSignature(from)
+
+	tx := &types.Transaction{}
+	// FromCallArgs is the only way to set the sender without the signature
+	tx.FromCallArgs(types.CallArgs{From: &from})
+	tx.Inner = inner
+	tx.Time = time.Now()
+	return tx
+}
+
+// IsImpersonatedTx reports whether the tx carries the impersonation signature of its sender,
+// the tx whose sender is recovered from the impersonation signature is not impersonated.
+func IsImpersonatedTx(tx *types.Transaction) bool {
+	sender, ok := components.ImpersonationSender(tx)
+	if !ok {
+		return false
+	}
+	from := tx.GetFrom()
+	if from == nil {
+		return false
+	}
+	return from.ETHAddress() == sender
+}
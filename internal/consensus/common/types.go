@@ -49,6 +49,11 @@ type TxWithResp struct {
 	Tx      *types.Transaction
 	CheckCh chan *TxResp
 	PoolCh  chan *TxResp
+
+	// Private means the tx is only added to the local txpool and never broadcast,
+	// MaxBlock is the max block height the private tx can be included in, 0 means no limit.
+	Private  bool
+	MaxBlock uint64
 }
 
 type TxResp struct {
@@ -24,6 +24,14 @@ type Consensus interface {
 	// Prepare means send transaction to the consensus engine
 	Prepare(tx *types.Transaction) error
 
+	// PreparePrivate means send transaction to the local txpool only, the transaction is never broadcast
+	// and it is dropped once the chain height reaches maxBlock, 0 means no limit
+	PreparePrivate(tx *types.Transaction, maxBlock uint64) error
+
+	// PrepareBundle means precheck the transactions and send them as a bundle to the local txpool,
+	// the bundle is never broadcast and it is dropped once the chain height reaches maxBlock
+	PrepareBundle(txs []*types.Transaction, maxBlock uint64) (string, error)
+
 	// Commit recv blocks form Consensus and commit it by consensus
 	Commit() chan *common.CommitEvent
 
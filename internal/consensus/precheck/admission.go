@@ -0,0 +1,206 @@
+package precheck
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+// AdmissionRule is a configurable rule which decides whether a tx can be admitted to the txpool,
+// it is evaluated after the signature is verified, so the sender of the tx is trusted.
+type AdmissionRule interface {
+	Name() string
+
+	// Check returns the rejection error if the tx is not admitted
+	Check(tx *types.Transaction) error
+}
+
+var (
+	_ AdmissionRule = (*contractCreationDenyRule)(nil)
+	_ AdmissionRule = (*calldataSizeRule)(nil)
+	_ AdmissionRule = (*toAllowRule)(nil)
+	_ AdmissionRule = (*toDenyRule)(nil)
+	_ AdmissionRule = (*minPriorityFeeRule)(nil)
+)
+
+// newAdmissionRules builds the enabled admission rules from the config.
+func newAdmissionRules(cfg repo.TxAdmission, baseFeeFn func() *big.Int) ([]AdmissionRule, error) {
+	var rules []AdmissionRule
+
+	if len(cfg.ContractCreationDenyList) > 0 {
+		senders, err := parseAddressSet(cfg.ContractCreationDenyList)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid contract_creation_deny_list")
+		}
+		rules = append(rules, &contractCreationDenyRule{senders: senders})
+	}
+
+	if cfg.MaxCalldataSize > 0 || len(cfg.SenderMaxCalldataSize) > 0 {
+		rule := &calldataSizeRule{
+			maxSize:       cfg.MaxCalldataSize,
+			senderMaxSize: make(map[string]uint64, len(cfg.SenderMaxCalldataSize)),
+		}
+		for _, limit := range cfg.SenderMaxCalldataSize {
+			if !ethcommon.IsHexAddress(limit.Address) {
+				return nil, fmt.Errorf("invalid sender_max_calldata_size: invalid address %s", limit.Address)
+			}
+			rule.senderMaxSize[strings.ToLower(limit.Address)] = limit.MaxSize
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(cfg.ToAllowList) > 0 {
+		tos, err := parseAddressSet(cfg.ToAllowList)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid to_allow_list")
+		}
+		rules = append(rules, &toAllowRule{tos: tos})
+	}
+
+	if len(cfg.ToDenyList) > 0 {
+		tos, err := parseAddressSet(cfg.ToDenyList)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid to_deny_list")
+		}
+		rules = append(rules, &toDenyRule{tos: tos})
+	}
+
+	if cfg.MinPriorityFee != nil && cfg.MinPriorityFee.ToBigInt().Sign() > 0 {
+		rules = append(rules, &minPriorityFeeRule{
+			minFee:    cfg.MinPriorityFee.ToBigInt(),
+			baseFeeFn: baseFeeFn,
+		})
+	}
+
+	return rules, nil
+}
+
+func parseAddressSet(addrs []string) (map[string]struct{}, error) {
+	set := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		if !ethcommon.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid address %s", addr)
+		}
+		set[strings.ToLower(addr)] = struct{}{}
+	}
+	return set, nil
+}
+
+type contractCreationDenyRule struct {
+	senders map[string]struct{}
+}
+
+func (r *contractCreationDenyRule) Name() string {
+	return "contract_creation_deny"
+}
+
+func (r *contractCreationDenyRule) Check(tx *types.Transaction) error {
+	if tx.GetTo() != nil {
+		return nil
+	}
+	if _, ok := r.senders[strings.ToLower(tx.GetFrom().String())]; ok {
+		return fmt.Errorf("%w: [hash:%s, nonce:%d], sender: %s", errContractCreationDenied,
+			tx.GetHash().String(), tx.GetNonce(), tx.GetFrom().String())
+	}
+	return nil
+}
+
+type calldataSizeRule struct {
+	maxSize       uint64
+	senderMaxSize map[string]uint64
+}
+
+func (r *calldataSizeRule) Name() string {
+	return "max_calldata_size"
+}
+
+func (r *calldataSizeRule) Check(tx *types.Transaction) error {
+	maxSize, ok := r.senderMaxSize[strings.ToLower(tx.GetFrom().String())]
+	if !ok {
+		maxSize = r.maxSize
+	}
+	if maxSize == 0 {
+		return nil
+	}
+	if size := uint64(len(tx.GetPayload())); size > maxSize {
+		return fmt.Errorf("%w: [hash:%s, nonce:%d], calldata size: %d, limit: %d", errCalldataTooLarge,
+			tx.GetHash().String(), tx.GetNonce(), size, maxSize)
+	}
+	return nil
+}
+
+type toAllowRule struct {
+	tos map[string]struct{}
+}
+
+func (r *toAllowRule) Name() string {
+	return "to_allow_list"
+}
+
+func (r *toAllowRule) Check(tx *types.Transaction) error {
+	if tx.GetTo() == nil {
+		return nil
+	}
+	if _, ok := r.tos[strings.ToLower(tx.GetTo().String())]; !ok {
+		return fmt.Errorf("%w: [hash:%s, nonce:%d], to: %s", errToNotAllowed,
+			tx.GetHash().String(), tx.GetNonce(), tx.GetTo().String())
+	}
+	return nil
+}
+
+type toDenyRule struct {
+	tos map[string]struct{}
+}
+
+func (r *toDenyRule) Name() string {
+	return "to_deny_list"
+}
+
+func (r *toDenyRule) Check(tx *types.Transaction) error {
+	if tx.GetTo() == nil {
+		return nil
+	}
+	if _, ok := r.tos[strings.ToLower(tx.GetTo().String())]; ok {
+		return fmt.Errorf("%w: [hash:%s, nonce:%d], to: %s", errToDenied,
+			tx.GetHash().String(), tx.GetNonce(), tx.GetTo().String())
+	}
+	return nil
+}
+
+type minPriorityFeeRule struct {
+	minFee    *big.Int
+	baseFeeFn func() *big.Int
+}
+
+func (r *minPriorityFeeRule) Name() string {
+	return "min_priority_fee"
+}
+
+func (r *minPriorityFeeRule) Check(tx *types.Transaction) error {
+	// effective tip = min(gasTipCap, gasFeeCap - baseFee), the legacy tx uses the gas price as both
+	tip := new(big.Int).Sub(tx.GetGasFeeCap(), r.baseFeeFn())
+	if tx.GetGasTipCap().Cmp(tip) < 0 {
+		tip = tx.GetGasTipCap()
+	}
+	if tip.Cmp(r.minFee) < 0 {
+		return fmt.Errorf("%w: [hash:%s, nonce:%d], priority fee: %s, min priority fee: %s", errPriorityFeeTooLow,
+			tx.GetHash().String(), tx.GetNonce(), tip, r.minFee)
+	}
+	return nil
+}
+
+// checkAdmission evaluates the admission rules in order, returns the error of the first rejecting rule.
+func (tp *TxPreCheckMgr) checkAdmission(tx *types.Transaction) error {
+	for _, rule := range tp.admissionRules {
+		if err := rule.Check(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ func NewMockMinPreCheck(mockCtl *gomock.Controller, pool txpool.TxPool[types.Tra
 	}
 	mockPrecheck.EXPECT().Start().AnyTimes()
 	mockPrecheck.EXPECT().UpdateEpochInfo(gomock.Any()).AnyTimes()
+	mockPrecheck.EXPECT().CheckTx(gomock.Any()).Return(nil).AnyTimes()
 	mockPrecheck.EXPECT().PostUncheckedTxEvent(gomock.Any()).Do(func(ev *common.UncheckedTxEvent) {
 		switch ev.EventType {
 		case common.LocalTxEvent:
@@ -31,7 +32,19 @@ func NewMockMinPreCheck(mockCtl *gomock.Controller, pool txpool.TxPool[types.Tra
 
 			tx := txWithResp.Tx
 
-			err := mockPrecheck.pool.AddLocalTx(tx)
+			var err error
+			if txWithResp.Private {
+				pool, ok := mockPrecheck.pool.(interface {
+					AddPrivateTx(tx *types.Transaction, maxBlock uint64) error
+				})
+				if !ok {
+					err = precheck.ErrPrivateTxUnsupported
+				} else {
+					err = pool.AddPrivateTx(tx, txWithResp.MaxBlock)
+				}
+			} else {
+				err = mockPrecheck.pool.AddLocalTx(tx)
+			}
 			if err != nil {
 				txWithResp.PoolCh <- &common.TxResp{
 					Status:   false,
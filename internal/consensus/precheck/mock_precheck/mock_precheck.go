@@ -40,6 +40,44 @@ func (m *MockPreCheck) EXPECT() *MockPreCheckMockRecorder {
 	return m.recorder
 }
 
+// CheckTx mocks base method.
+func (m *MockPreCheck) CheckTx(tx *types.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckTx", tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckTx indicates an expected call of CheckTx.
+func (mr *MockPreCheckMockRecorder) CheckTx(tx any) *MockPreCheckCheckTxCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTx", reflect.TypeOf((*MockPreCheck)(nil).CheckTx), tx)
+	return &MockPreCheckCheckTxCall{Call: call}
+}
+
+// MockPreCheckCheckTxCall wrap *gomock.Call
+type MockPreCheckCheckTxCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockPreCheckCheckTxCall) Return(arg0 error) *MockPreCheckCheckTxCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockPreCheckCheckTxCall) Do(f func(*types.Transaction) error) *MockPreCheckCheckTxCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockPreCheckCheckTxCall) DoAndReturn(f func(*types.Transaction) error) *MockPreCheckCheckTxCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // PostUncheckedTxEvent mocks base method.
 func (m *MockPreCheck) PostUncheckedTxEvent(ev *common.UncheckedTxEvent) {
 	m.ctrl.T.Helper()
@@ -14,4 +14,7 @@ type PreCheck interface {
 	PostUncheckedTxEvent(ev *common.UncheckedTxEvent)
 
 	UpdateEpochInfo(epoch *types.EpochInfo)
+
+	// CheckTx runs all the prechecks of the local tx synchronously
+	CheckTx(tx *types.Transaction) error
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/axiomesh/axiom-kit/log"
@@ -54,7 +55,9 @@ func newMockPreCheckMgr(ledger *mockDb, t *testing.T) (*TxPreCheckMgr, *logrus.E
 		TxPool:            mockPool,
 	}
 
-	return NewTxPreCheckMgr(ctx, cnf), logger, cancel
+	tp, err := NewTxPreCheckMgr(ctx, cnf)
+	require.Nil(t, err)
+	return tp, logger, cancel
 }
 
 func (db *mockDb) setBalance(address string, balance *big.Int) {
@@ -36,6 +36,10 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	ErrPrivateTxUnsupported = errors.New("txpool does not support private tx")
+
+	ErrBundleUnsupported = errors.New("txpool does not support bundle")
 )
 
 type ValidTxs struct {
@@ -43,6 +47,35 @@ type ValidTxs struct {
 	Txs              []*types.Transaction
 	LocalCheckRespCh chan *common.TxResp
 	LocalPoolRespCh  chan *common.TxResp
+	Private          bool
+	MaxBlock         uint64
+}
+
+type privateTxPool interface {
+	AddPrivateTx(tx *types.Transaction, maxBlock uint64) error
+}
+
+type bundleTxPool interface {
+	AddBundle(txs []*types.Transaction, maxBlock uint64) (string, error)
+}
+
+// AddBundle prechecks all the bundle txs synchronously and adds them to the txpool as a bundle,
+// the whole bundle is rejected if any tx fails the precheck.
+func AddBundle(preCheck PreCheck, pool txpool.TxPool[types.Transaction, *types.Transaction], txs []*types.Transaction, maxBlock uint64) (string, error) {
+	bundlePool, ok := pool.(bundleTxPool)
+	if !ok {
+		return "", ErrBundleUnsupported
+	}
+	for _, tx := range txs {
+		if err := preCheck.CheckTx(tx); err != nil {
+			return "", errors.Wrap(common.ErrorPreCheck, err.Error())
+		}
+	}
+	bundleID, err := bundlePool.AddBundle(txs, maxBlock)
+	if err != nil {
+		return "", errors.Wrap(common.ErrorAddTxPool, err.Error())
+	}
+	return bundleID, nil
 }
 
 type TxPreCheckMgr struct {
@@ -55,8 +88,12 @@ type TxPreCheckMgr struct {
 	txpool       txpool.TxPool[types.Transaction, *types.Transaction]
 
 	BaseFee      *big.Int // current is 0
+	chainID      uint64   // 0 means the chain id is not checked
 	getBalanceFn func(address string) *big.Int
 
+	admissionRules []AdmissionRule
+	impersonated   *common.ImpersonatedAccounts // only set with the solo_dev consensus
+
 	ctx       context.Context
 	txMaxSize atomic.Uint64
 }
@@ -73,7 +110,7 @@ func (tp *TxPreCheckMgr) pushValidTxs(ev *ValidTxs) {
 	tp.validTxsCh <- ev
 }
 
-func NewTxPreCheckMgr(ctx context.Context, conf *common.Config) *TxPreCheckMgr {
+func NewTxPreCheckMgr(ctx context.Context, conf *common.Config) (*TxPreCheckMgr, error) {
 	tp := &TxPreCheckMgr{
 		basicCheckCh: make(chan *common.UncheckedTxEvent, defaultTxPreCheckSize),
 		verifySignCh: make(chan *common.UncheckedTxEvent, defaultTxPreCheckSize),
@@ -82,9 +119,10 @@ func NewTxPreCheckMgr(ctx context.Context, conf *common.Config) *TxPreCheckMgr {
 		chainState:   conf.ChainState,
 		logger:       conf.Logger,
 		ctx:          ctx,
-		BaseFee:      big.NewInt(0),
+		BaseFee:      components.BaseFee(),
 		getBalanceFn: conf.GetAccountBalance,
 		txpool:       conf.TxPool,
+		impersonated: conf.Impersonated,
 	}
 
 	if conf.GenesisEpochInfo.MiscParams.TxMaxSize == 0 {
@@ -93,7 +131,16 @@ func NewTxPreCheckMgr(ctx context.Context, conf *common.Config) *TxPreCheckMgr {
 		tp.txMaxSize.Store(conf.GenesisEpochInfo.MiscParams.TxMaxSize)
 	}
 
-	return tp
+	if conf.Repo != nil {
+		tp.chainID = conf.Repo.GenesisConfig.ChainID
+		rules, err := newAdmissionRules(conf.Repo.Config.TxAdmission, func() *big.Int { return tp.BaseFee })
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to init tx admission rules")
+		}
+		tp.admissionRules = rules
+	}
+
+	return tp, nil
 }
 
 func (tp *TxPreCheckMgr) Start() {
@@ -114,7 +161,17 @@ func (tp *TxPreCheckMgr) postValidTxs() {
 			if txs.Local {
 				// notify consensus that it had prechecked, can broadcast to other nodes
 				respLocalTx(responseType_precheck, txs.LocalCheckRespCh, nil)
-				err := tp.txpool.AddLocalTx(txs.Txs[0])
+				var err error
+				if txs.Private {
+					pool, ok := tp.txpool.(privateTxPool)
+					if !ok {
+						err = ErrPrivateTxUnsupported
+					} else {
+						err = pool.AddPrivateTx(txs.Txs[0], txs.MaxBlock)
+					}
+				} else {
+					err = tp.txpool.AddLocalTx(txs.Txs[0])
+				}
 				respLocalTx(responseType_txPool, txs.LocalPoolRespCh, err)
 			} else {
 				tp.txpool.AddRemoteTxs(txs.Txs)
@@ -241,6 +298,8 @@ func (tp *TxPreCheckMgr) dispatchVerifyDataEvent() {
 					local            bool
 					localCheckRespCh chan *common.TxResp
 					localPoolRespCh  chan *common.TxResp
+					private          bool
+					maxBlock         uint64
 				)
 
 				now := time.Now()
@@ -250,6 +309,13 @@ func (tp *TxPreCheckMgr) dispatchVerifyDataEvent() {
 					txWithResp := ev.Event.(*common.TxWithResp)
 					localCheckRespCh = txWithResp.CheckCh
 					localPoolRespCh = txWithResp.PoolCh
+					private = txWithResp.Private
+					maxBlock = txWithResp.MaxBlock
+					if err := tp.checkAdmission(txWithResp.Tx); err != nil {
+						respLocalTx(responseType_precheck, txWithResp.CheckCh, wrapError(err))
+						tp.logger.Warningf("admission check local tx err:%s", err)
+						return
+					}
 					// check balance
 					if err := components.VerifyInsufficientBalance[types.Transaction, *types.Transaction](txWithResp.Tx, tp.getBalanceFn); err != nil {
 						respLocalTx(responseType_precheck, txWithResp.CheckCh, wrapError(err))
@@ -265,6 +331,10 @@ func (tp *TxPreCheckMgr) dispatchVerifyDataEvent() {
 						return
 					}
 					for _, tx := range txSet {
+						if err := tp.checkAdmission(tx); err != nil {
+							tp.logger.Warningf("admission check remote tx err:%s", err)
+							continue
+						}
 						if err := components.VerifyInsufficientBalance[types.Transaction, *types.Transaction](tx, tp.getBalanceFn); err != nil {
 							tp.logger.Warningf("verify remote tx balance failed: %v", err)
 							continue
@@ -282,6 +352,8 @@ func (tp *TxPreCheckMgr) dispatchVerifyDataEvent() {
 				if local {
 					validTxs.LocalCheckRespCh = localCheckRespCh
 					validTxs.LocalPoolRespCh = localPoolRespCh
+					validTxs.Private = private
+					validTxs.MaxBlock = maxBlock
 				}
 
 				tp.pushValidTxs(validTxs)
@@ -290,8 +362,44 @@ func (tp *TxPreCheckMgr) dispatchVerifyDataEvent() {
 	}
 }
 
+// CheckTx runs the basic check, signature verification, admission rules and balance check of the tx
+// synchronously, besides it checks the chain id and the gas limit of the tx. It is used by the txs which
+// are added to the txpool directly instead of through PostUncheckedTxEvent, e.g. the bundle txs.
+func (tp *TxPreCheckMgr) CheckTx(tx *types.Transaction) error {
+	if tp.chainID != 0 {
+		if chainID := tx.GetChainID(); chainID != nil && chainID.Sign() != 0 && chainID.Uint64() != tp.chainID {
+			return wrapError(fmt.Errorf("%w: [hash:%s, nonce:%d] expect chain id: %d, get chain id: %s",
+				errInvalidChainID, tx.GetHash().String(), tx.GetNonce(), tp.chainID, chainID))
+		}
+	}
+	if gasLimit := tp.chainState.EpochInfo.FinanceParams.GasLimit; gasLimit != 0 && tx.GetGas() > gasLimit {
+		return wrapError(fmt.Errorf("%w: [hash:%s, nonce:%d] block gas limit: %d, get gas limit: %d",
+			errGasLimitTooHigh, tx.GetHash().String(), tx.GetNonce(), gasLimit, tx.GetGas()))
+	}
+	if err := tp.basicCheckTx(tx); err != nil {
+		return wrapError(err)
+	}
+	if err := tp.verifySignature(tx); err != nil {
+		return wrapError(err)
+	}
+	if err := tp.checkAdmission(tx); err != nil {
+		return wrapError(err)
+	}
+	if err := components.VerifyInsufficientBalance[types.Transaction, *types.Transaction](tx, tp.getBalanceFn); err != nil {
+		return wrapError(err)
+	}
+	return nil
+}
+
 func (tp *TxPreCheckMgr) verifySignature(tx *types.Transaction) error {
-	if err := tx.VerifySignature(); err != nil {
+	if _, ok := components.ImpersonationSender(tx); ok {
+		// the unsigned tx is only accepted from the impersonated accounts in the dev mode, the decoded tx
+		// carrying the impersonation signature is rejected, otherwise its sender would be restored
+		// differently from the one recovered here
+		if !common.IsImpersonatedTx(tx) || !tp.impersonated.Has(tx.GetFrom().String()) {
+			return errTxSign
+		}
+	} else if err := tx.VerifySignature(); err != nil {
 		return errTxSign
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/axiomesh/axiom-kit/log"
+	commonpool "github.com/axiomesh/axiom-kit/txpool"
 	"github.com/axiomesh/axiom-kit/types"
 	"github.com/axiomesh/axiom-ledger/internal/chainstate"
 	consensuscommon "github.com/axiomesh/axiom-ledger/internal/consensus/common"
@@ -97,7 +98,8 @@ func TestTxPreCheckMgr_Start(t *testing.T) {
 			GetAccountBalance: getAccountBalance,
 		}
 
-		tp := NewTxPreCheckMgr(context.Background(), cnf)
+		tp, err := NewTxPreCheckMgr(context.Background(), cnf)
+		require.Nil(t, err)
 		tp.Start()
 
 		originalOutput := lg.Logger.Out
@@ -346,6 +348,57 @@ func TestTxPreCheckMgr_VerifySign(t *testing.T) {
 				require.Contains(t, resp.ErrorMsg, errTo.Error())
 			},
 		},
+		{
+			name: "impersonated tx",
+			fn: func(t *testing.T) {
+				defer func() {
+					jobDoneC <- struct{}{}
+				}()
+				from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+				to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+				newEvent := func() *consensuscommon.UncheckedTxEvent {
+					tx := consensuscommon.NewImpersonatedTx(from, &types.DynamicFeeTx{
+						ChainID:   big.NewInt(1356),
+						GasTipCap: big.NewInt(0),
+						GasFeeCap: big.NewInt(0),
+						Gas:       21000,
+						To:        &to,
+						Value:     big.NewInt(1),
+					})
+					return createLocalTxEvent(tx)
+				}
+
+				// the sender is not impersonated
+				event := newEvent()
+				tp.PostUncheckedTxEvent(event)
+				resp := <-event.Event.(*consensuscommon.TxWithResp).CheckCh
+				require.False(t, resp.Status)
+				require.Contains(t, resp.ErrorMsg, errTxSign.Error())
+
+				tp.impersonated = consensuscommon.NewImpersonatedAccounts()
+				tp.impersonated.Add(from.String())
+				defer func() {
+					tp.impersonated = nil
+				}()
+				event = newEvent()
+				tp.PostUncheckedTxEvent(event)
+				resp = <-event.Event.(*consensuscommon.TxWithResp).CheckCh
+				require.False(t, resp.Status)
+				// pass the signature check and fail for the balance
+				require.Contains(t, resp.ErrorMsg, core.ErrInsufficientFundsForTransfer.Error())
+
+				// the raw tx carrying the impersonation signature is rejected even if the sender is impersonated
+				data, err := newEvent().Event.(*consensuscommon.TxWithResp).Tx.RbftMarshal()
+				require.Nil(t, err)
+				rawTx := &types.Transaction{}
+				require.Nil(t, rawTx.RbftUnmarshal(data))
+				event = createLocalTxEvent(rawTx)
+				tp.PostUncheckedTxEvent(event)
+				resp = <-event.Event.(*consensuscommon.TxWithResp).CheckCh
+				require.False(t, resp.Status)
+				require.Contains(t, resp.ErrorMsg, errTxSign.Error())
+			},
+		},
 	}
 
 	for _, tt := range testCase {
@@ -509,3 +562,195 @@ func setupPrecheck(t *testing.T) (*TxPreCheckMgr, *logrus.Entry, *mockDb) {
 	tp.Start()
 	return tp, lg, ledger
 }
+
+func TestTxPreCheckMgr_AdmissionRules(t *testing.T) {
+	t.Parallel()
+	s, err := types.GenerateSigner()
+	require.Nil(t, err)
+	other, err := types.GenerateSigner()
+	require.Nil(t, err)
+	deniedTo := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+
+	setupAdmission := func(t *testing.T, cfg repo.TxAdmission) *TxPreCheckMgr {
+		tp, _, _ := newMockPreCheckMgr(&mockDb{db: make(map[string]*big.Int)}, t)
+		rules, err := newAdmissionRules(cfg, func() *big.Int { return tp.BaseFee })
+		require.Nil(t, err)
+		tp.admissionRules = rules
+		tp.Start()
+		return tp
+	}
+
+	checkLocal := func(t *testing.T, tp *TxPreCheckMgr, tx *types.Transaction, expectErr error) {
+		event := createLocalTxEvent(tx)
+		tp.PostUncheckedTxEvent(event)
+		resp := <-event.Event.(*consensuscommon.TxWithResp).CheckCh
+		require.False(t, resp.Status)
+		require.Contains(t, resp.ErrorMsg, expectErr.Error())
+	}
+
+	t.Run("test contract creation deny list", func(t *testing.T) {
+		tp := setupAdmission(t, repo.TxAdmission{ContractCreationDenyList: []string{s.Addr.String()}})
+		tx, err := generateLegacyTx(s, nil, 0, []byte{0x60}, basicGas*10, 1, big.NewInt(0))
+		require.Nil(t, err)
+		checkLocal(t, tp, tx, errContractCreationDenied)
+
+		tx, err = generateLegacyTx(other, nil, 0, []byte{0x60}, basicGas*10, 1, big.NewInt(0))
+		require.Nil(t, err)
+		require.Nil(t, tp.checkAdmission(tx))
+		tx, err = generateLegacyTx(s, &toAddr, 0, []byte{0x60}, basicGas*10, 1, big.NewInt(0))
+		require.Nil(t, err)
+		require.Nil(t, tp.checkAdmission(tx))
+	})
+
+	t.Run("test max calldata size", func(t *testing.T) {
+		tp := setupAdmission(t, repo.TxAdmission{
+			MaxCalldataSize: 4,
+			SenderMaxCalldataSize: []repo.SenderCalldataLimit{
+				{Address: other.Addr.String(), MaxSize: 8},
+			},
+		})
+		data := []byte("hello world")
+		tx, err := generateLegacyTx(s, &toAddr, 0, data[:5], basicGas*10, 1, big.NewInt(0))
+		require.Nil(t, err)
+		checkLocal(t, tp, tx, errCalldataTooLarge)
+
+		tx, err = generateLegacyTx(other, &toAddr, 0, data[:5], basicGas*10, 1, big.NewInt(0))
+		require.Nil(t, err)
+		require.Nil(t, tp.checkAdmission(tx))
+		tx, err = generateLegacyTx(other, &toAddr, 0, data, basicGas*10, 1, big.NewInt(0))
+		require.Nil(t, err)
+		require.ErrorIs(t, tp.checkAdmission(tx), errCalldataTooLarge)
+	})
+
+	t.Run("test to allow and deny list", func(t *testing.T) {
+		tp := setupAdmission(t, repo.TxAdmission{
+			ToAllowList: []string{toAddr.String(), deniedTo.String()},
+			ToDenyList:  []string{deniedTo.String()},
+		})
+		tx, err := generateLegacyTx(s, &deniedTo, 0, nil, basicGas, 1, big.NewInt(0))
+		require.Nil(t, err)
+		checkLocal(t, tp, tx, errToDenied)
+
+		notAllowed := common.HexToAddress("0x90F79bf6EB2c4f870365E785982E1f101E93b906")
+		tx, err = generateLegacyTx(s, &notAllowed, 0, nil, basicGas, 1, big.NewInt(0))
+		require.Nil(t, err)
+		require.ErrorIs(t, tp.checkAdmission(tx), errToNotAllowed)
+		tx, err = generateLegacyTx(s, &toAddr, 0, nil, basicGas, 1, big.NewInt(0))
+		require.Nil(t, err)
+		require.Nil(t, tp.checkAdmission(tx))
+	})
+
+	t.Run("test min priority fee", func(t *testing.T) {
+		tp := setupAdmission(t, repo.TxAdmission{MinPriorityFee: types.CoinNumberByMol(100)})
+		tx, err := generateLegacyTx(s, &toAddr, 0, nil, basicGas, 99, big.NewInt(0))
+		require.Nil(t, err)
+		checkLocal(t, tp, tx, errPriorityFeeTooLow)
+
+		tx, err = generateDynamicFeeTx(s, &toAddr, nil, basicGas, big.NewInt(0), big.NewInt(1000), big.NewInt(100))
+		require.Nil(t, err)
+		require.Nil(t, tp.checkAdmission(tx))
+		tp.BaseFee = big.NewInt(950)
+		require.ErrorIs(t, tp.checkAdmission(tx), errPriorityFeeTooLow)
+	})
+
+	t.Run("test invalid admission config", func(t *testing.T) {
+		_, err := newAdmissionRules(repo.TxAdmission{ToDenyList: []string{"0x123"}}, nil)
+		require.NotNil(t, err)
+		_, err = newAdmissionRules(repo.TxAdmission{
+			SenderMaxCalldataSize: []repo.SenderCalldataLimit{{Address: "abc", MaxSize: 1}},
+		}, nil)
+		require.NotNil(t, err)
+		rules, err := newAdmissionRules(repo.DefaultConfig().TxAdmission, nil)
+		require.Nil(t, err)
+		require.Empty(t, rules)
+	})
+}
+
+type mockBundlePool struct {
+	commonpool.TxPool[types.Transaction, *types.Transaction]
+	bundles [][]*types.Transaction
+}
+
+func (p *mockBundlePool) AddBundle(txs []*types.Transaction, _ uint64) (string, error) {
+	p.bundles = append(p.bundles, txs)
+	return txs[0].GetHash().String(), nil
+}
+
+func TestTxPreCheckMgr_CheckTx(t *testing.T) {
+	t.Parallel()
+	s, err := types.GenerateSigner()
+	require.Nil(t, err)
+	ledger := &mockDb{db: make(map[string]*big.Int)}
+	ledger.setBalance(s.Addr.String(), big.NewInt(int64(basicGas*100)))
+	tp, _, cancel := newMockPreCheckMgr(ledger, t)
+	defer cancel()
+	tp.chainID = 1
+	tp.chainState.EpochInfo.FinanceParams.GasLimit = basicGas * 10
+
+	tx, err := generateDynamicFeeTx(s, &toAddr, nil, basicGas, big.NewInt(0), big.NewInt(1), big.NewInt(1))
+	require.Nil(t, err)
+	require.Nil(t, tp.CheckTx(tx))
+
+	t.Run("test chain id", func(t *testing.T) {
+		tp.chainID = 2
+		defer func() {
+			tp.chainID = 1
+		}()
+		require.ErrorIs(t, tp.CheckTx(tx), errInvalidChainID)
+	})
+
+	t.Run("test gas limit", func(t *testing.T) {
+		tx, err := generateDynamicFeeTx(s, &toAddr, nil, basicGas*11, big.NewInt(0), big.NewInt(1), big.NewInt(1))
+		require.Nil(t, err)
+		require.ErrorIs(t, tp.CheckTx(tx), errGasLimitTooHigh)
+	})
+
+	t.Run("test intrinsic gas", func(t *testing.T) {
+		tx, err := generateDynamicFeeTx(s, &toAddr, nil, basicGas-1, big.NewInt(0), big.NewInt(1), big.NewInt(1))
+		require.Nil(t, err)
+		require.ErrorIs(t, tp.CheckTx(tx), errIntrinsicGas)
+	})
+
+	t.Run("test oversized data", func(t *testing.T) {
+		data := bytes.Repeat([]byte{1}, int(tp.txMaxSize.Load())+1)
+		tx, err := generateDynamicFeeTx(s, &toAddr, data, basicGas*10, big.NewInt(0), big.NewInt(1), big.NewInt(1))
+		require.Nil(t, err)
+		require.ErrorIs(t, tp.CheckTx(tx), ErrOversizedData)
+	})
+
+	t.Run("test admission rules", func(t *testing.T) {
+		rules, err := newAdmissionRules(repo.TxAdmission{ToDenyList: []string{toAddr.String()}}, func() *big.Int { return tp.BaseFee })
+		require.Nil(t, err)
+		tp.admissionRules = rules
+		defer func() {
+			tp.admissionRules = nil
+		}()
+		require.ErrorIs(t, tp.CheckTx(tx), errToDenied)
+	})
+
+	t.Run("test insufficient balance", func(t *testing.T) {
+		poor, err := types.GenerateSigner()
+		require.Nil(t, err)
+		tx, err := generateDynamicFeeTx(poor, &toAddr, nil, basicGas, big.NewInt(0), big.NewInt(1), big.NewInt(1))
+		require.Nil(t, err)
+		require.ErrorIs(t, tp.CheckTx(tx), errInsufficientFunds)
+	})
+
+	t.Run("test add bundle", func(t *testing.T) {
+		_, err := AddBundle(tp, tp.txpool, []*types.Transaction{tx}, 10)
+		require.ErrorIs(t, err, ErrBundleUnsupported)
+
+		pool := &mockBundlePool{TxPool: tp.txpool}
+		invalidTx, err := generateDynamicFeeTx(s, &toAddr, nil, basicGas*11, big.NewInt(0), big.NewInt(1), big.NewInt(1))
+		require.Nil(t, err)
+		_, err = AddBundle(tp, pool, []*types.Transaction{tx, invalidTx}, 10)
+		require.ErrorIs(t, err, consensuscommon.ErrorPreCheck)
+		require.Contains(t, err.Error(), errGasLimitTooHigh.Error())
+		require.Equal(t, 0, len(pool.bundles))
+
+		bundleID, err := AddBundle(tp, pool, []*types.Transaction{tx}, 10)
+		require.Nil(t, err)
+		require.Equal(t, tx.GetHash().String(), bundleID)
+		require.Equal(t, 1, len(pool.bundles))
+	})
+}
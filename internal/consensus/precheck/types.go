@@ -12,6 +12,8 @@ var precheckErrPrefix = errors.New("verify tx err")
 var (
 	errTxSign                       = errors.New("tx signature verify failed")
 	errTo                           = errors.New("tx from and to address is same")
+	errInvalidChainID               = errors.New("invalid chain id")
+	errGasLimitTooHigh              = errors.New("gas limit exceeds block gas limit")
 	errGasPriceTooLow               = errors.New("gas price too low")
 	errFeeCapVeryHigh               = core.ErrFeeCapVeryHigh
 	errTipVeryHigh                  = core.ErrTipVeryHigh
@@ -21,11 +23,19 @@ var (
 	errInsufficientFunds            = core.ErrInsufficientFunds
 	errIntrinsicGas                 = core.ErrIntrinsicGas
 	errInsufficientFundsForTransfer = core.ErrInsufficientFundsForTransfer
+
+	errContractCreationDenied = errors.New("contract creation denied by admission rule")
+	errCalldataTooLarge       = errors.New("calldata too large by admission rule")
+	errToNotAllowed           = errors.New("to address not allowed by admission rule")
+	errToDenied               = errors.New("to address denied by admission rule")
+	errPriorityFeeTooLow      = errors.New("priority fee too low by admission rule")
 )
 
 var errorTypes = map[error]string{
 	errTxSign:                       errTxSign.Error(),
 	errTo:                           errTo.Error(),
+	errInvalidChainID:               errInvalidChainID.Error(),
+	errGasLimitTooHigh:              errGasLimitTooHigh.Error(),
 	errGasPriceTooLow:               errGasPriceTooLow.Error(),
 	errFeeCapVeryHigh:               errFeeCapVeryHigh.Error(),
 	errTipVeryHigh:                  errTipVeryHigh.Error(),
@@ -35,6 +45,11 @@ var errorTypes = map[error]string{
 	errInsufficientFunds:            errInsufficientFunds.Error(),
 	errIntrinsicGas:                 errIntrinsicGas.Error(),
 	errInsufficientFundsForTransfer: core.ErrInsufficientFundsForTransfer.Error(),
+	errContractCreationDenied:       errContractCreationDenied.Error(),
+	errCalldataTooLarge:             errCalldataTooLarge.Error(),
+	errToNotAllowed:                 errToNotAllowed.Error(),
+	errToDenied:                     errToDenied.Error(),
+	errPriorityFeeTooLow:            errPriorityFeeTooLow.Error(),
 }
 
 const (
@@ -89,6 +89,12 @@ func NewNode(config *common.Config) (*Node, error) {
 		receiveMsgLimiter = rate.NewLimiter(rate.Limit(config.Repo.ConsensusConfig.Limit.Limit), int(config.Repo.ConsensusConfig.Limit.Burst))
 	}
 
+	txPreCheck, err := precheck.NewTxPreCheckMgr(ctx, config)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
 	return &Node{
 		config:            config,
 		n:                 n,
@@ -99,7 +105,7 @@ func NewNode(config *common.Config) (*Node, error) {
 		cancel:            cancel,
 		txCache:           txcache.NewTxCache(config.Repo.ConsensusConfig.TxCache.SetTimeout.ToDuration(), uint64(config.Repo.ConsensusConfig.TxCache.SetSize), config.Logger),
 		network:           config.Network,
-		txPreCheck:        precheck.NewTxPreCheckMgr(ctx, config),
+		txPreCheck:        txPreCheck,
 		txpool:            config.TxPool,
 	}, nil
 }
@@ -353,6 +359,42 @@ func (n *Node) Prepare(tx *types.Transaction) error {
 	return nil
 }
 
+// PreparePrivate adds the tx to the local txpool only, the tx is neither broadcast
+// to other nodes nor notified to the subscribers.
+func (n *Node) PreparePrivate(tx *types.Transaction, maxBlock uint64) error {
+	if !n.started.Load() {
+		return common.ErrorConsensusStart
+	}
+
+	txWithResp := &common.TxWithResp{
+		Tx:       tx,
+		CheckCh:  make(chan *common.TxResp, 1),
+		PoolCh:   make(chan *common.TxResp, 1),
+		Private:  true,
+		MaxBlock: maxBlock,
+	}
+	n.txCache.TxRespC <- txWithResp
+	precheckResp := <-txWithResp.CheckCh
+	if !precheckResp.Status {
+		return errors.Wrap(common.ErrorPreCheck, precheckResp.ErrorMsg)
+	}
+
+	resp := <-txWithResp.PoolCh
+	if !resp.Status {
+		return errors.Wrap(common.ErrorAddTxPool, resp.ErrorMsg)
+	}
+	return nil
+}
+
+// PrepareBundle prechecks the bundle txs and adds them to the local txpool as a bundle,
+// the bundle txs are neither broadcast to other nodes nor notified to the subscribers.
+func (n *Node) PrepareBundle(txs []*types.Transaction, maxBlock uint64) (string, error) {
+	if !n.started.Load() {
+		return "", common.ErrorConsensusStart
+	}
+	return precheck.AddBundle(n.txPreCheck, n.txpool, txs, maxBlock)
+}
+
 func (n *Node) submitTxsFromRemote(txs [][]byte) {
 	var requests []*types.Transaction
 	for _, item := range txs {
@@ -63,6 +63,11 @@ func NewNode(config *common.Config) (*Node, error) {
 	recvCh := make(chan consensusEvent, maxChanSize)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	txPreCheck, err := precheck.NewTxPreCheckMgr(ctx, config)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
 	soloNode := &Node{
 		config:       config,
 		blockCh:      make(chan *txpool.RequestHashBatch[types.Transaction, *types.Transaction], maxChanSize),
@@ -74,13 +79,13 @@ func NewNode(config *common.Config) (*Node, error) {
 		network:      config.Network,
 		ctx:          ctx,
 		cancel:       cancel,
-		txPreCheck:   precheck.NewTxPreCheckMgr(ctx, config),
+		txPreCheck:   txPreCheck,
 		epcCnf:       epochConf,
 		logger:       config.Logger,
 	}
 	batchTimerMgr := &batchTimerManager{Timer: timer.NewTimerManager(config.Logger)}
 
-	err := batchTimerMgr.CreateTimer(common.Batch, config.Repo.ConsensusConfig.Solo.BatchTimeout.ToDuration(), soloNode.handleTimeoutEvent)
+	err = batchTimerMgr.CreateTimer(common.Batch, config.Repo.ConsensusConfig.Solo.BatchTimeout.ToDuration(), soloNode.handleTimeoutEvent)
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +170,40 @@ func (n *Node) Prepare(tx *types.Transaction) error {
 	return nil
 }
 
+// PreparePrivate adds the tx to the txpool without notifying the subscribers,
+// solo node never broadcasts the txs.
+func (n *Node) PreparePrivate(tx *types.Transaction, maxBlock uint64) error {
+	if ready, status := n.getStatus(); !ready {
+		return fmt.Errorf("node get ready failed: %s", status)
+	}
+	txWithResp := &common.TxWithResp{
+		Tx:       tx,
+		CheckCh:  make(chan *common.TxResp, 1),
+		PoolCh:   make(chan *common.TxResp, 1),
+		Private:  true,
+		MaxBlock: maxBlock,
+	}
+	n.postMsg(txWithResp)
+	resp := <-txWithResp.CheckCh
+	if !resp.Status {
+		return errors.Wrap(common.ErrorPreCheck, resp.ErrorMsg)
+	}
+
+	resp = <-txWithResp.PoolCh
+	if !resp.Status {
+		return errors.Wrap(common.ErrorAddTxPool, resp.ErrorMsg)
+	}
+	return nil
+}
+
+// PrepareBundle prechecks the bundle txs and adds them to the txpool as a bundle without notifying the subscribers.
+func (n *Node) PrepareBundle(txs []*types.Transaction, maxBlock uint64) (string, error) {
+	if ready, status := n.getStatus(); !ready {
+		return "", fmt.Errorf("node get ready failed: %s", status)
+	}
+	return precheck.AddBundle(n.txPreCheck, n.txpool, txs, maxBlock)
+}
+
 func (n *Node) Commit() chan *common.CommitEvent {
 	return n.commitC
 }
@@ -1,10 +1,13 @@
 package solo_dev
 
 import (
+	"context"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/axiomesh/axiom-kit/types"
@@ -14,7 +17,18 @@ import (
 	"github.com/axiomesh/axiom-ledger/pkg/repo"
 )
 
-const checkpoint = 10
+const (
+	checkpoint = 10
+
+	idleMiningInterval = time.Second
+)
+
+var (
+	ErrTimestampTooLow     = errors.New("timestamp is lower than or equal to the previous block timestamp")
+	ErrRollbackUnsupported = errors.New("rollback is not supported")
+	ErrInvalidMiningMode   = errors.New("invalid mining mode")
+	ErrBundleUnsupported   = errors.New("bundle is not supported in dev mode")
+)
 
 type GetAccountNonceFunc func(address *types.Address) uint64
 
@@ -22,6 +36,14 @@ func init() {
 	repo.Register(repo.ConsensusTypeSoloDev, false)
 }
 
+// devSnapshot is the chain state saved by evm_snapshot and restored by evm_revert.
+type devSnapshot struct {
+	height        uint64
+	timeOffset    int64
+	nextTimestamp int64
+	pendingTxs    []*types.Transaction
+}
+
 type NodeDev struct {
 	config          *common.Config
 	proposerAccount string
@@ -33,11 +55,57 @@ type NodeDev struct {
 	GetAccountNonce GetAccountNonceFunc
 	txFeed          event.Feed
 	mockBlockFeed   event.Feed
+
+	miningMode     string
+	miningInterval time.Duration
+	intervalResetC chan struct{}
+	pendingTxs     []*types.Transaction
+
+	lastTimestamp int64 // the timestamp of the last-applied block
+	timeOffset    int64 // seconds added to the current time for the next blocks
+	nextTimestamp int64 // the timestamp of the next block, 0 means not set
+
+	snapshots      map[uint64]*devSnapshot
+	nextSnapshotID uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func NewNode(config *common.Config) (*NodeDev, error) {
 	proposerAccount := syscommon.StakingManagerContractAddr
 
+	miningMode := repo.DevMiningModeAuto
+	var miningInterval time.Duration
+	if config.Repo != nil {
+		devConfig := config.Repo.ConsensusConfig.SoloDev
+		if devConfig.MiningMode != "" {
+			miningMode = devConfig.MiningMode
+		}
+		// the interval is only enabled in the interval mode, it can be enabled later by evm_setIntervalMining
+		if miningMode == repo.DevMiningModeInterval {
+			miningInterval = devConfig.MiningInterval.ToDuration()
+		}
+	}
+	switch miningMode {
+	case repo.DevMiningModeAuto, repo.DevMiningModeManual:
+	case repo.DevMiningModeInterval:
+		if miningInterval <= 0 {
+			return nil, errors.Errorf("invalid mining interval %s of the interval mining mode", miningInterval)
+		}
+	default:
+		return nil, errors.Wrapf(ErrInvalidMiningMode, "mining mode: %s", miningMode)
+	}
+
+	var lastTimestamp int64
+	if config.GetBlockHeaderFunc != nil {
+		header, err := config.GetBlockHeaderFunc(config.Applied)
+		if err == nil {
+			lastTimestamp = header.Timestamp
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	return &NodeDev{
 		config:          config,
 		proposerAccount: proposerAccount,
@@ -46,39 +114,275 @@ func NewNode(config *common.Config) (*NodeDev, error) {
 		lastExec:        config.Applied,
 		logger:          config.Logger,
 		GetAccountNonce: config.GetAccountNonce,
+		miningMode:      miningMode,
+		miningInterval:  miningInterval,
+		intervalResetC:  make(chan struct{}, 1),
+		lastTimestamp:   lastTimestamp,
+		snapshots:       make(map[uint64]*devSnapshot),
+		nextSnapshotID:  1,
+		ctx:             ctx,
+		cancel:          cancel,
 	}, nil
 }
 
 func (n *NodeDev) Start() error {
-	n.logger.Info("consensus dev started")
+	go n.listenIntervalMining()
+	n.logger.WithFields(logrus.Fields{
+		"mining_mode":     n.miningMode,
+		"mining_interval": n.miningInterval,
+	}).Info("consensus dev started")
 	return nil
 }
 
 func (n *NodeDev) Stop() {
+	n.cancel()
 	n.logger.Info("consensus dev stopped")
 }
 
 func (n *NodeDev) Prepare(tx *types.Transaction) error {
 	n.mutex.Lock()
 	defer n.mutex.Unlock()
+	if n.miningMode != repo.DevMiningModeAuto {
+		n.pendingTxs = append(n.pendingTxs, tx)
+		return nil
+	}
+	return n.mineBlock([]*types.Transaction{tx}, 0)
+}
+
+// mineBlock generates a block with the txs and waits for it to be persisted, the timestamp is
+// decided by the time settings if it is 0.
+func (n *NodeDev) mineBlock(txs []*types.Transaction, timestamp int64) error {
+	if timestamp == 0 {
+		timestamp = n.nextTimestamp
+	}
+	if timestamp != 0 {
+		if timestamp <= n.lastTimestamp {
+			return errors.Wrapf(ErrTimestampTooLow, "timestamp: %d, previous block timestamp: %d", timestamp, n.lastTimestamp)
+		}
+		// the following blocks continue from the given timestamp
+		n.timeOffset = timestamp - time.Now().Unix()
+	} else {
+		timestamp = time.Now().Unix() + n.timeOffset
+		if timestamp < n.lastTimestamp {
+			timestamp = n.lastTimestamp
+		}
+	}
+	n.nextTimestamp = 0
+
 	block := &types.Block{
 		Header: &types.BlockHeader{
 			Epoch:          1,
 			Number:         n.lastExec + 1,
-			Timestamp:      time.Now().Unix(),
+			Timestamp:      timestamp,
 			ProposerNodeID: 1,
 		},
-		Transactions: []*types.Transaction{tx},
+		Transactions: txs,
 	}
 	n.commitC <- &common.CommitEvent{
 		Block: block,
 	}
 	n.lastExec++
+	n.lastTimestamp = timestamp
 	// ensure this tx had been persist
 	<-n.persistDoneC
 	return nil
 }
 
+func (n *NodeDev) listenIntervalMining() {
+	timer := time.NewTimer(n.getMiningInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-n.intervalResetC:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-timer.C:
+			n.mutex.Lock()
+			if n.miningMode == repo.DevMiningModeInterval {
+				txs := n.pendingTxs
+				n.pendingTxs = nil
+				if err := n.mineBlock(txs, 0); err != nil {
+					n.logger.WithFields(logrus.Fields{
+						"err": err,
+					}).Warning("Mine block by interval failed")
+				}
+			}
+			n.mutex.Unlock()
+		}
+		timer.Reset(n.getMiningInterval())
+	}
+}
+
+func (n *NodeDev) getMiningInterval() time.Duration {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.miningInterval <= 0 {
+		// keep the timer running, it does nothing out of the interval mode
+		return idleMiningInterval
+	}
+	return n.miningInterval
+}
+
+// Mine mines a block with all the pending txs, the block uses the given timestamp if it is not 0.
+func (n *NodeDev) Mine(timestamp int64) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	txs := n.pendingTxs
+	if err := n.mineBlock(txs, timestamp); err != nil {
+		return err
+	}
+	n.pendingTxs = nil
+	return nil
+}
+
+// MineEmptyBlock mines a block without txs, the pending txs are kept.
+func (n *NodeDev) MineEmptyBlock() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.mineBlock(nil, 0)
+}
+
+// IncreaseTime moves the time of the next blocks forward, returns the total seconds moved.
+func (n *NodeDev) IncreaseTime(seconds int64) int64 {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.timeOffset += seconds
+	return n.timeOffset
+}
+
+// SetNextBlockTimestamp sets the timestamp of the next block, the following blocks continue from it.
+func (n *NodeDev) SetNextBlockTimestamp(timestamp int64) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if timestamp <= n.lastTimestamp {
+		return errors.Wrapf(ErrTimestampTooLow, "timestamp: %d, previous block timestamp: %d", timestamp, n.lastTimestamp)
+	}
+	n.nextTimestamp = timestamp
+	return nil
+}
+
+// SetAutomine switches between the auto mining and the interval or manual mining,
+// the pending txs are mined at once if the auto mining is enabled.
+func (n *NodeDev) SetAutomine(enable bool) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if enable {
+		n.miningMode = repo.DevMiningModeAuto
+		if len(n.pendingTxs) == 0 {
+			return nil
+		}
+		txs := n.pendingTxs
+		if err := n.mineBlock(txs, 0); err != nil {
+			return err
+		}
+		n.pendingTxs = nil
+		return nil
+	}
+	if n.miningMode == repo.DevMiningModeAuto {
+		n.miningMode = repo.DevMiningModeManual
+		if n.miningInterval > 0 {
+			n.miningMode = repo.DevMiningModeInterval
+		}
+	}
+	return nil
+}
+
+// SetIntervalMining sets the mining interval, 0 disables the interval mining,
+// it takes effect when the auto mining is disabled.
+func (n *NodeDev) SetIntervalMining(interval time.Duration) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.miningInterval = interval
+	if n.miningMode != repo.DevMiningModeAuto {
+		n.miningMode = repo.DevMiningModeManual
+		if interval > 0 {
+			n.miningMode = repo.DevMiningModeInterval
+		}
+	}
+	select {
+	case n.intervalResetC <- struct{}{}:
+	default:
+	}
+}
+
+// MiningMode returns the current mining mode.
+func (n *NodeDev) MiningMode() string {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.miningMode
+}
+
+// Snapshot saves the current chain state, returns the snapshot id.
+func (n *NodeDev) Snapshot() uint64 {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	id := n.nextSnapshotID
+	n.nextSnapshotID++
+	n.snapshots[id] = &devSnapshot{
+		height:        n.lastExec,
+		timeOffset:    n.timeOffset,
+		nextTimestamp: n.nextTimestamp,
+		pendingTxs:    append([]*types.Transaction(nil), n.pendingTxs...),
+	}
+	return id
+}
+
+// Revert rolls back the chain to the snapshot, the snapshot and the later snapshots are removed,
+// returns false if the snapshot does not exist.
+func (n *NodeDev) Revert(id uint64) (bool, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	snap, ok := n.snapshots[id]
+	if !ok {
+		return false, nil
+	}
+	if snap.height < n.lastExec {
+		if n.config.Rollback == nil {
+			return false, ErrRollbackUnsupported
+		}
+		if err := n.config.Rollback(snap.height); err != nil {
+			return false, err
+		}
+		n.lastExec = snap.height
+	}
+	if n.config.GetBlockHeaderFunc != nil {
+		header, err := n.config.GetBlockHeaderFunc(snap.height)
+		if err != nil {
+			return false, err
+		}
+		n.lastTimestamp = header.Timestamp
+	}
+	n.timeOffset = snap.timeOffset
+	n.nextTimestamp = snap.nextTimestamp
+	n.pendingTxs = snap.pendingTxs
+	for snapID := range n.snapshots {
+		if snapID >= id {
+			delete(n.snapshots, snapID)
+		}
+	}
+	n.logger.WithFields(logrus.Fields{
+		"snapshot": id,
+		"height":   snap.height,
+	}).Info("Revert to snapshot")
+	return true, nil
+}
+
+// PreparePrivate executes the tx immediately like Prepare, the dev node never broadcasts the txs.
+func (n *NodeDev) PreparePrivate(tx *types.Transaction, _ uint64) error {
+	return n.Prepare(tx)
+}
+
+// PrepareBundle is not supported, the dev node executes the txs without the txpool.
+func (n *NodeDev) PrepareBundle(_ []*types.Transaction, _ uint64) (string, error) {
+	return "", ErrBundleUnsupported
+}
+
 func (n *NodeDev) SubmitTxsFromRemote(_ [][]byte) error {
 	return nil
 }
@@ -113,10 +417,24 @@ func (n *NodeDev) Quorum(_ uint64) uint64 {
 
 func (n *NodeDev) GetPendingTxCountByAccount(account string) uint64 {
 	nonce := n.GetAccountNonce(types.NewAddressByStr(account))
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, tx := range n.pendingTxs {
+		if strings.EqualFold(tx.RbftGetFrom(), account) && tx.GetNonce() >= nonce {
+			nonce = tx.GetNonce() + 1
+		}
+	}
 	return nonce
 }
 
-func (n *NodeDev) GetPendingTxByHash(_ *types.Hash) *types.Transaction {
+func (n *NodeDev) GetPendingTxByHash(hash *types.Hash) *types.Transaction {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, tx := range n.pendingTxs {
+		if tx.GetHash().String() == hash.String() {
+			return tx
+		}
+	}
 	return nil
 }
 
@@ -133,7 +451,9 @@ func (n *NodeDev) SubscribeMockBlockEvent(ch chan<- events.ExecutedEvent) event.
 }
 
 func (n *NodeDev) GetTotalPendingTxCount() uint64 {
-	return 0
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return uint64(len(n.pendingTxs))
 }
 
 func (n *NodeDev) GetLowWatermark() uint64 {
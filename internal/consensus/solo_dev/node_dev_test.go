@@ -0,0 +1,354 @@
+package solo_dev
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-kit/log"
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/consensus/common"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+// mockChain executes the blocks committed by the node and keeps their headers.
+type mockChain struct {
+	lock       sync.Mutex
+	headers    map[uint64]*types.BlockHeader
+	blocks     map[uint64]*types.Block
+	height     uint64
+	rolledBack []uint64
+}
+
+func (c *mockChain) getBlockHeader(height uint64) (*types.BlockHeader, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	header, ok := c.headers[height]
+	if !ok {
+		return nil, errors.Errorf("block %d not found", height)
+	}
+	return header, nil
+}
+
+func (c *mockChain) rollback(height uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for h := height + 1; h <= c.height; h++ {
+		delete(c.headers, h)
+		delete(c.blocks, h)
+	}
+	c.height = height
+	c.rolledBack = append(c.rolledBack, height)
+	return nil
+}
+
+func (c *mockChain) getHeight() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.height
+}
+
+func (c *mockChain) getBlock(height uint64) *types.Block {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.blocks[height]
+}
+
+func mockNodeDev(t *testing.T, setConfig func(devConfig *repo.SoloDev)) (*NodeDev, *mockChain) {
+	logger := log.NewWithModule("consensus")
+	logger.Logger.SetLevel(logrus.DebugLevel)
+	rep := repo.MockRepo(t)
+	if setConfig != nil {
+		setConfig(&rep.ConsensusConfig.SoloDev)
+	}
+
+	chain := &mockChain{
+		headers: map[uint64]*types.BlockHeader{
+			0: {Number: 0, Timestamp: time.Now().Unix()},
+		},
+		blocks: make(map[uint64]*types.Block),
+	}
+	node, err := NewNode(&common.Config{
+		Repo:               rep,
+		Logger:             logger,
+		Applied:            0,
+		GetBlockHeaderFunc: chain.getBlockHeader,
+		Rollback:           chain.rollback,
+		GetAccountNonce: func(address *types.Address) uint64 {
+			return 0
+		},
+	})
+	require.Nil(t, err)
+
+	// execute the committed blocks like the executor
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-node.Commit():
+				chain.lock.Lock()
+				chain.headers[ev.Block.Height()] = ev.Block.Header
+				chain.blocks[ev.Block.Height()] = ev.Block
+				chain.height = ev.Block.Height()
+				chain.lock.Unlock()
+				node.ReportState(ev.Block.Height(), ev.Block.Hash(), nil, nil, false)
+			}
+		}
+	}()
+	require.Nil(t, node.Start())
+	t.Cleanup(func() {
+		node.Stop()
+		cancel()
+	})
+	return node, chain
+}
+
+func mockTx(t *testing.T) *types.Transaction {
+	tx, err := types.GenerateEmptyTransactionAndSigner()
+	require.Nil(t, err)
+	return tx
+}
+
+func TestNewNode(t *testing.T) {
+	rep := repo.MockRepo(t)
+	rep.ConsensusConfig.SoloDev.MiningMode = "unknown"
+	_, err := NewNode(&common.Config{Repo: rep, Logger: log.NewWithModule("consensus")})
+	assert.ErrorIs(t, err, ErrInvalidMiningMode)
+
+	rep.ConsensusConfig.SoloDev.MiningMode = repo.DevMiningModeInterval
+	rep.ConsensusConfig.SoloDev.MiningInterval = 0
+	_, err = NewNode(&common.Config{Repo: rep, Logger: log.NewWithModule("consensus")})
+	assert.NotNil(t, err)
+
+	// the interval is ignored out of the interval mode
+	rep.ConsensusConfig.SoloDev.MiningMode = repo.DevMiningModeManual
+	node, err := NewNode(&common.Config{Repo: rep, Logger: log.NewWithModule("consensus")})
+	assert.Nil(t, err)
+	assert.Equal(t, repo.DevMiningModeManual, node.MiningMode())
+
+	node, err = NewNode(&common.Config{Logger: log.NewWithModule("consensus")})
+	assert.Nil(t, err)
+	assert.Equal(t, repo.DevMiningModeAuto, node.MiningMode())
+
+	_, err = node.PrepareBundle([]*types.Transaction{mockTx(t)}, 1)
+	assert.ErrorIs(t, err, ErrBundleUnsupported)
+}
+
+func TestAutoMining(t *testing.T) {
+	node, chain := mockNodeDev(t, nil)
+	assert.Equal(t, repo.DevMiningModeAuto, node.MiningMode())
+
+	// each tx is mined in a block at once
+	tx1, tx2 := mockTx(t), mockTx(t)
+	assert.Nil(t, node.Prepare(tx1))
+	assert.Nil(t, node.PreparePrivate(tx2, 0))
+	assert.EqualValues(t, 2, chain.getHeight())
+	assert.Equal(t, tx1.GetHash(), chain.getBlock(1).Transactions[0].GetHash())
+	assert.Equal(t, tx2.GetHash(), chain.getBlock(2).Transactions[0].GetHash())
+	assert.EqualValues(t, 0, node.GetTotalPendingTxCount())
+	assert.EqualValues(t, 2, node.GetLowWatermark())
+
+	assert.Nil(t, node.MineEmptyBlock())
+	assert.EqualValues(t, 3, chain.getHeight())
+	assert.Equal(t, 0, len(chain.getBlock(3).Transactions))
+}
+
+func TestManualMining(t *testing.T) {
+	node, chain := mockNodeDev(t, func(devConfig *repo.SoloDev) {
+		devConfig.MiningMode = repo.DevMiningModeManual
+	})
+
+	tx1, tx2 := mockTx(t), mockTx(t)
+	assert.Nil(t, node.Prepare(tx1))
+	assert.Nil(t, node.Prepare(tx2))
+	assert.EqualValues(t, 0, chain.getHeight())
+	assert.EqualValues(t, 2, node.GetTotalPendingTxCount())
+	assert.Equal(t, tx1, node.GetPendingTxByHash(tx1.GetHash()))
+	assert.Nil(t, node.GetPendingTxByHash(types.NewHashByStr("0x1")))
+	assert.Equal(t, tx1.GetNonce()+1, node.GetPendingTxCountByAccount(tx1.RbftGetFrom()))
+
+	// the empty block keeps the pending txs
+	assert.Nil(t, node.MineEmptyBlock())
+	assert.EqualValues(t, 1, chain.getHeight())
+	assert.Equal(t, 0, len(chain.getBlock(1).Transactions))
+	assert.EqualValues(t, 2, node.GetTotalPendingTxCount())
+
+	assert.Nil(t, node.Mine(0))
+	assert.EqualValues(t, 2, chain.getHeight())
+	assert.Equal(t, 2, len(chain.getBlock(2).Transactions))
+	assert.EqualValues(t, 0, node.GetTotalPendingTxCount())
+
+	// the auto mining mines the pending txs at once
+	assert.Nil(t, node.Prepare(mockTx(t)))
+	assert.EqualValues(t, 2, chain.getHeight())
+	assert.Nil(t, node.SetAutomine(true))
+	assert.Equal(t, repo.DevMiningModeAuto, node.MiningMode())
+	assert.EqualValues(t, 3, chain.getHeight())
+	assert.Equal(t, 1, len(chain.getBlock(3).Transactions))
+
+	assert.Nil(t, node.SetAutomine(false))
+	assert.Equal(t, repo.DevMiningModeManual, node.MiningMode())
+}
+
+func TestIntervalMining(t *testing.T) {
+	node, chain := mockNodeDev(t, func(devConfig *repo.SoloDev) {
+		devConfig.MiningMode = repo.DevMiningModeInterval
+		devConfig.MiningInterval = repo.Duration(50 * time.Millisecond)
+	})
+	assert.Equal(t, repo.DevMiningModeInterval, node.MiningMode())
+
+	tx1, tx2 := mockTx(t), mockTx(t)
+	assert.Nil(t, node.Prepare(tx1))
+	assert.Nil(t, node.Prepare(tx2))
+	require.Eventually(t, func() bool {
+		return node.GetTotalPendingTxCount() == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	var minedTxs int
+	for h := uint64(1); h <= chain.getHeight(); h++ {
+		minedTxs += len(chain.getBlock(h).Transactions)
+	}
+	assert.Equal(t, 2, minedTxs)
+
+	// the blocks are mined even without txs
+	height := chain.getHeight()
+	require.Eventually(t, func() bool {
+		return chain.getHeight() > height
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// disable the interval mining
+	node.SetIntervalMining(0)
+	assert.Equal(t, repo.DevMiningModeManual, node.MiningMode())
+	// wait for the mining in progress
+	time.Sleep(100 * time.Millisecond)
+	height = chain.getHeight()
+	assert.Nil(t, node.Prepare(mockTx(t)))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, height, chain.getHeight())
+	assert.EqualValues(t, 1, node.GetTotalPendingTxCount())
+
+	// the auto mining takes precedence over the interval mining
+	assert.Nil(t, node.SetAutomine(true))
+	node.SetIntervalMining(50 * time.Millisecond)
+	assert.Equal(t, repo.DevMiningModeAuto, node.MiningMode())
+	assert.Nil(t, node.SetAutomine(false))
+	assert.Equal(t, repo.DevMiningModeInterval, node.MiningMode())
+}
+
+func TestBlockTimestamp(t *testing.T) {
+	node, chain := mockNodeDev(t, func(devConfig *repo.SoloDev) {
+		devConfig.MiningMode = repo.DevMiningModeManual
+	})
+	genesisHeader, err := chain.getBlockHeader(0)
+	require.Nil(t, err)
+
+	// the timestamp must be higher than the previous block
+	err = node.SetNextBlockTimestamp(genesisHeader.Timestamp)
+	assert.ErrorIs(t, err, ErrTimestampTooLow)
+	err = node.Mine(genesisHeader.Timestamp - 1)
+	assert.ErrorIs(t, err, ErrTimestampTooLow)
+	assert.EqualValues(t, 0, chain.getHeight())
+
+	next := genesisHeader.Timestamp + 1000
+	assert.Nil(t, node.SetNextBlockTimestamp(next))
+	assert.Nil(t, node.Mine(0))
+	assert.Equal(t, next, chain.getBlock(1).Header.Timestamp)
+
+	// the following blocks continue from the timestamp
+	assert.Nil(t, node.MineEmptyBlock())
+	assert.GreaterOrEqual(t, chain.getBlock(2).Header.Timestamp, next)
+	assert.Less(t, chain.getBlock(2).Header.Timestamp, next+10)
+
+	assert.Nil(t, node.Mine(next+2000))
+	assert.Equal(t, next+2000, chain.getBlock(3).Header.Timestamp)
+
+	offset := node.IncreaseTime(3600)
+	assert.Nil(t, node.MineEmptyBlock())
+	assert.GreaterOrEqual(t, chain.getBlock(4).Header.Timestamp, next+2000+3600)
+	assert.GreaterOrEqual(t, chain.getBlock(4).Header.Timestamp, time.Now().Unix()+offset-1)
+
+	// the timestamps never decrease even if the time is moved backward
+	node.IncreaseTime(-100000)
+	assert.Nil(t, node.MineEmptyBlock())
+	assert.Nil(t, node.MineEmptyBlock())
+	for h := uint64(1); h <= chain.getHeight(); h++ {
+		parent, err := chain.getBlockHeader(h - 1)
+		require.Nil(t, err)
+		assert.GreaterOrEqual(t, chain.getBlock(h).Header.Timestamp, parent.Timestamp)
+	}
+}
+
+func TestSnapshotAndRevert(t *testing.T) {
+	node, chain := mockNodeDev(t, func(devConfig *repo.SoloDev) {
+		devConfig.MiningMode = repo.DevMiningModeManual
+	})
+
+	tx := mockTx(t)
+	assert.Nil(t, node.Prepare(tx))
+	snap0 := node.Snapshot()
+	assert.Nil(t, node.Mine(0))
+	snap1 := node.Snapshot()
+	node.IncreaseTime(1000)
+	assert.Nil(t, node.MineEmptyBlock())
+	snap2 := node.Snapshot()
+	assert.Nil(t, node.MineEmptyBlock())
+	assert.EqualValues(t, 3, chain.getHeight())
+
+	// the unknown snapshot is not reverted
+	ok, err := node.Revert(100)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = node.Revert(snap1)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, chain.getHeight())
+	assert.Equal(t, []uint64{1}, chain.rolledBack)
+	assert.EqualValues(t, 1, node.GetLowWatermark())
+
+	// the reverted snapshot and the later snapshots are removed
+	for _, id := range []uint64{snap1, snap2} {
+		ok, err = node.Revert(id)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	}
+
+	// the time settings are restored, and the next block follows the reverted block
+	assert.Nil(t, node.MineEmptyBlock())
+	assert.EqualValues(t, 2, chain.getHeight())
+	assert.Less(t, chain.getBlock(2).Header.Timestamp, chain.getBlock(1).Header.Timestamp+100)
+	assert.GreaterOrEqual(t, chain.getBlock(2).Header.Timestamp, chain.getBlock(1).Header.Timestamp)
+
+	// the pending txs are restored
+	ok, err = node.Revert(snap0)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 0, chain.getHeight())
+	assert.Equal(t, tx, node.GetPendingTxByHash(tx.GetHash()))
+
+	// the snapshot at the current height does not roll back the chain
+	snap3 := node.Snapshot()
+	ok, err = node.Revert(snap3)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []uint64{1, 0}, chain.rolledBack)
+}
+
+func TestRevertWithoutRollback(t *testing.T) {
+	node, chain := mockNodeDev(t, nil)
+	node.config.Rollback = nil
+
+	snap := node.Snapshot()
+	assert.Nil(t, node.MineEmptyBlock())
+	ok, err := node.Revert(snap)
+	assert.ErrorIs(t, err, ErrRollbackUnsupported)
+	assert.False(t, ok)
+	assert.EqualValues(t, 1, chain.getHeight())
+}
@@ -7,6 +7,9 @@
 package api
 
 import (
+	"math/big"
+	"time"
+
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -15,8 +18,10 @@ import (
 
 	"github.com/axiomesh/axiom-kit/types"
 	"github.com/axiomesh/axiom-ledger/internal/chainstate"
+	"github.com/axiomesh/axiom-ledger/internal/indexer"
 	"github.com/axiomesh/axiom-ledger/internal/ledger"
 	"github.com/axiomesh/axiom-ledger/internal/sync/common"
+	"github.com/axiomesh/axiom-ledger/internal/txpool"
 	"github.com/axiomesh/axiom-ledger/pkg/events"
 )
 
@@ -26,15 +31,20 @@ type CoreAPI interface {
 	Chain() ChainAPI
 	Feed() FeedAPI
 	TxPool() TxPoolAPI
+	Dev() DevAPI
 	ChainState() *chainstate.ChainState
 }
 
 type BrokerAPI interface {
 	HandleTransaction(tx *types.Transaction) error
+	HandlePrivateTransaction(tx *types.Transaction, maxBlock uint64) error
+	HandleBundle(txs []*types.Transaction, maxBlock uint64) (string, error)
 	GetTransaction(*types.Hash) (*types.Transaction, error)
 	GetTransactionMeta(*types.Hash) (*types.TransactionMeta, error)
+	GetTransactionsByAddress(addr *types.Address, begin, end uint64, offset, limit uint64) ([]*ledger.AddressTx, error)
 	GetReceipt(*types.Hash) (*types.Receipt, error)
 	GetReceipts(blockNum uint64) ([]*types.Receipt, error)
+	GetBundleTx(*types.Hash) (*ledger.BundleTx, error)
 	GetViewStateLedger() ledger.StateLedger
 	GetEvm(mes *core.Message, vmConfig *vm.Config) (*vm.EVM, error)
 	ConsensusReady() (bool, string)
@@ -51,6 +61,9 @@ type BrokerAPI interface {
 	GetBlockTxList(height uint64) ([]*types.Transaction, error)
 
 	GetSyncProgress() *common.SyncProgress
+
+	GetInternalTxsByHash(hash *types.Hash) ([]*indexer.InternalTx, error)
+	GetInternalTxsByAddress(addr *types.Address, begin, end uint64) ([]*indexer.InternalTx, error)
 }
 
 type NetworkAPI interface {
@@ -67,6 +80,7 @@ type FeedAPI interface {
 	SubscribeLogsEvent(chan<- []*types.EvmLog) event.Subscription
 	SubscribeNewTxEvent(chan<- []*types.Transaction) event.Subscription
 	SubscribeNewBlockEvent(chan<- events.ExecutedEvent) event.Subscription
+	SubscribeDroppedTxEvent(chan<- []*txpool.DroppedTx) event.Subscription
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(session *bloombits.MatcherSession)
 }
@@ -77,5 +91,34 @@ type TxPoolAPI interface {
 	GetTransaction(hash *types.Hash) *types.Transaction
 	GetAccountMeta(account string, full bool) any
 	GetMeta(full bool) any
+	GetQuota() *txpool.Quota
+	GetTxStatus(hash string) *txpool.TxStatusInfo
 	IsStarted() bool
 }
+
+// DevAPI controls the chain in the dev mode, the block production and the state setters are only supported
+// by the solo_dev consensus.
+type DevAPI interface {
+	// Mine mines a block with the pending txs, the block uses the timestamp if it is not 0
+	Mine(timestamp int64) error
+	// IncreaseTime moves the time of the next blocks forward, returns the total seconds moved
+	IncreaseTime(seconds int64) (int64, error)
+	SetNextBlockTimestamp(timestamp int64) error
+	Snapshot() (uint64, error)
+	// Revert rolls back the chain to the snapshot, returns false if the snapshot does not exist
+	Revert(id uint64) (bool, error)
+	SetAutomine(enable bool) error
+	// SetIntervalMining sets the interval of mining the pending txs, 0 disables the interval mining
+	SetIntervalMining(interval time.Duration) error
+
+	// the state setters mine a block to apply the writes
+	SetBalance(address *types.Address, balance *big.Int) error
+	SetCode(address *types.Address, code []byte) error
+	SetStorageAt(address *types.Address, key, value []byte) error
+	SetNonce(address *types.Address, nonce uint64) error
+
+	// the unsigned txs of the impersonated accounts are accepted, which is also available with the dev executor
+	ImpersonateAccount(address *types.Address) error
+	StopImpersonatingAccount(address *types.Address) error
+	IsImpersonated(address *types.Address) bool
+}
@@ -10,15 +10,20 @@
 package mock_api
 
 import (
+	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	types "github.com/axiomesh/axiom-kit/types"
 	chainstate "github.com/axiomesh/axiom-ledger/internal/chainstate"
 	api "github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	indexer "github.com/axiomesh/axiom-ledger/internal/indexer"
 	ledger "github.com/axiomesh/axiom-ledger/internal/ledger"
 	common "github.com/axiomesh/axiom-ledger/internal/sync/common"
+	txpool "github.com/axiomesh/axiom-ledger/internal/txpool"
 	events "github.com/axiomesh/axiom-ledger/pkg/events"
 	core "github.com/ethereum/go-ethereum/core"
+	bloombits "github.com/ethereum/go-ethereum/core/bloombits"
 	vm "github.com/ethereum/go-ethereum/core/vm"
 	event "github.com/ethereum/go-ethereum/event"
 	params "github.com/ethereum/go-ethereum/params"
@@ -162,6 +167,44 @@ func (c *MockCoreAPIChainStateCall) DoAndReturn(f func() *chainstate.ChainState)
 	return c
 }
 
+// Dev mocks base method.
+func (m *MockCoreAPI) Dev() api.DevAPI {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dev")
+	ret0, _ := ret[0].(api.DevAPI)
+	return ret0
+}
+
+// Dev indicates an expected call of Dev.
+func (mr *MockCoreAPIMockRecorder) Dev() *MockCoreAPIDevCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dev", reflect.TypeOf((*MockCoreAPI)(nil).Dev))
+	return &MockCoreAPIDevCall{Call: call}
+}
+
+// MockCoreAPIDevCall wrap *gomock.Call
+type MockCoreAPIDevCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockCoreAPIDevCall) Return(arg0 api.DevAPI) *MockCoreAPIDevCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockCoreAPIDevCall) Do(f func() api.DevAPI) *MockCoreAPIDevCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockCoreAPIDevCall) DoAndReturn(f func() api.DevAPI) *MockCoreAPIDevCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Feed mocks base method.
 func (m *MockCoreAPI) Feed() api.FeedAPI {
 	m.ctrl.T.Helper()
@@ -300,11 +343,12 @@ func (c *MockBrokerAPIChainConfigCall) DoAndReturn(f func() *params.ChainConfig)
 }
 
 // ConsensusReady mocks base method.
-func (m *MockBrokerAPI) ConsensusReady() error {
+func (m *MockBrokerAPI) ConsensusReady() (bool, string) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ConsensusReady")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	return ret0, ret1
 }
 
 // ConsensusReady indicates an expected call of ConsensusReady.
@@ -320,19 +364,19 @@ type MockBrokerAPIConsensusReadyCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockBrokerAPIConsensusReadyCall) Return(arg0 error) *MockBrokerAPIConsensusReadyCall {
-	c.Call = c.Call.Return(arg0)
+func (c *MockBrokerAPIConsensusReadyCall) Return(arg0 bool, arg1 string) *MockBrokerAPIConsensusReadyCall {
+	c.Call = c.Call.Return(arg0, arg1)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockBrokerAPIConsensusReadyCall) Do(f func() error) *MockBrokerAPIConsensusReadyCall {
+func (c *MockBrokerAPIConsensusReadyCall) Do(f func() (bool, string)) *MockBrokerAPIConsensusReadyCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockBrokerAPIConsensusReadyCall) DoAndReturn(f func() error) *MockBrokerAPIConsensusReadyCall {
+func (c *MockBrokerAPIConsensusReadyCall) DoAndReturn(f func() (bool, string)) *MockBrokerAPIConsensusReadyCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -532,6 +576,45 @@ func (c *MockBrokerAPIGetBlockTxListCall) DoAndReturn(f func(uint64) ([]*types.T
 	return c
 }
 
+// GetBundleTx mocks base method.
+func (m *MockBrokerAPI) GetBundleTx(arg0 *types.Hash) (*ledger.BundleTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundleTx", arg0)
+	ret0, _ := ret[0].(*ledger.BundleTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundleTx indicates an expected call of GetBundleTx.
+func (mr *MockBrokerAPIMockRecorder) GetBundleTx(arg0 any) *MockBrokerAPIGetBundleTxCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundleTx", reflect.TypeOf((*MockBrokerAPI)(nil).GetBundleTx), arg0)
+	return &MockBrokerAPIGetBundleTxCall{Call: call}
+}
+
+// MockBrokerAPIGetBundleTxCall wrap *gomock.Call
+type MockBrokerAPIGetBundleTxCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBrokerAPIGetBundleTxCall) Return(arg0 *ledger.BundleTx, arg1 error) *MockBrokerAPIGetBundleTxCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBrokerAPIGetBundleTxCall) Do(f func(*types.Hash) (*ledger.BundleTx, error)) *MockBrokerAPIGetBundleTxCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBrokerAPIGetBundleTxCall) DoAndReturn(f func(*types.Hash) (*ledger.BundleTx, error)) *MockBrokerAPIGetBundleTxCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetEvm mocks base method.
 func (m *MockBrokerAPI) GetEvm(mes *core.Message, vmConfig *vm.Config) (*vm.EVM, error) {
 	m.ctrl.T.Helper()
@@ -571,6 +654,84 @@ func (c *MockBrokerAPIGetEvmCall) DoAndReturn(f func(*core.Message, *vm.Config)
 	return c
 }
 
+// GetInternalTxsByAddress mocks base method.
+func (m *MockBrokerAPI) GetInternalTxsByAddress(addr *types.Address, begin, end uint64) ([]*indexer.InternalTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalTxsByAddress", addr, begin, end)
+	ret0, _ := ret[0].([]*indexer.InternalTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalTxsByAddress indicates an expected call of GetInternalTxsByAddress.
+func (mr *MockBrokerAPIMockRecorder) GetInternalTxsByAddress(addr, begin, end any) *MockBrokerAPIGetInternalTxsByAddressCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalTxsByAddress", reflect.TypeOf((*MockBrokerAPI)(nil).GetInternalTxsByAddress), addr, begin, end)
+	return &MockBrokerAPIGetInternalTxsByAddressCall{Call: call}
+}
+
+// MockBrokerAPIGetInternalTxsByAddressCall wrap *gomock.Call
+type MockBrokerAPIGetInternalTxsByAddressCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBrokerAPIGetInternalTxsByAddressCall) Return(arg0 []*indexer.InternalTx, arg1 error) *MockBrokerAPIGetInternalTxsByAddressCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBrokerAPIGetInternalTxsByAddressCall) Do(f func(*types.Address, uint64, uint64) ([]*indexer.InternalTx, error)) *MockBrokerAPIGetInternalTxsByAddressCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBrokerAPIGetInternalTxsByAddressCall) DoAndReturn(f func(*types.Address, uint64, uint64) ([]*indexer.InternalTx, error)) *MockBrokerAPIGetInternalTxsByAddressCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetInternalTxsByHash mocks base method.
+func (m *MockBrokerAPI) GetInternalTxsByHash(hash *types.Hash) ([]*indexer.InternalTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInternalTxsByHash", hash)
+	ret0, _ := ret[0].([]*indexer.InternalTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInternalTxsByHash indicates an expected call of GetInternalTxsByHash.
+func (mr *MockBrokerAPIMockRecorder) GetInternalTxsByHash(hash any) *MockBrokerAPIGetInternalTxsByHashCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInternalTxsByHash", reflect.TypeOf((*MockBrokerAPI)(nil).GetInternalTxsByHash), hash)
+	return &MockBrokerAPIGetInternalTxsByHashCall{Call: call}
+}
+
+// MockBrokerAPIGetInternalTxsByHashCall wrap *gomock.Call
+type MockBrokerAPIGetInternalTxsByHashCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBrokerAPIGetInternalTxsByHashCall) Return(arg0 []*indexer.InternalTx, arg1 error) *MockBrokerAPIGetInternalTxsByHashCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBrokerAPIGetInternalTxsByHashCall) Do(f func(*types.Hash) ([]*indexer.InternalTx, error)) *MockBrokerAPIGetInternalTxsByHashCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBrokerAPIGetInternalTxsByHashCall) DoAndReturn(f func(*types.Hash) ([]*indexer.InternalTx, error)) *MockBrokerAPIGetInternalTxsByHashCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetReceipt mocks base method.
 func (m *MockBrokerAPI) GetReceipt(arg0 *types.Hash) (*types.Receipt, error) {
 	m.ctrl.T.Helper()
@@ -765,6 +926,45 @@ func (c *MockBrokerAPIGetTransactionMetaCall) DoAndReturn(f func(*types.Hash) (*
 	return c
 }
 
+// GetTransactionsByAddress mocks base method.
+func (m *MockBrokerAPI) GetTransactionsByAddress(addr *types.Address, begin, end, offset, limit uint64) ([]*ledger.AddressTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByAddress", addr, begin, end, offset, limit)
+	ret0, _ := ret[0].([]*ledger.AddressTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByAddress indicates an expected call of GetTransactionsByAddress.
+func (mr *MockBrokerAPIMockRecorder) GetTransactionsByAddress(addr, begin, end, offset, limit any) *MockBrokerAPIGetTransactionsByAddressCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByAddress", reflect.TypeOf((*MockBrokerAPI)(nil).GetTransactionsByAddress), addr, begin, end, offset, limit)
+	return &MockBrokerAPIGetTransactionsByAddressCall{Call: call}
+}
+
+// MockBrokerAPIGetTransactionsByAddressCall wrap *gomock.Call
+type MockBrokerAPIGetTransactionsByAddressCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBrokerAPIGetTransactionsByAddressCall) Return(arg0 []*ledger.AddressTx, arg1 error) *MockBrokerAPIGetTransactionsByAddressCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBrokerAPIGetTransactionsByAddressCall) Do(f func(*types.Address, uint64, uint64, uint64, uint64) ([]*ledger.AddressTx, error)) *MockBrokerAPIGetTransactionsByAddressCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBrokerAPIGetTransactionsByAddressCall) DoAndReturn(f func(*types.Address, uint64, uint64, uint64, uint64) ([]*ledger.AddressTx, error)) *MockBrokerAPIGetTransactionsByAddressCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetViewStateLedger mocks base method.
 func (m *MockBrokerAPI) GetViewStateLedger() ledger.StateLedger {
 	m.ctrl.T.Helper()
@@ -803,6 +1003,83 @@ func (c *MockBrokerAPIGetViewStateLedgerCall) DoAndReturn(f func() ledger.StateL
 	return c
 }
 
+// HandleBundle mocks base method.
+func (m *MockBrokerAPI) HandleBundle(txs []*types.Transaction, maxBlock uint64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleBundle", txs, maxBlock)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandleBundle indicates an expected call of HandleBundle.
+func (mr *MockBrokerAPIMockRecorder) HandleBundle(txs, maxBlock any) *MockBrokerAPIHandleBundleCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleBundle", reflect.TypeOf((*MockBrokerAPI)(nil).HandleBundle), txs, maxBlock)
+	return &MockBrokerAPIHandleBundleCall{Call: call}
+}
+
+// MockBrokerAPIHandleBundleCall wrap *gomock.Call
+type MockBrokerAPIHandleBundleCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBrokerAPIHandleBundleCall) Return(arg0 string, arg1 error) *MockBrokerAPIHandleBundleCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBrokerAPIHandleBundleCall) Do(f func([]*types.Transaction, uint64) (string, error)) *MockBrokerAPIHandleBundleCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBrokerAPIHandleBundleCall) DoAndReturn(f func([]*types.Transaction, uint64) (string, error)) *MockBrokerAPIHandleBundleCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// HandlePrivateTransaction mocks base method.
+func (m *MockBrokerAPI) HandlePrivateTransaction(tx *types.Transaction, maxBlock uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandlePrivateTransaction", tx, maxBlock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandlePrivateTransaction indicates an expected call of HandlePrivateTransaction.
+func (mr *MockBrokerAPIMockRecorder) HandlePrivateTransaction(tx, maxBlock any) *MockBrokerAPIHandlePrivateTransactionCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandlePrivateTransaction", reflect.TypeOf((*MockBrokerAPI)(nil).HandlePrivateTransaction), tx, maxBlock)
+	return &MockBrokerAPIHandlePrivateTransactionCall{Call: call}
+}
+
+// MockBrokerAPIHandlePrivateTransactionCall wrap *gomock.Call
+type MockBrokerAPIHandlePrivateTransactionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockBrokerAPIHandlePrivateTransactionCall) Return(arg0 error) *MockBrokerAPIHandlePrivateTransactionCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockBrokerAPIHandlePrivateTransactionCall) Do(f func(*types.Transaction, uint64) error) *MockBrokerAPIHandlePrivateTransactionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockBrokerAPIHandlePrivateTransactionCall) DoAndReturn(f func(*types.Transaction, uint64) error) *MockBrokerAPIHandlePrivateTransactionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // HandleTransaction mocks base method.
 func (m *MockBrokerAPI) HandleTransaction(tx *types.Transaction) error {
 	m.ctrl.T.Helper()
@@ -1145,6 +1422,80 @@ func (c *MockFeedAPIBloomStatusCall) DoAndReturn(f func() (uint64, uint64)) *Moc
 	return c
 }
 
+// ServiceFilter mocks base method.
+func (m *MockFeedAPI) ServiceFilter(session *bloombits.MatcherSession) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ServiceFilter", session)
+}
+
+// ServiceFilter indicates an expected call of ServiceFilter.
+func (mr *MockFeedAPIMockRecorder) ServiceFilter(session any) *MockFeedAPIServiceFilterCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceFilter", reflect.TypeOf((*MockFeedAPI)(nil).ServiceFilter), session)
+	return &MockFeedAPIServiceFilterCall{Call: call}
+}
+
+// MockFeedAPIServiceFilterCall wrap *gomock.Call
+type MockFeedAPIServiceFilterCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockFeedAPIServiceFilterCall) Return() *MockFeedAPIServiceFilterCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockFeedAPIServiceFilterCall) Do(f func(*bloombits.MatcherSession)) *MockFeedAPIServiceFilterCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockFeedAPIServiceFilterCall) DoAndReturn(f func(*bloombits.MatcherSession)) *MockFeedAPIServiceFilterCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SubscribeDroppedTxEvent mocks base method.
+func (m *MockFeedAPI) SubscribeDroppedTxEvent(arg0 chan<- []*txpool.DroppedTx) event.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeDroppedTxEvent", arg0)
+	ret0, _ := ret[0].(event.Subscription)
+	return ret0
+}
+
+// SubscribeDroppedTxEvent indicates an expected call of SubscribeDroppedTxEvent.
+func (mr *MockFeedAPIMockRecorder) SubscribeDroppedTxEvent(arg0 any) *MockFeedAPISubscribeDroppedTxEventCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeDroppedTxEvent", reflect.TypeOf((*MockFeedAPI)(nil).SubscribeDroppedTxEvent), arg0)
+	return &MockFeedAPISubscribeDroppedTxEventCall{Call: call}
+}
+
+// MockFeedAPISubscribeDroppedTxEventCall wrap *gomock.Call
+type MockFeedAPISubscribeDroppedTxEventCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockFeedAPISubscribeDroppedTxEventCall) Return(arg0 event.Subscription) *MockFeedAPISubscribeDroppedTxEventCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockFeedAPISubscribeDroppedTxEventCall) Do(f func(chan<- []*txpool.DroppedTx) event.Subscription) *MockFeedAPISubscribeDroppedTxEventCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockFeedAPISubscribeDroppedTxEventCall) DoAndReturn(f func(chan<- []*txpool.DroppedTx) event.Subscription) *MockFeedAPISubscribeDroppedTxEventCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // SubscribeLogsEvent mocks base method.
 func (m *MockFeedAPI) SubscribeLogsEvent(arg0 chan<- []*types.EvmLog) event.Subscription {
 	m.ctrl.T.Helper()
@@ -1320,116 +1671,116 @@ func (c *MockTxPoolAPIGetAccountMetaCall) DoAndReturn(f func(string, bool) any)
 	return c
 }
 
-// GetChainInfo mocks base method.
-func (m *MockTxPoolAPI) GetChainInfo() any {
+// GetMeta mocks base method.
+func (m *MockTxPoolAPI) GetMeta(full bool) any {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetChainInfo")
+	ret := m.ctrl.Call(m, "GetMeta", full)
 	ret0, _ := ret[0].(any)
 	return ret0
 }
 
-// GetChainInfo indicates an expected call of GetChainInfo.
-func (mr *MockTxPoolAPIMockRecorder) GetChainInfo() *MockTxPoolAPIGetChainInfoCall {
+// GetMeta indicates an expected call of GetMeta.
+func (mr *MockTxPoolAPIMockRecorder) GetMeta(full any) *MockTxPoolAPIGetMetaCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChainInfo", reflect.TypeOf((*MockTxPoolAPI)(nil).GetChainInfo))
-	return &MockTxPoolAPIGetChainInfoCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMeta", reflect.TypeOf((*MockTxPoolAPI)(nil).GetMeta), full)
+	return &MockTxPoolAPIGetMetaCall{Call: call}
 }
 
-// MockTxPoolAPIGetChainInfoCall wrap *gomock.Call
-type MockTxPoolAPIGetChainInfoCall struct {
+// MockTxPoolAPIGetMetaCall wrap *gomock.Call
+type MockTxPoolAPIGetMetaCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockTxPoolAPIGetChainInfoCall) Return(arg0 any) *MockTxPoolAPIGetChainInfoCall {
+func (c *MockTxPoolAPIGetMetaCall) Return(arg0 any) *MockTxPoolAPIGetMetaCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockTxPoolAPIGetChainInfoCall) Do(f func() any) *MockTxPoolAPIGetChainInfoCall {
+func (c *MockTxPoolAPIGetMetaCall) Do(f func(bool) any) *MockTxPoolAPIGetMetaCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockTxPoolAPIGetChainInfoCall) DoAndReturn(f func() any) *MockTxPoolAPIGetChainInfoCall {
+func (c *MockTxPoolAPIGetMetaCall) DoAndReturn(f func(bool) any) *MockTxPoolAPIGetMetaCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// GetMeta mocks base method.
-func (m *MockTxPoolAPI) GetMeta(full bool) any {
+// GetPendingTxCountByAccount mocks base method.
+func (m *MockTxPoolAPI) GetPendingTxCountByAccount(account string) uint64 {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMeta", full)
-	ret0, _ := ret[0].(any)
-	return ret0
+	ret := m.ctrl.Call(m, "GetPendingTxCountByAccount", account)
+	ret0, _ := ret[0].(uint64)
+	return ret0
 }
 
-// GetMeta indicates an expected call of GetMeta.
-func (mr *MockTxPoolAPIMockRecorder) GetMeta(full any) *MockTxPoolAPIGetMetaCall {
+// GetPendingTxCountByAccount indicates an expected call of GetPendingTxCountByAccount.
+func (mr *MockTxPoolAPIMockRecorder) GetPendingTxCountByAccount(account any) *MockTxPoolAPIGetPendingTxCountByAccountCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMeta", reflect.TypeOf((*MockTxPoolAPI)(nil).GetMeta), full)
-	return &MockTxPoolAPIGetMetaCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTxCountByAccount", reflect.TypeOf((*MockTxPoolAPI)(nil).GetPendingTxCountByAccount), account)
+	return &MockTxPoolAPIGetPendingTxCountByAccountCall{Call: call}
 }
 
-// MockTxPoolAPIGetMetaCall wrap *gomock.Call
-type MockTxPoolAPIGetMetaCall struct {
+// MockTxPoolAPIGetPendingTxCountByAccountCall wrap *gomock.Call
+type MockTxPoolAPIGetPendingTxCountByAccountCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockTxPoolAPIGetMetaCall) Return(arg0 any) *MockTxPoolAPIGetMetaCall {
+func (c *MockTxPoolAPIGetPendingTxCountByAccountCall) Return(arg0 uint64) *MockTxPoolAPIGetPendingTxCountByAccountCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockTxPoolAPIGetMetaCall) Do(f func(bool) any) *MockTxPoolAPIGetMetaCall {
+func (c *MockTxPoolAPIGetPendingTxCountByAccountCall) Do(f func(string) uint64) *MockTxPoolAPIGetPendingTxCountByAccountCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockTxPoolAPIGetMetaCall) DoAndReturn(f func(bool) any) *MockTxPoolAPIGetMetaCall {
+func (c *MockTxPoolAPIGetPendingTxCountByAccountCall) DoAndReturn(f func(string) uint64) *MockTxPoolAPIGetPendingTxCountByAccountCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
 
-// GetPendingTxCountByAccount mocks base method.
-func (m *MockTxPoolAPI) GetPendingTxCountByAccount(account string) uint64 {
+// GetQuota mocks base method.
+func (m *MockTxPoolAPI) GetQuota() *txpool.Quota {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingTxCountByAccount", account)
-	ret0, _ := ret[0].(uint64)
+	ret := m.ctrl.Call(m, "GetQuota")
+	ret0, _ := ret[0].(*txpool.Quota)
 	return ret0
 }
 
-// GetPendingTxCountByAccount indicates an expected call of GetPendingTxCountByAccount.
-func (mr *MockTxPoolAPIMockRecorder) GetPendingTxCountByAccount(account any) *MockTxPoolAPIGetPendingTxCountByAccountCall {
+// GetQuota indicates an expected call of GetQuota.
+func (mr *MockTxPoolAPIMockRecorder) GetQuota() *MockTxPoolAPIGetQuotaCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTxCountByAccount", reflect.TypeOf((*MockTxPoolAPI)(nil).GetPendingTxCountByAccount), account)
-	return &MockTxPoolAPIGetPendingTxCountByAccountCall{Call: call}
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuota", reflect.TypeOf((*MockTxPoolAPI)(nil).GetQuota))
+	return &MockTxPoolAPIGetQuotaCall{Call: call}
 }
 
-// MockTxPoolAPIGetPendingTxCountByAccountCall wrap *gomock.Call
-type MockTxPoolAPIGetPendingTxCountByAccountCall struct {
+// MockTxPoolAPIGetQuotaCall wrap *gomock.Call
+type MockTxPoolAPIGetQuotaCall struct {
 	*gomock.Call
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *MockTxPoolAPIGetPendingTxCountByAccountCall) Return(arg0 uint64) *MockTxPoolAPIGetPendingTxCountByAccountCall {
+func (c *MockTxPoolAPIGetQuotaCall) Return(arg0 *txpool.Quota) *MockTxPoolAPIGetQuotaCall {
 	c.Call = c.Call.Return(arg0)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *MockTxPoolAPIGetPendingTxCountByAccountCall) Do(f func(string) uint64) *MockTxPoolAPIGetPendingTxCountByAccountCall {
+func (c *MockTxPoolAPIGetQuotaCall) Do(f func() *txpool.Quota) *MockTxPoolAPIGetQuotaCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *MockTxPoolAPIGetPendingTxCountByAccountCall) DoAndReturn(f func(string) uint64) *MockTxPoolAPIGetPendingTxCountByAccountCall {
+func (c *MockTxPoolAPIGetQuotaCall) DoAndReturn(f func() *txpool.Quota) *MockTxPoolAPIGetQuotaCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -1510,6 +1861,44 @@ func (c *MockTxPoolAPIGetTransactionCall) DoAndReturn(f func(*types.Hash) *types
 	return c
 }
 
+// GetTxStatus mocks base method.
+func (m *MockTxPoolAPI) GetTxStatus(hash string) *txpool.TxStatusInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxStatus", hash)
+	ret0, _ := ret[0].(*txpool.TxStatusInfo)
+	return ret0
+}
+
+// GetTxStatus indicates an expected call of GetTxStatus.
+func (mr *MockTxPoolAPIMockRecorder) GetTxStatus(hash any) *MockTxPoolAPIGetTxStatusCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxStatus", reflect.TypeOf((*MockTxPoolAPI)(nil).GetTxStatus), hash)
+	return &MockTxPoolAPIGetTxStatusCall{Call: call}
+}
+
+// MockTxPoolAPIGetTxStatusCall wrap *gomock.Call
+type MockTxPoolAPIGetTxStatusCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockTxPoolAPIGetTxStatusCall) Return(arg0 *txpool.TxStatusInfo) *MockTxPoolAPIGetTxStatusCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockTxPoolAPIGetTxStatusCall) Do(f func(string) *txpool.TxStatusInfo) *MockTxPoolAPIGetTxStatusCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockTxPoolAPIGetTxStatusCall) DoAndReturn(f func(string) *txpool.TxStatusInfo) *MockTxPoolAPIGetTxStatusCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // IsStarted mocks base method.
 func (m *MockTxPoolAPI) IsStarted() bool {
 	m.ctrl.T.Helper()
@@ -1547,3 +1936,561 @@ func (c *MockTxPoolAPIIsStartedCall) DoAndReturn(f func() bool) *MockTxPoolAPIIs
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// MockDevAPI is a mock of DevAPI interface.
+type MockDevAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockDevAPIMockRecorder
+}
+
+// MockDevAPIMockRecorder is the mock recorder for MockDevAPI.
+type MockDevAPIMockRecorder struct {
+	mock *MockDevAPI
+}
+
+// NewMockDevAPI creates a new mock instance.
+func NewMockDevAPI(ctrl *gomock.Controller) *MockDevAPI {
+	mock := &MockDevAPI{ctrl: ctrl}
+	mock.recorder = &MockDevAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDevAPI) EXPECT() *MockDevAPIMockRecorder {
+	return m.recorder
+}
+
+// ImpersonateAccount mocks base method.
+func (m *MockDevAPI) ImpersonateAccount(address *types.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImpersonateAccount", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImpersonateAccount indicates an expected call of ImpersonateAccount.
+func (mr *MockDevAPIMockRecorder) ImpersonateAccount(address any) *MockDevAPIImpersonateAccountCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImpersonateAccount", reflect.TypeOf((*MockDevAPI)(nil).ImpersonateAccount), address)
+	return &MockDevAPIImpersonateAccountCall{Call: call}
+}
+
+// MockDevAPIImpersonateAccountCall wrap *gomock.Call
+type MockDevAPIImpersonateAccountCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPIImpersonateAccountCall) Return(arg0 error) *MockDevAPIImpersonateAccountCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPIImpersonateAccountCall) Do(f func(*types.Address) error) *MockDevAPIImpersonateAccountCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPIImpersonateAccountCall) DoAndReturn(f func(*types.Address) error) *MockDevAPIImpersonateAccountCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// IncreaseTime mocks base method.
+func (m *MockDevAPI) IncreaseTime(seconds int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseTime", seconds)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncreaseTime indicates an expected call of IncreaseTime.
+func (mr *MockDevAPIMockRecorder) IncreaseTime(seconds any) *MockDevAPIIncreaseTimeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseTime", reflect.TypeOf((*MockDevAPI)(nil).IncreaseTime), seconds)
+	return &MockDevAPIIncreaseTimeCall{Call: call}
+}
+
+// MockDevAPIIncreaseTimeCall wrap *gomock.Call
+type MockDevAPIIncreaseTimeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPIIncreaseTimeCall) Return(arg0 int64, arg1 error) *MockDevAPIIncreaseTimeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPIIncreaseTimeCall) Do(f func(int64) (int64, error)) *MockDevAPIIncreaseTimeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPIIncreaseTimeCall) DoAndReturn(f func(int64) (int64, error)) *MockDevAPIIncreaseTimeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// IsImpersonated mocks base method.
+func (m *MockDevAPI) IsImpersonated(address *types.Address) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsImpersonated", address)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsImpersonated indicates an expected call of IsImpersonated.
+func (mr *MockDevAPIMockRecorder) IsImpersonated(address any) *MockDevAPIIsImpersonatedCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsImpersonated", reflect.TypeOf((*MockDevAPI)(nil).IsImpersonated), address)
+	return &MockDevAPIIsImpersonatedCall{Call: call}
+}
+
+// MockDevAPIIsImpersonatedCall wrap *gomock.Call
+type MockDevAPIIsImpersonatedCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPIIsImpersonatedCall) Return(arg0 bool) *MockDevAPIIsImpersonatedCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPIIsImpersonatedCall) Do(f func(*types.Address) bool) *MockDevAPIIsImpersonatedCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPIIsImpersonatedCall) DoAndReturn(f func(*types.Address) bool) *MockDevAPIIsImpersonatedCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Mine mocks base method.
+func (m *MockDevAPI) Mine(timestamp int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mine", timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Mine indicates an expected call of Mine.
+func (mr *MockDevAPIMockRecorder) Mine(timestamp any) *MockDevAPIMineCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mine", reflect.TypeOf((*MockDevAPI)(nil).Mine), timestamp)
+	return &MockDevAPIMineCall{Call: call}
+}
+
+// MockDevAPIMineCall wrap *gomock.Call
+type MockDevAPIMineCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPIMineCall) Return(arg0 error) *MockDevAPIMineCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPIMineCall) Do(f func(int64) error) *MockDevAPIMineCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPIMineCall) DoAndReturn(f func(int64) error) *MockDevAPIMineCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Revert mocks base method.
+func (m *MockDevAPI) Revert(id uint64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revert", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revert indicates an expected call of Revert.
+func (mr *MockDevAPIMockRecorder) Revert(id any) *MockDevAPIRevertCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockDevAPI)(nil).Revert), id)
+	return &MockDevAPIRevertCall{Call: call}
+}
+
+// MockDevAPIRevertCall wrap *gomock.Call
+type MockDevAPIRevertCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPIRevertCall) Return(arg0 bool, arg1 error) *MockDevAPIRevertCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPIRevertCall) Do(f func(uint64) (bool, error)) *MockDevAPIRevertCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPIRevertCall) DoAndReturn(f func(uint64) (bool, error)) *MockDevAPIRevertCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetAutomine mocks base method.
+func (m *MockDevAPI) SetAutomine(enable bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAutomine", enable)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAutomine indicates an expected call of SetAutomine.
+func (mr *MockDevAPIMockRecorder) SetAutomine(enable any) *MockDevAPISetAutomineCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAutomine", reflect.TypeOf((*MockDevAPI)(nil).SetAutomine), enable)
+	return &MockDevAPISetAutomineCall{Call: call}
+}
+
+// MockDevAPISetAutomineCall wrap *gomock.Call
+type MockDevAPISetAutomineCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISetAutomineCall) Return(arg0 error) *MockDevAPISetAutomineCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISetAutomineCall) Do(f func(bool) error) *MockDevAPISetAutomineCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISetAutomineCall) DoAndReturn(f func(bool) error) *MockDevAPISetAutomineCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetBalance mocks base method.
+func (m *MockDevAPI) SetBalance(address *types.Address, balance *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBalance", address, balance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBalance indicates an expected call of SetBalance.
+func (mr *MockDevAPIMockRecorder) SetBalance(address, balance any) *MockDevAPISetBalanceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBalance", reflect.TypeOf((*MockDevAPI)(nil).SetBalance), address, balance)
+	return &MockDevAPISetBalanceCall{Call: call}
+}
+
+// MockDevAPISetBalanceCall wrap *gomock.Call
+type MockDevAPISetBalanceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISetBalanceCall) Return(arg0 error) *MockDevAPISetBalanceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISetBalanceCall) Do(f func(*types.Address, *big.Int) error) *MockDevAPISetBalanceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISetBalanceCall) DoAndReturn(f func(*types.Address, *big.Int) error) *MockDevAPISetBalanceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetCode mocks base method.
+func (m *MockDevAPI) SetCode(address *types.Address, code []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCode", address, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCode indicates an expected call of SetCode.
+func (mr *MockDevAPIMockRecorder) SetCode(address, code any) *MockDevAPISetCodeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCode", reflect.TypeOf((*MockDevAPI)(nil).SetCode), address, code)
+	return &MockDevAPISetCodeCall{Call: call}
+}
+
+// MockDevAPISetCodeCall wrap *gomock.Call
+type MockDevAPISetCodeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISetCodeCall) Return(arg0 error) *MockDevAPISetCodeCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISetCodeCall) Do(f func(*types.Address, []byte) error) *MockDevAPISetCodeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISetCodeCall) DoAndReturn(f func(*types.Address, []byte) error) *MockDevAPISetCodeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetIntervalMining mocks base method.
+func (m *MockDevAPI) SetIntervalMining(interval time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIntervalMining", interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetIntervalMining indicates an expected call of SetIntervalMining.
+func (mr *MockDevAPIMockRecorder) SetIntervalMining(interval any) *MockDevAPISetIntervalMiningCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIntervalMining", reflect.TypeOf((*MockDevAPI)(nil).SetIntervalMining), interval)
+	return &MockDevAPISetIntervalMiningCall{Call: call}
+}
+
+// MockDevAPISetIntervalMiningCall wrap *gomock.Call
+type MockDevAPISetIntervalMiningCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISetIntervalMiningCall) Return(arg0 error) *MockDevAPISetIntervalMiningCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISetIntervalMiningCall) Do(f func(time.Duration) error) *MockDevAPISetIntervalMiningCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISetIntervalMiningCall) DoAndReturn(f func(time.Duration) error) *MockDevAPISetIntervalMiningCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetNextBlockTimestamp mocks base method.
+func (m *MockDevAPI) SetNextBlockTimestamp(timestamp int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNextBlockTimestamp", timestamp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNextBlockTimestamp indicates an expected call of SetNextBlockTimestamp.
+func (mr *MockDevAPIMockRecorder) SetNextBlockTimestamp(timestamp any) *MockDevAPISetNextBlockTimestampCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNextBlockTimestamp", reflect.TypeOf((*MockDevAPI)(nil).SetNextBlockTimestamp), timestamp)
+	return &MockDevAPISetNextBlockTimestampCall{Call: call}
+}
+
+// MockDevAPISetNextBlockTimestampCall wrap *gomock.Call
+type MockDevAPISetNextBlockTimestampCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISetNextBlockTimestampCall) Return(arg0 error) *MockDevAPISetNextBlockTimestampCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISetNextBlockTimestampCall) Do(f func(int64) error) *MockDevAPISetNextBlockTimestampCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISetNextBlockTimestampCall) DoAndReturn(f func(int64) error) *MockDevAPISetNextBlockTimestampCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetNonce mocks base method.
+func (m *MockDevAPI) SetNonce(address *types.Address, nonce uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNonce", address, nonce)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNonce indicates an expected call of SetNonce.
+func (mr *MockDevAPIMockRecorder) SetNonce(address, nonce any) *MockDevAPISetNonceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNonce", reflect.TypeOf((*MockDevAPI)(nil).SetNonce), address, nonce)
+	return &MockDevAPISetNonceCall{Call: call}
+}
+
+// MockDevAPISetNonceCall wrap *gomock.Call
+type MockDevAPISetNonceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISetNonceCall) Return(arg0 error) *MockDevAPISetNonceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISetNonceCall) Do(f func(*types.Address, uint64) error) *MockDevAPISetNonceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISetNonceCall) DoAndReturn(f func(*types.Address, uint64) error) *MockDevAPISetNonceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetStorageAt mocks base method.
+func (m *MockDevAPI) SetStorageAt(address *types.Address, key, value []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStorageAt", address, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStorageAt indicates an expected call of SetStorageAt.
+func (mr *MockDevAPIMockRecorder) SetStorageAt(address, key, value any) *MockDevAPISetStorageAtCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStorageAt", reflect.TypeOf((*MockDevAPI)(nil).SetStorageAt), address, key, value)
+	return &MockDevAPISetStorageAtCall{Call: call}
+}
+
+// MockDevAPISetStorageAtCall wrap *gomock.Call
+type MockDevAPISetStorageAtCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISetStorageAtCall) Return(arg0 error) *MockDevAPISetStorageAtCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISetStorageAtCall) Do(f func(*types.Address, []byte, []byte) error) *MockDevAPISetStorageAtCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISetStorageAtCall) DoAndReturn(f func(*types.Address, []byte, []byte) error) *MockDevAPISetStorageAtCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Snapshot mocks base method.
+func (m *MockDevAPI) Snapshot() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockDevAPIMockRecorder) Snapshot() *MockDevAPISnapshotCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockDevAPI)(nil).Snapshot))
+	return &MockDevAPISnapshotCall{Call: call}
+}
+
+// MockDevAPISnapshotCall wrap *gomock.Call
+type MockDevAPISnapshotCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPISnapshotCall) Return(arg0 uint64, arg1 error) *MockDevAPISnapshotCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPISnapshotCall) Do(f func() (uint64, error)) *MockDevAPISnapshotCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPISnapshotCall) DoAndReturn(f func() (uint64, error)) *MockDevAPISnapshotCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// StopImpersonatingAccount mocks base method.
+func (m *MockDevAPI) StopImpersonatingAccount(address *types.Address) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopImpersonatingAccount", address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopImpersonatingAccount indicates an expected call of StopImpersonatingAccount.
+func (mr *MockDevAPIMockRecorder) StopImpersonatingAccount(address any) *MockDevAPIStopImpersonatingAccountCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopImpersonatingAccount", reflect.TypeOf((*MockDevAPI)(nil).StopImpersonatingAccount), address)
+	return &MockDevAPIStopImpersonatingAccountCall{Call: call}
+}
+
+// MockDevAPIStopImpersonatingAccountCall wrap *gomock.Call
+type MockDevAPIStopImpersonatingAccountCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockDevAPIStopImpersonatingAccountCall) Return(arg0 error) *MockDevAPIStopImpersonatingAccountCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockDevAPIStopImpersonatingAccountCall) Do(f func(*types.Address) error) *MockDevAPIStopImpersonatingAccountCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockDevAPIStopImpersonatingAccountCall) DoAndReturn(f func(*types.Address) error) *MockDevAPIStopImpersonatingAccountCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
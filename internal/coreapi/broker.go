@@ -14,12 +14,15 @@ import (
 	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
 	"github.com/axiomesh/axiom-ledger/internal/executor"
 	syscommon "github.com/axiomesh/axiom-ledger/internal/executor/system/common"
+	"github.com/axiomesh/axiom-ledger/internal/indexer"
 	"github.com/axiomesh/axiom-ledger/internal/ledger"
 	"github.com/axiomesh/axiom-ledger/internal/sync/common"
 )
 
 type BrokerAPI CoreAPI
 
+var ErrInternalTxIndexerDisabled = errors.New("internal tx indexer is disabled")
+
 var _ api.BrokerAPI = (*BrokerAPI)(nil)
 
 func (b *BrokerAPI) HandleTransaction(tx *types.Transaction) error {
@@ -42,6 +45,55 @@ func (b *BrokerAPI) HandleTransaction(tx *types.Transaction) error {
 	return nil
 }
 
+// HandlePrivateTransaction adds the tx to the local txpool only, the tx is never broadcast to other nodes
+// and is dropped once the chain height reaches maxBlock, 0 means no limit.
+func (b *BrokerAPI) HandlePrivateTransaction(tx *types.Transaction, maxBlock uint64) error {
+	if b.axiomLedger.Repo.StartArgs.ReadonlyMode {
+		return errors.New("readonly mode cannot process tx")
+	}
+
+	if tx.GetHash() == nil {
+		return errors.New("transaction hash is nil")
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"hash":      tx.GetHash().String(),
+		"max_block": maxBlock,
+	}).Debugf("Receive private tx")
+
+	if err := b.axiomLedger.Consensus.PreparePrivate(tx, maxBlock); err != nil {
+		return fmt.Errorf("consensus prepare for private tx %s failed: %w", tx.GetHash().String(), err)
+	}
+
+	return nil
+}
+
+// HandleBundle adds the txs as a bundle to the local txpool, the bundle txs are placed consecutively
+// in the batch generated by this node or not at all, returns the bundle id.
+func (b *BrokerAPI) HandleBundle(txs []*types.Transaction, maxBlock uint64) (string, error) {
+	if b.axiomLedger.Repo.StartArgs.ReadonlyMode {
+		return "", errors.New("readonly mode cannot process tx")
+	}
+
+	for _, tx := range txs {
+		if tx.GetHash() == nil {
+			return "", errors.New("transaction hash is nil")
+		}
+	}
+
+	bundleID, err := b.axiomLedger.Consensus.PrepareBundle(txs, maxBlock)
+	if err != nil {
+		return "", fmt.Errorf("consensus prepare for bundle failed: %w", err)
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"bundle":    bundleID,
+		"size":      len(txs),
+		"max_block": maxBlock,
+	}).Debugf("Receive bundle")
+	return bundleID, nil
+}
+
 func (b *BrokerAPI) GetTransaction(hash *types.Hash) (*types.Transaction, error) {
 	return b.axiomLedger.ViewLedger.ChainLedger.GetTransaction(hash)
 }
@@ -50,6 +102,10 @@ func (b *BrokerAPI) GetTransactionMeta(hash *types.Hash) (*types.TransactionMeta
 	return b.axiomLedger.ViewLedger.ChainLedger.GetTransactionMeta(hash)
 }
 
+func (b *BrokerAPI) GetTransactionsByAddress(addr *types.Address, begin, end uint64, offset, limit uint64) ([]*ledger.AddressTx, error) {
+	return b.axiomLedger.ViewLedger.ChainLedger.GetTransactionsByAddress(addr, begin, end, offset, limit)
+}
+
 func (b *BrokerAPI) GetReceipts(blockNum uint64) ([]*types.Receipt, error) {
 	return b.axiomLedger.ViewLedger.ChainLedger.GetBlockReceipts(blockNum)
 }
@@ -58,6 +114,10 @@ func (b *BrokerAPI) GetReceipt(hash *types.Hash) (*types.Receipt, error) {
 	return b.axiomLedger.ViewLedger.ChainLedger.GetReceipt(hash)
 }
 
+func (b *BrokerAPI) GetBundleTx(hash *types.Hash) (*ledger.BundleTx, error) {
+	return b.axiomLedger.ViewLedger.ChainLedger.GetBundleTx(hash)
+}
+
 func (b *BrokerAPI) GetBlockHeaderByNumber(height uint64) (*types.BlockHeader, error) {
 	return b.axiomLedger.ViewLedger.ChainLedger.GetBlockHeader(height)
 }
@@ -155,6 +215,20 @@ func (b *BrokerAPI) GetSyncProgress() *common.SyncProgress {
 	return b.axiomLedger.Sync.GetSyncProgress()
 }
 
+func (b *BrokerAPI) GetInternalTxsByHash(hash *types.Hash) ([]*indexer.InternalTx, error) {
+	if b.axiomLedger.InternalTxIndexer == nil {
+		return nil, ErrInternalTxIndexerDisabled
+	}
+	return b.axiomLedger.InternalTxIndexer.GetInternalTxsByHash(hash.ETHHash())
+}
+
+func (b *BrokerAPI) GetInternalTxsByAddress(addr *types.Address, begin, end uint64) ([]*indexer.InternalTx, error) {
+	if b.axiomLedger.InternalTxIndexer == nil {
+		return nil, ErrInternalTxIndexerDisabled
+	}
+	return b.axiomLedger.InternalTxIndexer.GetInternalTxsByAddress(addr.ETHAddress(), begin, end)
+}
+
 func getBlockHashFunc(block *types.Block) vm.GetHashFunc {
 	return func(n uint64) ethcommon.Hash {
 		hash := block.Hash()
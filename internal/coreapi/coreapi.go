@@ -39,6 +39,10 @@ func (api *CoreAPI) TxPool() api.TxPoolAPI {
 	return (*TxPoolAPI)(api)
 }
 
+func (api *CoreAPI) Dev() api.DevAPI {
+	return (*DevAPI)(api)
+}
+
 func (api *CoreAPI) ChainState() *chainstate.ChainState {
 	return api.axiomLedger.ChainState
 }
@@ -0,0 +1,149 @@
+package coreapi
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/consensus/solo_dev"
+	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	"github.com/axiomesh/axiom-ledger/internal/executor"
+	"github.com/axiomesh/axiom-ledger/internal/ledger"
+)
+
+var (
+	ErrDevModeDisabled          = errors.New("dev mode is only supported by the solo_dev consensus")
+	ErrImpersonationDisabled    = errors.New("account impersonation is only supported by the solo_dev consensus")
+	ErrStateOverrideUnsupported = errors.New("state setters are only supported by the solo_dev consensus with the native executor")
+)
+
+type DevAPI CoreAPI
+
+var _ api.DevAPI = (*DevAPI)(nil)
+
+func (api *DevAPI) node() (*solo_dev.NodeDev, error) {
+	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
+		return nil, ErrDevModeDisabled
+	}
+	node, ok := api.axiomLedger.Consensus.(*solo_dev.NodeDev)
+	if !ok {
+		return nil, ErrDevModeDisabled
+	}
+	return node, nil
+}
+
+func (api *DevAPI) Mine(timestamp int64) error {
+	node, err := api.node()
+	if err != nil {
+		return err
+	}
+	return node.Mine(timestamp)
+}
+
+func (api *DevAPI) IncreaseTime(seconds int64) (int64, error) {
+	node, err := api.node()
+	if err != nil {
+		return 0, err
+	}
+	return node.IncreaseTime(seconds), nil
+}
+
+func (api *DevAPI) SetNextBlockTimestamp(timestamp int64) error {
+	node, err := api.node()
+	if err != nil {
+		return err
+	}
+	return node.SetNextBlockTimestamp(timestamp)
+}
+
+func (api *DevAPI) Snapshot() (uint64, error) {
+	node, err := api.node()
+	if err != nil {
+		return 0, err
+	}
+	return node.Snapshot(), nil
+}
+
+func (api *DevAPI) Revert(id uint64) (bool, error) {
+	node, err := api.node()
+	if err != nil {
+		return false, err
+	}
+	return node.Revert(id)
+}
+
+func (api *DevAPI) SetAutomine(enable bool) error {
+	node, err := api.node()
+	if err != nil {
+		return err
+	}
+	return node.SetAutomine(enable)
+}
+
+func (api *DevAPI) SetIntervalMining(interval time.Duration) error {
+	node, err := api.node()
+	if err != nil {
+		return err
+	}
+	node.SetIntervalMining(interval)
+	return nil
+}
+
+// overrideState writes the state with the next block, which is mined at once to make the writes visible.
+func (api *DevAPI) overrideState(override executor.StateOverride) error {
+	node, err := api.node()
+	if err != nil {
+		return err
+	}
+	blockExecutor, ok := api.axiomLedger.BlockExecutor.(*executor.BlockExecutor)
+	if !ok {
+		return ErrStateOverrideUnsupported
+	}
+	blockExecutor.AddStateOverride(override)
+	return node.MineEmptyBlock()
+}
+
+func (api *DevAPI) SetBalance(address *types.Address, balance *big.Int) error {
+	return api.overrideState(func(stateLedger ledger.StateAccessor) {
+		stateLedger.SetBalance(address, balance)
+	})
+}
+
+func (api *DevAPI) SetCode(address *types.Address, code []byte) error {
+	return api.overrideState(func(stateLedger ledger.StateAccessor) {
+		stateLedger.SetCode(address, code)
+	})
+}
+
+func (api *DevAPI) SetStorageAt(address *types.Address, key, value []byte) error {
+	return api.overrideState(func(stateLedger ledger.StateAccessor) {
+		stateLedger.SetState(address, key, value)
+	})
+}
+
+func (api *DevAPI) SetNonce(address *types.Address, nonce uint64) error {
+	return api.overrideState(func(stateLedger ledger.StateAccessor) {
+		stateLedger.SetNonce(address, nonce)
+	})
+}
+
+func (api *DevAPI) ImpersonateAccount(address *types.Address) error {
+	if api.axiomLedger.ImpersonatedAccounts == nil {
+		return ErrImpersonationDisabled
+	}
+	api.axiomLedger.ImpersonatedAccounts.Add(address.String())
+	return nil
+}
+
+func (api *DevAPI) StopImpersonatingAccount(address *types.Address) error {
+	if api.axiomLedger.ImpersonatedAccounts == nil {
+		return ErrImpersonationDisabled
+	}
+	api.axiomLedger.ImpersonatedAccounts.Remove(address.String())
+	return nil
+}
+
+func (api *DevAPI) IsImpersonated(address *types.Address) bool {
+	return api.axiomLedger.ImpersonatedAccounts.Has(address.String())
+}
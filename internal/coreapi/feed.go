@@ -14,6 +14,7 @@ import (
 
 	"github.com/axiomesh/axiom-kit/types"
 	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	"github.com/axiomesh/axiom-ledger/internal/txpool"
 	"github.com/axiomesh/axiom-ledger/pkg/events"
 	"github.com/axiomesh/axiom-ledger/pkg/repo"
 )
@@ -24,6 +25,8 @@ var _ api.FeedAPI = (*FeedAPI)(nil)
 
 var emptyTxFeed event.Feed
 
+var emptyDroppedTxFeed event.Feed
+
 // todo: subscribe from txpool?
 func (api *FeedAPI) SubscribeNewTxEvent(ch chan<- []*types.Transaction) event.Subscription {
 	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
@@ -32,6 +35,19 @@ func (api *FeedAPI) SubscribeNewTxEvent(ch chan<- []*types.Transaction) event.Su
 	return api.axiomLedger.Consensus.SubscribeTxEvent(ch)
 }
 
+func (api *FeedAPI) SubscribeDroppedTxEvent(ch chan<- []*txpool.DroppedTx) event.Subscription {
+	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
+		return emptyDroppedTxFeed.Subscribe(ch)
+	}
+	pool, ok := api.axiomLedger.TxPool.(interface {
+		SubscribeDroppedTxEvent(chan<- []*txpool.DroppedTx) event.Subscription
+	})
+	if !ok {
+		return emptyDroppedTxFeed.Subscribe(ch)
+	}
+	return pool.SubscribeDroppedTxEvent(ch)
+}
+
 func (api *FeedAPI) SubscribeNewBlockEvent(ch chan<- events.ExecutedEvent) event.Subscription {
 	return api.axiomLedger.BlockExecutor.SubscribeBlockEventForRemote(ch)
 }
@@ -2,7 +2,9 @@ package coreapi
 
 import (
 	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/consensus/solo_dev"
 	"github.com/axiomesh/axiom-ledger/internal/coreapi/api"
+	"github.com/axiomesh/axiom-ledger/internal/txpool"
 )
 
 type TxPoolAPI CoreAPI
@@ -13,6 +15,9 @@ func (api *TxPoolAPI) GetTotalPendingTxCount() uint64 {
 	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
 		return 0
 	}
+	if node, ok := api.axiomLedger.Consensus.(*solo_dev.NodeDev); ok {
+		return node.GetTotalPendingTxCount()
+	}
 	return api.axiomLedger.TxPool.GetTotalPendingTxCount()
 }
 
@@ -20,6 +25,9 @@ func (api *TxPoolAPI) GetPendingTxCountByAccount(account string) uint64 {
 	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
 		return 0
 	}
+	if node, ok := api.axiomLedger.Consensus.(*solo_dev.NodeDev); ok {
+		return node.GetPendingTxCountByAccount(account)
+	}
 	return api.axiomLedger.TxPool.GetPendingTxCountByAccount(account)
 }
 
@@ -27,6 +35,10 @@ func (api *TxPoolAPI) GetTransaction(hash *types.Hash) *types.Transaction {
 	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
 		return nil
 	}
+	// the dev node keeps the pending txs by itself when the auto mining is disabled
+	if node, ok := api.axiomLedger.Consensus.(*solo_dev.NodeDev); ok {
+		return node.GetPendingTxByHash(hash)
+	}
 	return api.axiomLedger.TxPool.GetPendingTxByHash(hash.String())
 }
 
@@ -45,6 +57,30 @@ func (api *TxPoolAPI) GetMeta(full bool) any {
 	return api.axiomLedger.TxPool.GetMeta(full)
 }
 
+func (api *TxPoolAPI) GetQuota() *txpool.Quota {
+	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
+		return nil
+	}
+	pool, ok := api.axiomLedger.TxPool.(interface{ GetQuota() *txpool.Quota })
+	if !ok {
+		return nil
+	}
+	return pool.GetQuota()
+}
+
+func (api *TxPoolAPI) GetTxStatus(hash string) *txpool.TxStatusInfo {
+	if api.axiomLedger.Repo.StartArgs.ReadonlyMode {
+		return &txpool.TxStatusInfo{Status: txpool.TxStatusUnknown}
+	}
+	pool, ok := api.axiomLedger.TxPool.(interface {
+		GetTxStatus(hash string) *txpool.TxStatusInfo
+	})
+	if !ok {
+		return &txpool.TxStatusInfo{Status: txpool.TxStatusUnknown}
+	}
+	return pool.GetTxStatus(hash)
+}
+
 func (api *TxPoolAPI) IsStarted() bool {
 	return api.axiomLedger.TxPool.IsStarted()
 }
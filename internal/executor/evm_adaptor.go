@@ -11,6 +11,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 
 	"github.com/axiomesh/axiom-kit/types"
+
+	"github.com/axiomesh/axiom-ledger/internal/components"
 )
 
 func CallArgsToMessage(args *types.CallArgs, globalGasCap uint64, baseFee *big.Int) (*core.Message, error) {
@@ -148,7 +150,7 @@ func NewEVMBlockContextAdaptor(number uint64, timestamp uint64, coinbase string,
 		BlockNumber: new(big.Int).SetUint64(number),
 		Time:        timestamp,
 		Difficulty:  big.NewInt(0x2000),
-		BaseFee:     big.NewInt(0),
+		BaseFee:     components.BaseFee(),
 		GasLimit:    0x2fefd8,
 		Random:      &common.Hash{},
 	}
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"math/big"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/event"
@@ -14,6 +15,7 @@ import (
 	"github.com/axiomesh/axiom-ledger/internal/consensus/common"
 	"github.com/axiomesh/axiom-ledger/internal/executor/system"
 	syscommon "github.com/axiomesh/axiom-ledger/internal/executor/system/common"
+	"github.com/axiomesh/axiom-ledger/internal/indexer"
 	"github.com/axiomesh/axiom-ledger/internal/ledger"
 	"github.com/axiomesh/axiom-ledger/pkg/events"
 	"github.com/axiomesh/axiom-ledger/pkg/loggers"
@@ -53,8 +55,21 @@ type BlockExecutor struct {
 
 	nvm             syscommon.VirtualMachine
 	afterBlockHooks []AfterBlockHook
+
+	internalTxIndexer *indexer.InternalTxIndexer
+	// internal txs of the executing block, grouped by tx
+	internalTxs [][]*indexer.InternalTx
+
+	bundleTracker BundleTracker
+
+	// state writes of the dev mode, applied before the txs of the next block
+	stateOverridesLock sync.Mutex
+	stateOverrides     []StateOverride
 }
 
+// StateOverride writes the state directly, only used in the dev mode
+type StateOverride func(stateLedger ledger.StateAccessor)
+
 // New creates executor instance
 func New(rep *repo.Repo, ledger *ledger.Ledger, chainState *chainstate.ChainState) (*BlockExecutor, error) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -92,6 +107,34 @@ func New(rep *repo.Repo, ledger *ledger.Ledger, chainState *chainstate.ChainStat
 	return blockExecutor, nil
 }
 
+// SetInternalTxIndexer enables recording the call tree of every executed transaction
+func (exec *BlockExecutor) SetInternalTxIndexer(internalTxIndexer *indexer.InternalTxIndexer) {
+	exec.internalTxIndexer = internalTxIndexer
+}
+
+// SetBundleTracker enables persisting the bundle info of the bundle txs batched by this node
+func (exec *BlockExecutor) SetBundleTracker(bundleTracker BundleTracker) {
+	exec.bundleTracker = bundleTracker
+}
+
+// AddStateOverride queues the state writes, which are applied and committed with the next block
+func (exec *BlockExecutor) AddStateOverride(override StateOverride) {
+	exec.stateOverridesLock.Lock()
+	defer exec.stateOverridesLock.Unlock()
+	exec.stateOverrides = append(exec.stateOverrides, override)
+}
+
+func (exec *BlockExecutor) applyStateOverrides() {
+	exec.stateOverridesLock.Lock()
+	overrides := exec.stateOverrides
+	exec.stateOverrides = nil
+	exec.stateOverridesLock.Unlock()
+
+	for _, override := range overrides {
+		override(exec.ledger.StateLedger)
+	}
+}
+
 // Start starts executor
 func (exec *BlockExecutor) Start() error {
 	go exec.listenExecuteEvent()
@@ -122,6 +122,13 @@ func TestGetLogsForReceipt(t *testing.T) {
 	executor.updateLogsBlockHash(receipts, &types.Hash{})
 }
 
+type mockBundleTracker map[string]string
+
+func (m mockBundleTracker) GetBundleID(txHash string) (string, bool) {
+	bundleID, ok := m[txHash]
+	return bundleID, ok
+}
+
 func TestGetChainConfig(t *testing.T) {
 	executor := executorStart(t)
 	config := executor.GetChainConfig()
@@ -267,6 +274,72 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 	assert.Nil(t, exec.Stop())
 }
 
+func TestBlockExecutor_Rollback(t *testing.T) {
+	r := repo.MockRepo(t)
+
+	mockLedger, err := ledger.NewMemory(r)
+	require.Nil(t, err)
+
+	genesisBlock := &types.Block{
+		Header: &types.BlockHeader{
+			Number:   0,
+			GasPrice: minGasPrice * 5,
+		},
+	}
+	mockLedger.StateLedger.PrepareBlock(genesisBlock.Header.StateRoot, 0)
+	err = system.New().GenesisInit(r.GenesisConfig, mockLedger.StateLedger)
+	assert.Nil(t, err)
+	mockLedger.StateLedger.Finalise()
+	stateRoot, err := mockLedger.StateLedger.Commit()
+	assert.Nil(t, err)
+	genesisBlock.Header.StateRoot = stateRoot
+	mockLedger.PersistBlockData(&ledger.BlockData{Block: genesisBlock})
+
+	chainState := chainstate.NewMockChainState(r.GenesisConfig, nil)
+	chainState.ChainMeta = &types.ChainMeta{
+		Height:    0,
+		BlockHash: genesisBlock.Hash(),
+	}
+	exec, err := New(r, mockLedger, chainState)
+	assert.Nil(t, err)
+	assert.Nil(t, exec.Start())
+
+	ch := make(chan events.ExecutedEvent)
+	blockSub := exec.SubscribeBlockEvent(ch)
+	defer blockSub.Unsubscribe()
+
+	exec.AsyncExecuteBlock(mockCommitEvent(uint64(1), nil))
+	exec.AsyncExecuteBlock(mockCommitEvent(uint64(2), nil))
+	blockRes1 := <-ch
+	blockRes2 := <-ch
+	assert.EqualValues(t, 2, chainState.ChainMeta.Height)
+	assert.Equal(t, blockRes2.Block.Hash(), chainState.ChainMeta.BlockHash)
+
+	// the chain state follows the rolled back ledger before the next block is executed
+	err = exec.Rollback(1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, exec.currentHeight)
+	assert.Equal(t, blockRes1.Block.Hash(), exec.currentBlockHash)
+	assert.EqualValues(t, 1, mockLedger.ChainLedger.GetChainMeta().Height)
+	assert.EqualValues(t, 1, chainState.ChainMeta.Height)
+	assert.Equal(t, blockRes1.Block.Hash(), chainState.ChainMeta.BlockHash)
+
+	// the new block follows the rolled back block
+	exec.AsyncExecuteBlock(mockCommitEvent(uint64(2), nil))
+	newBlockRes2 := <-ch
+	assert.EqualValues(t, 2, newBlockRes2.Block.Height())
+	assert.Equal(t, blockRes1.Block.Hash(), newBlockRes2.Block.Header.ParentHash)
+	assert.EqualValues(t, 2, chainState.ChainMeta.Height)
+
+	err = exec.rollbackBlocks(genesisBlock)
+	assert.NotNil(t, err)
+	err = exec.rollbackBlocks(newBlockRes2.Block)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, chainState.ChainMeta.Height)
+
+	assert.Nil(t, exec.Stop())
+}
+
 // NodeExtraArgs is Node proposal extra arguments
 
 func mockCommitEvent(blockNumber uint64, txs []*types.Transaction) *consensuscommon.CommitEvent {
@@ -376,6 +449,196 @@ func TestBlockExecutor_ExecuteBlock_Transfer(t *testing.T) {
 	require.EqualValues(t, 3, ldg.StateLedger.GetBalance(to).Uint64())
 }
 
+func TestBlockExecutor_ExecuteBlock_Bundle(t *testing.T) {
+	r := repo.MockRepo(t)
+
+	ldg, err := ledger.NewMemory(r)
+	require.Nil(t, err)
+
+	nvm := system.New()
+	err = nvm.GenesisInit(r.GenesisConfig, ldg.StateLedger)
+	assert.Nil(t, err)
+
+	signer, err := types.GenerateSigner()
+	require.Nil(t, err)
+	to := types.NewAddressByStr("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	dummyRootHash := ethcommon.Hash{}
+	ldg.StateLedger.PrepareBlock(types.NewHash(dummyRootHash[:]), 1)
+	ldg.StateLedger.SetBalance(signer.Addr, new(big.Int).Mul(big.NewInt(5000000000000), big.NewInt(21000*10000)))
+	ldg.StateLedger.Finalise()
+	rootHash, err := ldg.StateLedger.Commit()
+	require.Nil(t, err)
+	block1 := mockBlock(0, nil)
+	block1.Header.StateRoot = rootHash
+	err = ldg.ChainLedger.PersistExecutionResult(block1, nil)
+	require.Nil(t, err)
+	ldg.ChainLedger.UpdateChainMeta(&types.ChainMeta{
+		Height:    0,
+		BlockHash: types.NewHash([]byte(from)),
+	})
+
+	chainState := chainstate.NewMockChainState(r.GenesisConfig, nil)
+	executor, err := New(r, ldg, chainState)
+	require.Nil(t, err)
+
+	// the second bundle tx reverts for the insufficient balance
+	tx1 := mockTransferTx(t, signer, to, 0, 1)
+	tx2, err := types.GenerateTransactionWithSigner(1, to, new(big.Int).Mul(big.NewInt(5000000000000), big.NewInt(21000*10000)), nil, signer)
+	require.Nil(t, err)
+	tx3 := mockTransferTx(t, signer, to, 2, 1)
+	executor.SetBundleTracker(mockBundleTracker{
+		tx1.GetHash().String(): "bundle",
+		tx2.GetHash().String(): "bundle",
+	})
+	err = executor.Start()
+	require.Nil(t, err)
+
+	ch := make(chan events.ExecutedEvent)
+	sub := executor.SubscribeBlockEvent(ch)
+	defer sub.Unsubscribe()
+
+	executor.AsyncExecuteBlock(mockCommitEvent(1, []*types.Transaction{tx1, tx2, tx3}))
+	block := <-ch
+	require.EqualValues(t, 1, block.Block.Height())
+
+	receipt, err := ldg.ChainLedger.GetReceipt(tx2.GetHash())
+	require.Nil(t, err)
+	assert.Equal(t, types.ReceiptFAILED, receipt.Status)
+
+	bundleTx, err := ldg.ChainLedger.GetBundleTx(tx1.GetHash())
+	require.Nil(t, err)
+	assert.Equal(t, &ledger.BundleTx{BundleID: "bundle"}, bundleTx)
+	bundleTx, err = ldg.ChainLedger.GetBundleTx(tx2.GetHash())
+	require.Nil(t, err)
+	assert.Equal(t, &ledger.BundleTx{BundleID: "bundle", Reverted: true}, bundleTx)
+	_, err = ldg.ChainLedger.GetBundleTx(tx3.GetHash())
+	assert.ErrorIs(t, err, ledger.ErrNotFound)
+}
+
+func TestBlockExecutor_ExecuteBlock_StateOverride(t *testing.T) {
+	r := repo.MockRepo(t)
+
+	ldg, err := ledger.NewMemory(r)
+	require.Nil(t, err)
+
+	nvm := system.New()
+	err = nvm.GenesisInit(r.GenesisConfig, ldg.StateLedger)
+	assert.Nil(t, err)
+
+	dummyRootHash := ethcommon.Hash{}
+	ldg.StateLedger.PrepareBlock(types.NewHash(dummyRootHash[:]), 1)
+	ldg.StateLedger.Finalise()
+	rootHash, err := ldg.StateLedger.Commit()
+	require.Nil(t, err)
+	block1 := mockBlock(0, nil)
+	block1.Header.StateRoot = rootHash
+	err = ldg.ChainLedger.PersistExecutionResult(block1, nil)
+	require.Nil(t, err)
+	ldg.ChainLedger.UpdateChainMeta(&types.ChainMeta{
+		Height:    0,
+		BlockHash: types.NewHash([]byte(from)),
+	})
+
+	chainState := chainstate.NewMockChainState(r.GenesisConfig, nil)
+	executor, err := New(r, ldg, chainState)
+	require.Nil(t, err)
+	err = executor.Start()
+	require.Nil(t, err)
+
+	ch := make(chan events.ExecutedEvent)
+	sub := executor.SubscribeBlockEvent(ch)
+	defer sub.Unsubscribe()
+
+	impersonated := ethcommon.HexToAddress("0x00000000000000000000000000000000000000aa")
+	contract := types.NewAddressByStr("0x00000000000000000000000000000000000000bb")
+	toAddr := ethcommon.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	to := types.NewAddress(toAddr.Bytes())
+	executor.AddStateOverride(func(stateLedger ledger.StateAccessor) {
+		stateLedger.SetBalance(types.NewAddress(impersonated.Bytes()), new(big.Int).Mul(big.NewInt(minGasPrice), big.NewInt(21000*10)))
+		stateLedger.SetNonce(types.NewAddress(impersonated.Bytes()), 5)
+		stateLedger.SetCode(contract, []byte{0x60, 0x00})
+		stateLedger.SetState(contract, []byte("key"), []byte("value"))
+	})
+
+	tx := consensuscommon.NewImpersonatedTx(impersonated, &types.DynamicFeeTx{
+		ChainID:   new(big.Int).SetUint64(r.GenesisConfig.ChainID),
+		Nonce:     5,
+		GasTipCap: big.NewInt(minGasPrice),
+		GasFeeCap: big.NewInt(minGasPrice),
+		Gas:       21000,
+		To:        &toAddr,
+		Value:     big.NewInt(1),
+	})
+	require.True(t, consensuscommon.IsImpersonatedTx(tx))
+	executor.AsyncExecuteBlock(mockCommitEvent(1, []*types.Transaction{tx}))
+
+	block := <-ch
+	require.EqualValues(t, 1, block.Block.Height())
+	require.EqualValues(t, 1, ldg.StateLedger.GetBalance(to).Uint64())
+	require.EqualValues(t, 6, ldg.StateLedger.GetNonce(types.NewAddress(impersonated.Bytes())))
+	require.Equal(t, []byte{0x60, 0x00}, ldg.StateLedger.GetCode(contract))
+	exist, value := ldg.StateLedger.GetState(contract, []byte("key"))
+	require.True(t, exist)
+	require.Equal(t, []byte("value"), value)
+}
+
+func TestBlockExecutor_ReplayBlock(t *testing.T) {
+	r := repo.MockRepo(t)
+
+	ldg, err := ledger.NewMemory(r)
+	require.Nil(t, err)
+
+	nvm := system.New()
+	err = nvm.GenesisInit(r.GenesisConfig, ldg.StateLedger)
+	assert.Nil(t, err)
+
+	signer, err := types.GenerateSigner()
+	require.Nil(t, err)
+	to := types.NewAddressByStr("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	dummyRootHash := ethcommon.Hash{}
+	ldg.StateLedger.PrepareBlock(types.NewHash(dummyRootHash[:]), 1)
+	ldg.StateLedger.SetBalance(signer.Addr, new(big.Int).Mul(big.NewInt(5000000000000), big.NewInt(21000*10000)))
+	ldg.StateLedger.Finalise()
+	rootHash, err := ldg.StateLedger.Commit()
+	require.Nil(t, err)
+	block0 := mockBlock(0, nil)
+	block0.Header.StateRoot = rootHash
+	err = ldg.ChainLedger.PersistExecutionResult(block0, nil)
+	require.Nil(t, err)
+	ldg.ChainLedger.UpdateChainMeta(&types.ChainMeta{
+		Height:    0,
+		BlockHash: types.NewHash([]byte(from)),
+	})
+
+	executor, err := New(r, ldg, chainstate.NewMockChainState(r.GenesisConfig, nil))
+	require.Nil(t, err)
+	tx1 := mockTransferTx(t, signer, to, 0, 1)
+	tx2 := mockTransferTx(t, signer, to, 1, 1)
+	commitEvent := mockCommitEvent(1, []*types.Transaction{tx1, tx2})
+	executor.ExecuteBlock(commitEvent)
+
+	block, err := ldg.ChainLedger.GetBlock(1)
+	require.Nil(t, err)
+	receipts, err := ldg.ChainLedger.GetBlockReceipts(1)
+	require.Nil(t, err)
+
+	// replay the block on the state of its parent
+	err = ldg.StateLedger.RollbackState(0, rootHash)
+	require.Nil(t, err)
+	replayed, replayedReceipts, err := executor.ReplayBlock(block)
+	require.Nil(t, err)
+	assert.Equal(t, block.Header.StateRoot.String(), replayed.Header.StateRoot.String())
+	assert.Equal(t, block.Header.ReceiptRoot.String(), replayed.Header.ReceiptRoot.String())
+	assert.Equal(t, block.Header.GasUsed, replayed.Header.GasUsed)
+	assert.Equal(t, block.Hash().String(), replayed.Hash().String())
+	require.Len(t, replayedReceipts, len(receipts))
+	for i, receipt := range receipts {
+		assert.Equal(t, receipt.Hash().String(), replayedReceipts[i].Hash().String())
+	}
+}
+
 func mockTransferTx(t *testing.T, s *types.Signer, to *types.Address, nonce, amount int) *types.Transaction {
 	tx, err := types.GenerateTransactionWithSigner(uint64(nonce), to, big.NewInt(int64(amount)), nil, s)
 	assert.Nil(t, err)
@@ -21,6 +21,7 @@ import (
 	"github.com/axiomesh/axiom-kit/types"
 	consensuscommon "github.com/axiomesh/axiom-ledger/internal/consensus/common"
 	syscommon "github.com/axiomesh/axiom-ledger/internal/executor/system/common"
+	"github.com/axiomesh/axiom-ledger/internal/indexer"
 	"github.com/axiomesh/axiom-ledger/internal/ledger"
 	"github.com/axiomesh/axiom-ledger/pkg/events"
 )
@@ -34,6 +35,9 @@ type BlockWrapper struct {
 
 func (exec *BlockExecutor) applyTransactions(txs []*types.Transaction, height uint64) []*types.Receipt {
 	receipts := make([]*types.Receipt, 0, len(txs))
+	if exec.internalTxIndexer != nil {
+		exec.internalTxs = make([][]*indexer.InternalTx, 0, len(txs))
+	}
 
 	for i, tx := range txs {
 		receipts = append(receipts, exec.applyTransaction(i, tx, height))
@@ -48,21 +52,32 @@ func (exec *BlockExecutor) rollbackBlocks(newBlock *types.Block) error {
 	if newBlock.Height() == 0 {
 		return errors.New("cannot rollback genesis block")
 	}
+	return exec.Rollback(newBlock.Height() - 1)
+}
 
+// Rollback rolls back the ledger and the executor to the given height,
+// it must not be called concurrently with the block execution.
+func (exec *BlockExecutor) Rollback(height uint64) error {
 	// rollback from stateLedger、chainLedger and blockFile
-	err := exec.ledger.Rollback(newBlock.Height() - 1)
+	err := exec.ledger.Rollback(height)
 	if err != nil {
-		return errors.Wrapf(err, "rollback block error, begin height: %d, end height: %d", newBlock.Height()-1, exec.currentHeight)
+		return errors.Wrapf(err, "rollback block error, begin height: %d, end height: %d", height, exec.currentHeight)
+	}
+	if exec.internalTxIndexer != nil {
+		if err := exec.internalTxIndexer.Rollback(height); err != nil {
+			return errors.Wrapf(err, "rollback internal txs error, height: %d", height)
+		}
 	}
 
 	// query last checked block for generating right parent blockHash
-	lastCheckedBlockHeader, err := exec.ledger.ChainLedger.GetBlockHeader(newBlock.Height() - 1)
+	lastCheckedBlockHeader, err := exec.ledger.ChainLedger.GetBlockHeader(height)
 	if err != nil {
-		return errors.Wrapf(err, "get last checked block from ledger error at height: %d", newBlock.Height()-1)
+		return errors.Wrapf(err, "get last checked block from ledger error at height: %d", height)
 	}
 	// rollback currentHeight and currentBlockHash
-	exec.currentHeight = newBlock.Height() - 1
+	exec.currentHeight = height
 	exec.currentBlockHash = lastCheckedBlockHeader.Hash()
+	exec.chainState.UpdateChainMeta(exec.ledger.ChainLedger.GetChainMeta())
 
 	exec.logger.WithFields(logrus.Fields{
 		"height": lastCheckedBlockHeader.Number,
@@ -92,6 +107,71 @@ func (exec *BlockExecutor) processExecuteEvent(commitEvent *consensuscommon.Comm
 		txHashList = append(txHashList, tx.GetHash())
 	}
 
+	receipts := exec.applyBlock(block, exec.currentBlockHash)
+	executeBlockDuration.Observe(float64(time.Since(current)) / float64(time.Second))
+
+	data := &ledger.BlockData{
+		Block:      block,
+		Receipts:   receipts,
+		TxHashList: txHashList,
+		BundleTxs:  exec.collectBundleTxs(block.Height(), receipts),
+	}
+
+	exec.logger.WithFields(logrus.Fields{
+		"height": commitEvent.Block.Header.Number,
+		"count":  len(commitEvent.Block.Transactions),
+		"elapse": time.Since(current),
+	}).Info("[Execute-Block] Executed block")
+
+	now := time.Now()
+	exec.ledger.PersistBlockData(data)
+	if exec.internalTxIndexer != nil {
+		if err := exec.internalTxIndexer.WriteBlock(block.Height(), exec.internalTxs); err != nil {
+			exec.logger.WithFields(logrus.Fields{
+				"height": block.Height(),
+				"err":    err.Error(),
+			}).Error("Index internal txs failed")
+			exec.internalTxIndexer.MarkFailed(block.Height(), err)
+		}
+		exec.internalTxs = nil
+	}
+
+	// metrics for cal tx tps
+	txCounter.Add(float64(len(data.Block.Transactions)))
+	if block.Header.ProposerNodeID == exec.chainState.SelfNodeInfo.ID {
+		proposedBlockCounter.Inc()
+	}
+
+	exec.logger.WithFields(logrus.Fields{
+		"height": data.Block.Header.Number,
+		"hash":   data.Block.Hash().String(),
+		"count":  len(data.Block.Transactions),
+		"elapse": time.Since(now),
+	}).Info("[Execute-Block] Persisted block")
+
+	exec.currentHeight = block.Header.Number
+	exec.currentBlockHash = block.Hash()
+	exec.chainState.UpdateChainMeta(exec.ledger.ChainLedger.GetChainMeta())
+	exec.chainState.TryUpdateSelfNodeInfo()
+
+	txPointerList := make([]*events.TxPointer, len(data.Block.Transactions))
+	lo.ForEach(data.Block.Transactions, func(item *types.Transaction, index int) {
+		txPointerList[index] = &events.TxPointer{
+			Hash:    item.GetHash(),
+			Account: item.RbftGetFrom(),
+			Nonce:   item.RbftGetNonce(),
+		}
+	})
+
+	exec.postBlockEvent(data.Block, txPointerList, commitEvent.StateUpdatedCheckpoint)
+	exec.postLogsEvent(data.Receipts)
+	exec.clear()
+}
+
+// applyBlock executes the block on the state of its parent block and commits the state,
+// the computed fields of the block header are filled, but the block is not persisted.
+func (exec *BlockExecutor) applyBlock(block *types.Block, parentHash *types.Hash) []*types.Receipt {
+	current := time.Now()
 	exec.cumulativeGasUsed = 0
 	exec.evm = newEvm(block.Height(), uint64(block.Header.Timestamp), exec.evmChainCfg, exec.ledger.StateLedger, exec.ledger.ChainLedger, syscommon.StakingManagerContractAddr)
 	// get last block's stateRoot to init the latest world state trie
@@ -101,14 +181,15 @@ func (exec *BlockExecutor) processExecuteEvent(commitEvent *consensuscommon.Comm
 			"height": block.Height() - 1,
 			"err":    err.Error(),
 		}).Panic("Get last block from ledger error")
-		return
+		return nil
 	}
 	exec.ledger.StateLedger.PrepareBlock(parentBlockHeader.StateRoot, block.Height())
+	exec.applyStateOverrides()
 	receipts := exec.applyTransactions(block.Transactions, block.Height())
 
 	totalGasFee := new(big.Int)
 	for i, receipt := range receipts {
-		receipt.EffectiveGasPrice = block.Transactions[i].Inner.EffectiveGasPrice(big.NewInt(0))
+		receipt.EffectiveGasPrice = block.Transactions[i].Inner.EffectiveGasPrice(components.BaseFee())
 		txGasFee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
 		totalGasFee = totalGasFee.Add(totalGasFee, txGasFee)
 	}
@@ -122,7 +203,7 @@ func (exec *BlockExecutor) processExecuteEvent(commitEvent *consensuscommon.Comm
 				"err":    err.Error(),
 				"hook":   hook.Name,
 			}).Panic("Execute afterBlock hook failed")
-			return
+			return nil
 		}
 	}
 
@@ -149,7 +230,7 @@ func (exec *BlockExecutor) processExecuteEvent(commitEvent *consensuscommon.Comm
 
 	block.Header.TxRoot = txRoot
 	block.Header.ReceiptRoot = receiptRoot
-	block.Header.ParentHash = exec.currentBlockHash
+	block.Header.ParentHash = parentHash
 
 	stateRoot, err := exec.ledger.StateLedger.Commit()
 	if err != nil {
@@ -176,56 +257,11 @@ func (exec *BlockExecutor) processExecuteEvent(commitEvent *consensuscommon.Comm
 	}).Info("[Execute-Block] Block meta")
 
 	calcBlockSize.Observe(float64(block.Size()))
-	executeBlockDuration.Observe(float64(time.Since(current)) / float64(time.Second))
 
 	exec.updateLogsBlockHash(receipts, block.Hash())
 	block.Header.Bloom = ledger.CreateBloom(receipts)
 
-	data := &ledger.BlockData{
-		Block:      block,
-		Receipts:   receipts,
-		TxHashList: txHashList,
-	}
-
-	exec.logger.WithFields(logrus.Fields{
-		"height": commitEvent.Block.Header.Number,
-		"count":  len(commitEvent.Block.Transactions),
-		"elapse": time.Since(current),
-	}).Info("[Execute-Block] Executed block")
-
-	now := time.Now()
-	exec.ledger.PersistBlockData(data)
-
-	// metrics for cal tx tps
-	txCounter.Add(float64(len(data.Block.Transactions)))
-	if block.Header.ProposerNodeID == exec.chainState.SelfNodeInfo.ID {
-		proposedBlockCounter.Inc()
-	}
-
-	exec.logger.WithFields(logrus.Fields{
-		"height": data.Block.Header.Number,
-		"hash":   data.Block.Hash().String(),
-		"count":  len(data.Block.Transactions),
-		"elapse": time.Since(now),
-	}).Info("[Execute-Block] Persisted block")
-
-	exec.currentHeight = block.Header.Number
-	exec.currentBlockHash = block.Hash()
-	exec.chainState.UpdateChainMeta(exec.ledger.ChainLedger.GetChainMeta())
-	exec.chainState.TryUpdateSelfNodeInfo()
-
-	txPointerList := make([]*events.TxPointer, len(data.Block.Transactions))
-	lo.ForEach(data.Block.Transactions, func(item *types.Transaction, index int) {
-		txPointerList[index] = &events.TxPointer{
-			Hash:    item.GetHash(),
-			Account: item.RbftGetFrom(),
-			Nonce:   item.RbftGetNonce(),
-		}
-	})
-
-	exec.postBlockEvent(data.Block, txPointerList, commitEvent.StateUpdatedCheckpoint)
-	exec.postLogsEvent(data.Receipts)
-	exec.clear()
+	return receipts
 }
 
 func (exec *BlockExecutor) postBlockEvent(block *types.Block, txPointerList []*events.TxPointer, ckp *consensuscommon.Checkpoint) {
@@ -251,12 +287,27 @@ func (exec *BlockExecutor) postLogsEvent(receipts []*types.Receipt) {
 
 func (exec *BlockExecutor) applyTransaction(i int, tx *types.Transaction, height uint64) *types.Receipt {
 	defer func() {
+		snapshot := exec.ledger.StateLedger.Snapshot()
 		exec.ledger.StateLedger.SetNonce(tx.GetFrom(), tx.GetNonce()+1)
+		if err := exec.ledger.StateLedger.ForkError(); err != nil {
+			// the sender failed to fetch from the forked chain must not be replaced by an empty account
+			exec.logger.Errorf("set nonce of tx %s failed: %s", tx.GetHash(), err.Error())
+			exec.ledger.StateLedger.RevertToSnapshot(snapshot)
+		}
 		exec.ledger.StateLedger.Finalise()
 	}()
 
 	exec.ledger.StateLedger.SetTxContext(tx.GetHash(), i)
 
+	if exec.internalTxIndexer != nil {
+		tracer := indexer.NewInternalTxTracer(height, uint64(i), tx.GetHash().ETHHash())
+		exec.evm.Config.Tracer = tracer
+		defer func() {
+			exec.evm.Config.Tracer = nil
+			exec.internalTxs = append(exec.internalTxs, tracer.InternalTxs())
+		}()
+	}
+
 	receipt := &types.Receipt{
 		TxHash: tx.GetHash(),
 	}
@@ -277,6 +328,10 @@ func (exec *BlockExecutor) applyTransaction(i int, tx *types.Transaction, height
 	exec.evm.Reset(txContext, evmStateDB)
 	exec.logger.Debugf("evm apply message, msg gas limit: %d, gas price: %s", msg.GasLimit, msg.GasPrice.Text(10))
 	result, err = core.ApplyMessage(exec.evm, msg, gp)
+	if forkErr := statedb.ForkError(); forkErr != nil {
+		// the tx is executed on the missing state, discard the result
+		err = forkErr
+	}
 	if err != nil {
 		exec.logger.Errorf("apply tx failed: %s", err.Error())
 		statedb.RevertToSnapshot(snapshot)
@@ -319,6 +374,32 @@ func (exec *BlockExecutor) applyTransaction(i int, tx *types.Transaction, height
 	return receipt
 }
 
+// collectBundleTxs collects the bundle info of the bundle txs in the block and reports the reverted ones,
+// the bundle txs are placed consecutively in the block, but they are executed one by one.
+func (exec *BlockExecutor) collectBundleTxs(height uint64, receipts []*types.Receipt) map[string]*ledger.BundleTx {
+	if exec.bundleTracker == nil {
+		return nil
+	}
+	bundleTxs := make(map[string]*ledger.BundleTx)
+	for _, receipt := range receipts {
+		bundleID, ok := exec.bundleTracker.GetBundleID(receipt.TxHash.String())
+		if !ok {
+			continue
+		}
+		reverted := receipt.Status == types.ReceiptFAILED
+		bundleTxs[receipt.TxHash.String()] = &ledger.BundleTx{BundleID: bundleID, Reverted: reverted}
+		if reverted {
+			exec.logger.WithFields(logrus.Fields{
+				"height": height,
+				"tx":     receipt.TxHash.String(),
+				"bundle": bundleID,
+				"ret":    string(receipt.Ret),
+			}).Warning("Bundle tx reverted")
+		}
+	}
+	return bundleTxs
+}
+
 func (exec *BlockExecutor) clear() {
 	exec.ledger.StateLedger.Clear()
 }
@@ -0,0 +1,31 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+// ReplayBlock re-executes a persisted block on the state of its parent block and returns the execution results,
+// which can be compared with the persisted block and receipts. The executor should be created with a replay ledger,
+// otherwise the persisted state would be modified.
+func (exec *BlockExecutor) ReplayBlock(block *types.Block) (replayed *types.Block, receipts []*types.Receipt, err error) {
+	replayed = &types.Block{
+		Header: &types.BlockHeader{
+			Epoch:          block.Header.Epoch,
+			Number:         block.Header.Number,
+			Timestamp:      block.Header.Timestamp,
+			ProposerNodeID: block.Header.ProposerNodeID,
+		},
+		Transactions: block.Transactions,
+	}
+
+	// the executor panics on the unexpected errors of ledger and hooks
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("replay block %d failed: %v", block.Height(), r)
+		}
+	}()
+	receipts = exec.applyBlock(replayed, block.Header.ParentHash)
+	return replayed, receipts, nil
+}
@@ -31,3 +31,11 @@ type Executor interface {
 
 	GetChainConfig() *params.ChainConfig
 }
+
+// BundleTracker tracks the bundle txs batched by this node, the bundle info is not written into the block
+// or receipt, so that it does not affect the consensus data, it is persisted with the block by the node which
+// batched the bundle instead.
+type BundleTracker interface {
+	// GetBundleID returns the bundle id of the tx, false if the tx is not batched in a bundle
+	GetBundleID(txHash string) (string, bool)
+}
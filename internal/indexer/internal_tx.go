@@ -0,0 +1,354 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/axiom-kit/storage/kv"
+	"github.com/axiomesh/axiom-ledger/pkg/loggers"
+)
+
+var (
+	internalTxMetaKey        = []byte("itx-meta")    // internalTxMetaKey -> latest indexed block number (uint64 big endian)
+	internalTxBlockPrefix    = []byte("itx-block-")  // internalTxBlockPrefix + block number (uint64 big endian) -> internal txs of block
+	internalTxHashPrefix     = []byte("itx-tx-")     // internalTxHashPrefix + tx hash -> internal txs of tx
+	internalTxAddressPrefix  = []byte("itx-addr-")   // internalTxAddressPrefix + address + block number (uint64 big endian) + tx index (uint32 big endian) + frame index (uint32 big endian) -> internal tx
+	internalTxFailedPrefix   = []byte("itx-failed-") // internalTxFailedPrefix + block number (uint64 big endian) -> the reason why the block failed to be indexed
+	errInternalTxNotIndexed  = errors.New("internal txs not indexed")
+	emptyInternalTxTraceAddr = []int{}
+)
+
+// InternalTx is a single call frame of the call tree of a transaction.
+type InternalTx struct {
+	BlockNumber  hexutil.Uint64 `json:"blockNumber"`
+	TxHash       common.Hash    `json:"transactionHash"`
+	TxIndex      hexutil.Uint64 `json:"transactionIndex"`
+	TraceAddress []int          `json:"traceAddress"`
+	Type         string         `json:"type"`
+	From         common.Address `json:"from"`
+	To           common.Address `json:"to"`
+	Value        *hexutil.Big   `json:"value"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Input        hexutil.Bytes  `json:"input"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// InternalTxTracer collects the flattened call tree of a single transaction,
+// the frames are ordered by the time they are entered.
+type InternalTxTracer struct {
+	blockNumber uint64
+	txIndex     uint64
+	txHash      common.Hash
+
+	frames []*InternalTx
+	// indexes of the open frames and the number of their sub calls
+	stack    []int
+	children []int
+}
+
+var _ vm.EVMLogger = (*InternalTxTracer)(nil)
+
+func NewInternalTxTracer(blockNumber uint64, txIndex uint64, txHash common.Hash) *InternalTxTracer {
+	return &InternalTxTracer{
+		blockNumber: blockNumber,
+		txIndex:     txIndex,
+		txHash:      txHash,
+	}
+}
+
+func (t *InternalTxTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *InternalTxTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *InternalTxTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.enter(typ, from, to, input, gas, value)
+}
+
+func (t *InternalTxTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	t.exit(gasUsed, err)
+}
+
+func (t *InternalTxTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *InternalTxTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+func (t *InternalTxTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.enter(typ, from, to, input, gas, value)
+}
+
+func (t *InternalTxTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.exit(gasUsed, err)
+}
+
+// InternalTxs returns the collected call frames, the first one is the top call of the transaction.
+func (t *InternalTxTracer) InternalTxs() []*InternalTx {
+	return t.frames
+}
+
+func (t *InternalTxTracer) enter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	traceAddress := emptyInternalTxTraceAddr
+	if len(t.stack) > 0 {
+		parent := len(t.stack) - 1
+		parentTraceAddress := t.frames[t.stack[parent]].TraceAddress
+		traceAddress = make([]int, len(parentTraceAddress)+1)
+		copy(traceAddress, parentTraceAddress)
+		traceAddress[len(parentTraceAddress)] = t.children[parent]
+		t.children[parent]++
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	t.frames = append(t.frames, &InternalTx{
+		BlockNumber:  hexutil.Uint64(t.blockNumber),
+		TxHash:       t.txHash,
+		TxIndex:      hexutil.Uint64(t.txIndex),
+		TraceAddress: traceAddress,
+		Type:         typ.String(),
+		From:         from,
+		To:           to,
+		Value:        (*hexutil.Big)(new(big.Int).Set(value)),
+		Gas:          hexutil.Uint64(gas),
+		Input:        common.CopyBytes(input),
+	})
+	t.stack = append(t.stack, len(t.frames)-1)
+	t.children = append(t.children, 0)
+}
+
+func (t *InternalTxTracer) exit(gasUsed uint64, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.frames[t.stack[len(t.stack)-1]]
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+	t.stack = t.stack[:len(t.stack)-1]
+	t.children = t.children[:len(t.children)-1]
+}
+
+// InternalTxIndexer persists the call trees of the executed transactions and
+// indexes the sub calls by the addresses they touched.
+type InternalTxIndexer struct {
+	store  kv.Storage
+	logger logrus.FieldLogger
+	lock   sync.RWMutex
+}
+
+func NewInternalTxIndexer(store kv.Storage) *InternalTxIndexer {
+	return &InternalTxIndexer{
+		store:  store,
+		logger: loggers.Logger(loggers.Indexer),
+	}
+}
+
+// IndexedHeight returns the latest indexed block number.
+func (idx *InternalTxIndexer) IndexedHeight() uint64 {
+	data := idx.store.Get(internalTxMetaKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteBlock persists the call trees of all transactions of the block.
+func (idx *InternalTxIndexer) WriteBlock(blockNumber uint64, internalTxs [][]*InternalTx) error {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	var blockInternalTxs []*InternalTx
+	batch := idx.store.NewBatch()
+	for _, txInternalTxs := range internalTxs {
+		if len(txInternalTxs) == 0 {
+			continue
+		}
+		data, err := json.Marshal(txInternalTxs)
+		if err != nil {
+			return fmt.Errorf("marshal internal txs error: %w", err)
+		}
+		batch.Put(internalTxHashKey(txInternalTxs[0].TxHash), data)
+
+		// the top call is recorded by the transaction itself
+		for i, itx := range txInternalTxs[1:] {
+			data, err := json.Marshal(itx)
+			if err != nil {
+				return fmt.Errorf("marshal internal tx error: %w", err)
+			}
+			batch.Put(internalTxAddressKey(itx.From, blockNumber, uint64(itx.TxIndex), i), data)
+			if itx.To != itx.From {
+				batch.Put(internalTxAddressKey(itx.To, blockNumber, uint64(itx.TxIndex), i), data)
+			}
+		}
+		blockInternalTxs = append(blockInternalTxs, txInternalTxs...)
+	}
+
+	data, err := json.Marshal(blockInternalTxs)
+	if err != nil {
+		return fmt.Errorf("marshal block internal txs error: %w", err)
+	}
+	batch.Put(internalTxBlockKey(blockNumber), data)
+	batch.Put(internalTxMetaKey, binary.BigEndian.AppendUint64(nil, blockNumber))
+	batch.Commit()
+
+	idx.logger.WithFields(logrus.Fields{
+		"height": blockNumber,
+		"count":  len(blockInternalTxs),
+	}).Debug("Index internal txs")
+	return nil
+}
+
+// MarkFailed records the block which failed to be indexed, so that the queries covering
+// the block return an error instead of silently missing its internal txs.
+func (idx *InternalTxIndexer) MarkFailed(blockNumber uint64, cause error) {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	batch := idx.store.NewBatch()
+	batch.Put(internalTxFailedKey(blockNumber), []byte(cause.Error()))
+	batch.Put(internalTxMetaKey, binary.BigEndian.AppendUint64(nil, blockNumber))
+	batch.Commit()
+
+	idx.logger.WithFields(logrus.Fields{
+		"height": blockNumber,
+		"err":    cause.Error(),
+	}).Warn("Mark internal txs index failed")
+}
+
+// Rollback removes the internal txs of the blocks above the height.
+func (idx *InternalTxIndexer) Rollback(height uint64) error {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	indexed := idx.IndexedHeight()
+	if indexed <= height {
+		return nil
+	}
+
+	batch := idx.store.NewBatch()
+	for i := indexed; i > height; i-- {
+		batch.Delete(internalTxFailedKey(i))
+		blockInternalTxs, err := idx.getBlockInternalTxs(i)
+		if err != nil {
+			if errors.Is(err, errInternalTxNotIndexed) {
+				continue
+			}
+			return err
+		}
+		txIndexes := make(map[common.Hash]int)
+		for _, itx := range blockInternalTxs {
+			// the frame index restarts from the first sub call of every tx
+			frame, ok := txIndexes[itx.TxHash]
+			txIndexes[itx.TxHash] = frame + 1
+			if !ok {
+				batch.Delete(internalTxHashKey(itx.TxHash))
+				continue
+			}
+			batch.Delete(internalTxAddressKey(itx.From, i, uint64(itx.TxIndex), frame-1))
+			batch.Delete(internalTxAddressKey(itx.To, i, uint64(itx.TxIndex), frame-1))
+		}
+		batch.Delete(internalTxBlockKey(i))
+	}
+	batch.Put(internalTxMetaKey, binary.BigEndian.AppendUint64(nil, height))
+	batch.Commit()
+
+	idx.logger.WithFields(logrus.Fields{
+		"from": indexed,
+		"to":   height,
+	}).Info("Rollback internal txs")
+	return nil
+}
+
+// GetInternalTxsByHash returns the flattened call tree of the transaction.
+func (idx *InternalTxIndexer) GetInternalTxsByHash(hash common.Hash) ([]*InternalTx, error) {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+
+	data := idx.store.Get(internalTxHashKey(hash))
+	if data == nil {
+		return nil, errInternalTxNotIndexed
+	}
+	var res []*InternalTx
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("unmarshal internal txs error: %w", err)
+	}
+	return res, nil
+}
+
+// GetInternalTxsByAddress returns the sub calls from or to the address in blocks [begin, end].
+func (idx *InternalTxIndexer) GetInternalTxsByAddress(addr common.Address, begin, end uint64) ([]*InternalTx, error) {
+	idx.lock.RLock()
+	defer idx.lock.RUnlock()
+
+	res := make([]*InternalTx, 0)
+	if indexed := idx.IndexedHeight(); end > indexed {
+		end = indexed
+	}
+	if begin > end {
+		return res, nil
+	}
+	failedIt := idx.store.Iterator(internalTxFailedKey(begin), internalTxFailedKey(end+1))
+	if failedIt.Next() {
+		return nil, fmt.Errorf("internal txs of block %d failed to be indexed: %s", binary.BigEndian.Uint64(failedIt.Key()[len(internalTxFailedPrefix):]), failedIt.Value())
+	}
+
+	prefix := append(common.CopyBytes(internalTxAddressPrefix), addr.Bytes()...)
+	start := binary.BigEndian.AppendUint64(common.CopyBytes(prefix), begin)
+	limit := binary.BigEndian.AppendUint64(common.CopyBytes(prefix), end+1)
+	it := idx.store.Iterator(start, limit)
+	for it.Next() {
+		itx := &InternalTx{}
+		if err := json.Unmarshal(it.Value(), itx); err != nil {
+			return nil, fmt.Errorf("unmarshal internal tx error: %w", err)
+		}
+		res = append(res, itx)
+	}
+	return res, nil
+}
+
+func (idx *InternalTxIndexer) getBlockInternalTxs(blockNumber uint64) ([]*InternalTx, error) {
+	data := idx.store.Get(internalTxBlockKey(blockNumber))
+	if data == nil {
+		return nil, errInternalTxNotIndexed
+	}
+	var res []*InternalTx
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("unmarshal block internal txs error: %w", err)
+	}
+	return res, nil
+}
+
+func internalTxBlockKey(blockNumber uint64) []byte {
+	return binary.BigEndian.AppendUint64(common.CopyBytes(internalTxBlockPrefix), blockNumber)
+}
+
+func internalTxFailedKey(blockNumber uint64) []byte {
+	return binary.BigEndian.AppendUint64(common.CopyBytes(internalTxFailedPrefix), blockNumber)
+}
+
+func internalTxHashKey(hash common.Hash) []byte {
+	return append(common.CopyBytes(internalTxHashPrefix), hash.Bytes()...)
+}
+
+func internalTxAddressKey(addr common.Address, blockNumber uint64, txIndex uint64, frame int) []byte {
+	key := append(common.CopyBytes(internalTxAddressPrefix), addr.Bytes()...)
+	key = binary.BigEndian.AppendUint64(key, blockNumber)
+	key = binary.BigEndian.AppendUint32(key, uint32(txIndex))
+	return binary.BigEndian.AppendUint32(key, uint32(frame))
+}
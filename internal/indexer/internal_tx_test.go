@@ -0,0 +1,134 @@
+package indexer
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-kit/storage/kv"
+)
+
+func TestInternalTxTracer(t *testing.T) {
+	from := common.HexToAddress("0x1")
+	contract := common.HexToAddress("0x2")
+	sysContract := common.HexToAddress("0x0000000000000000000000000000000000001001")
+	receiver := common.HexToAddress("0x3")
+
+	tracer := NewInternalTxTracer(10, 1, common.HexToHash("0xa"))
+	tracer.CaptureStart(nil, from, contract, false, []byte{1}, 100000, big.NewInt(1))
+	tracer.CaptureEnter(vm.CALL, contract, sysContract, nil, 5000, big.NewInt(0))
+	tracer.CaptureExit(nil, 100, nil)
+	tracer.CaptureEnter(vm.DELEGATECALL, contract, receiver, nil, 5000, nil)
+	tracer.CaptureEnter(vm.CALL, contract, receiver, nil, 2000, big.NewInt(2))
+	tracer.CaptureExit(nil, 200, errors.New("execution reverted"))
+	tracer.CaptureExit(nil, 300, nil)
+	tracer.CaptureEnd(nil, 1000, nil)
+
+	itxs := tracer.InternalTxs()
+	require.Len(t, itxs, 4)
+	assert.Equal(t, []int{}, itxs[0].TraceAddress)
+	assert.Equal(t, "CALL", itxs[0].Type)
+	assert.EqualValues(t, 1000, itxs[0].GasUsed)
+	assert.Equal(t, []int{0}, itxs[1].TraceAddress)
+	assert.Equal(t, sysContract, itxs[1].To)
+	assert.Equal(t, []int{1}, itxs[2].TraceAddress)
+	assert.Equal(t, "DELEGATECALL", itxs[2].Type)
+	assert.EqualValues(t, 0, itxs[2].Value.ToInt().Int64())
+	assert.Equal(t, []int{1, 0}, itxs[3].TraceAddress)
+	assert.Equal(t, "execution reverted", itxs[3].Error)
+	assert.EqualValues(t, 2, itxs[3].Value.ToInt().Int64())
+	for _, itx := range itxs {
+		assert.EqualValues(t, 10, itx.BlockNumber)
+		assert.EqualValues(t, 1, itx.TxIndex)
+	}
+}
+
+func TestInternalTxIndexer(t *testing.T) {
+	idx := NewInternalTxIndexer(kv.NewMemory())
+	from := common.HexToAddress("0x1")
+	contract := common.HexToAddress("0x2")
+	receiver := common.HexToAddress("0x3")
+
+	traceTx := func(blockNumber uint64, txIndex uint64, txHash common.Hash) []*InternalTx {
+		tracer := NewInternalTxTracer(blockNumber, txIndex, txHash)
+		tracer.CaptureStart(nil, from, contract, false, nil, 100000, big.NewInt(1))
+		tracer.CaptureEnter(vm.CALL, contract, receiver, nil, 5000, big.NewInt(1))
+		tracer.CaptureExit(nil, 100, nil)
+		tracer.CaptureEnd(nil, 1000, nil)
+		return tracer.InternalTxs()
+	}
+
+	for i := uint64(1); i <= 5; i++ {
+		err := idx.WriteBlock(i, [][]*InternalTx{
+			traceTx(i, 0, common.BigToHash(new(big.Int).SetUint64(i*10))),
+			nil,
+			traceTx(i, 2, common.BigToHash(new(big.Int).SetUint64(i*10+2))),
+		})
+		require.Nil(t, err)
+	}
+	assert.EqualValues(t, 5, idx.IndexedHeight())
+
+	itxs, err := idx.GetInternalTxsByHash(common.BigToHash(big.NewInt(32)))
+	require.Nil(t, err)
+	require.Len(t, itxs, 2)
+	assert.EqualValues(t, 3, itxs[1].BlockNumber)
+	assert.EqualValues(t, 2, itxs[1].TxIndex)
+
+	// the top call is not indexed by address
+	itxs, err = idx.GetInternalTxsByAddress(from, 1, 5)
+	require.Nil(t, err)
+	assert.Len(t, itxs, 0)
+
+	itxs, err = idx.GetInternalTxsByAddress(receiver, 2, 4)
+	require.Nil(t, err)
+	require.Len(t, itxs, 6)
+	assert.EqualValues(t, 2, itxs[0].BlockNumber)
+	assert.EqualValues(t, 0, itxs[0].TxIndex)
+	assert.EqualValues(t, 4, itxs[5].BlockNumber)
+	assert.EqualValues(t, 2, itxs[5].TxIndex)
+
+	itxs, err = idx.GetInternalTxsByAddress(contract, 1, 100)
+	require.Nil(t, err)
+	assert.Len(t, itxs, 10)
+
+	err = idx.Rollback(3)
+	require.Nil(t, err)
+	assert.EqualValues(t, 3, idx.IndexedHeight())
+	itxs, err = idx.GetInternalTxsByAddress(receiver, 1, 5)
+	require.Nil(t, err)
+	assert.Len(t, itxs, 6)
+	_, err = idx.GetInternalTxsByHash(common.BigToHash(big.NewInt(40)))
+	assert.ErrorIs(t, err, errInternalTxNotIndexed)
+	_, err = idx.GetInternalTxsByHash(common.BigToHash(big.NewInt(30)))
+	assert.Nil(t, err)
+
+	// the rolled back blocks can be indexed again
+	err = idx.WriteBlock(4, [][]*InternalTx{traceTx(4, 0, common.BigToHash(big.NewInt(40)))})
+	require.Nil(t, err)
+	itxs, err = idx.GetInternalTxsByAddress(receiver, 4, 4)
+	require.Nil(t, err)
+	assert.Len(t, itxs, 1)
+
+	// the queries covering the failed block return error
+	idx.MarkFailed(5, errors.New("marshal error"))
+	assert.EqualValues(t, 5, idx.IndexedHeight())
+	_, err = idx.GetInternalTxsByAddress(receiver, 1, 5)
+	assert.ErrorContains(t, err, "internal txs of block 5 failed to be indexed: marshal error")
+	itxs, err = idx.GetInternalTxsByAddress(receiver, 1, 4)
+	require.Nil(t, err)
+	assert.Len(t, itxs, 7)
+
+	// the failed block can be indexed again after rollback
+	err = idx.Rollback(4)
+	require.Nil(t, err)
+	err = idx.WriteBlock(5, [][]*InternalTx{traceTx(5, 0, common.BigToHash(big.NewInt(50)))})
+	require.Nil(t, err)
+	itxs, err = idx.GetInternalTxsByAddress(receiver, 1, 5)
+	require.Nil(t, err)
+	assert.Len(t, itxs, 8)
+}
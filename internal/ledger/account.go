@@ -60,6 +60,8 @@ type SimpleAccount struct {
 	created        bool // Flag whether the account was created in the current transaction
 
 	snapshot *snapshot.Snapshot
+
+	fork *forkReader
 }
 
 func NewMockAccount(blockHeight uint64, addr *types.Address) *SimpleAccount {
@@ -159,6 +161,12 @@ func (o *SimpleAccount) GetState(key []byte) (bool, []byte) {
 
 	if o.snapshot != nil {
 		if value, err := o.snapshot.Storage(o.Addr, key); err == nil {
+			if value == nil {
+				var ok bool
+				if value, ok = o.loadForkState(key); !ok {
+					return false, nil
+				}
+			}
 			o.originState[string(key)] = value
 			o.initStorageTrie()
 			o.logger.Debugf("[GetState] get from snapshot, addr: %v, key: %v, state: %v", o.Addr, &bytesLazyLogger{bytes: key}, &bytesLazyLogger{bytes: value})
@@ -171,6 +179,12 @@ func (o *SimpleAccount) GetState(key []byte) (bool, []byte) {
 	if err != nil {
 		panic(err)
 	}
+	if val == nil {
+		var ok bool
+		if val, ok = o.loadForkState(key); !ok {
+			return false, nil
+		}
+	}
 	o.logger.Debugf("[GetState] get from storage trie, addr: %v, key: %v, state: %v", o.Addr, string(key), &bytesLazyLogger{bytes: val})
 
 	o.originState[string(key)] = val
@@ -199,6 +213,12 @@ func (o *SimpleAccount) GetCommittedState(key []byte) []byte {
 
 	if o.snapshot != nil {
 		if value, err := o.snapshot.Storage(o.Addr, key); err == nil {
+			if value == nil {
+				var ok bool
+				if value, ok = o.loadForkState(key); !ok {
+					return (&types.Hash{}).Bytes()
+				}
+			}
 			o.originState[string(key)] = value
 			o.initStorageTrie()
 			o.logger.Debugf("[GetCommittedState] get from snapshot, addr: %v, key: %v, state: %v", o.Addr, &bytesLazyLogger{bytes: key}, &bytesLazyLogger{bytes: value})
@@ -214,6 +234,12 @@ func (o *SimpleAccount) GetCommittedState(key []byte) []byte {
 	if err != nil {
 		panic(err)
 	}
+	if val == nil {
+		var ok bool
+		if val, ok = o.loadForkState(key); !ok {
+			return (&types.Hash{}).Bytes()
+		}
+	}
 	o.logger.Debugf("[GetCommittedState] get from storage trie, addr: %v, key: %v, state: %v", o.Addr, string(key), &bytesLazyLogger{bytes: val})
 
 	o.originState[string(key)] = val
@@ -0,0 +1,156 @@
+package blockarchive
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+// A block archive is a portable stream of blocks and receipts, the block record payload
+// is the protobuf encoded block and receipts.
+const (
+	blockArchiveMagic = "AXMBLKAR"
+
+	recordKindBlock = 1
+)
+
+// Header describes the chain and the block range of an archive.
+type Header struct {
+	ChainID     uint64
+	GenesisHash *types.Hash
+	From        uint64
+	To          uint64
+}
+
+func (h *Header) encode() []byte {
+	buf := make([]byte, 0, 8+common.HashLength+8+8)
+	buf = binary.BigEndian.AppendUint64(buf, h.ChainID)
+	buf = append(buf, h.GenesisHash.Bytes()...)
+	buf = binary.BigEndian.AppendUint64(buf, h.From)
+	buf = binary.BigEndian.AppendUint64(buf, h.To)
+	return buf
+}
+
+func (h *Header) decode(data []byte) error {
+	if len(data) != 8+common.HashLength+8+8 {
+		return fmt.Errorf("invalid block archive header length %d", len(data))
+	}
+	h.ChainID = binary.BigEndian.Uint64(data[:8])
+	h.GenesisHash = types.NewHash(data[8 : 8+common.HashLength])
+	h.From = binary.BigEndian.Uint64(data[8+common.HashLength:])
+	h.To = binary.BigEndian.Uint64(data[16+common.HashLength:])
+	return nil
+}
+
+// Writer writes blocks into an archive, Close must be called to finish the archive.
+type Writer struct {
+	rw     *recordWriter
+	header *Header
+	count  uint64
+}
+
+func NewWriter(w io.Writer, header *Header) (*Writer, error) {
+	if header.From > header.To {
+		return nil, fmt.Errorf("invalid block range [%d, %d]", header.From, header.To)
+	}
+	rw, err := newRecordWriter(w, blockArchiveMagic, header.encode())
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{rw: rw, header: header}, nil
+}
+
+// WriteBlock appends the block and its receipts, blocks must be written in order.
+func (aw *Writer) WriteBlock(block *types.Block, receipts []*types.Receipt) error {
+	expected := aw.header.From + aw.count
+	if block.Height() != expected || expected > aw.header.To {
+		return fmt.Errorf("unexpected block %d, expect block %d in range [%d, %d]", block.Height(), expected, aw.header.From, aw.header.To)
+	}
+	blockData, err := block.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal block %d: %w", block.Height(), err)
+	}
+	receiptsData, err := types.MarshalReceipts(receipts)
+	if err != nil {
+		return fmt.Errorf("marshal receipts of block %d: %w", block.Height(), err)
+	}
+
+	payload := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(blockData)+len(receiptsData)), uint64(len(blockData)))
+	payload = append(payload, blockData...)
+	payload = append(payload, receiptsData...)
+	if err := aw.rw.writeRecord(recordKindBlock, payload); err != nil {
+		return err
+	}
+	aw.count++
+	return nil
+}
+
+// Close writes the end record, the underlying writer is not closed.
+func (aw *Writer) Close() error {
+	if aw.count != aw.header.To-aw.header.From+1 {
+		return fmt.Errorf("block archive is incomplete, %d blocks are written, expect %d", aw.count, aw.header.To-aw.header.From+1)
+	}
+	return aw.rw.close()
+}
+
+// Reader reads blocks from an archive, the checksums are verified while reading.
+type Reader struct {
+	rr     *recordReader
+	header *Header
+	count  uint64
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	rr, data, err := newRecordReader(r, blockArchiveMagic)
+	if err != nil {
+		return nil, err
+	}
+	header := &Header{}
+	if err := header.decode(data); err != nil {
+		return nil, err
+	}
+	return &Reader{rr: rr, header: header}, nil
+}
+
+func (ar *Reader) Header() *Header {
+	return ar.header
+}
+
+// Next returns the next block and its receipts, io.EOF is returned after the whole archive is verified.
+func (ar *Reader) Next() (*types.Block, []*types.Receipt, error) {
+	kind, payload, err := ar.rr.readRecord()
+	if err == io.EOF {
+		if ar.count != ar.header.To-ar.header.From+1 {
+			return nil, nil, fmt.Errorf("%w: %d blocks are read, expect %d", ErrUnexpectedEnd, ar.count, ar.header.To-ar.header.From+1)
+		}
+		return nil, nil, io.EOF
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind != recordKindBlock {
+		return nil, nil, fmt.Errorf("unexpected record kind %d in block archive", kind)
+	}
+
+	blockLen, n := binary.Uvarint(payload)
+	if n <= 0 || uint64(len(payload)-n) < blockLen {
+		return nil, nil, fmt.Errorf("invalid block record at block %d", ar.header.From+ar.count)
+	}
+	block := &types.Block{}
+	if err := block.Unmarshal(payload[n : n+int(blockLen)]); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal block %d: %w", ar.header.From+ar.count, err)
+	}
+	receipts, err := types.UnmarshalReceipts(payload[n+int(blockLen):])
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal receipts of block %d: %w", ar.header.From+ar.count, err)
+	}
+	if block.Height() != ar.header.From+ar.count || block.Height() > ar.header.To {
+		return nil, nil, fmt.Errorf("unexpected block %d, expect block %d", block.Height(), ar.header.From+ar.count)
+	}
+	ar.count++
+	return block, receipts, nil
+}
@@ -0,0 +1,153 @@
+package blockarchive
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+func mockBlocks(t *testing.T, from, to uint64) ([]*types.Block, [][]*types.Receipt) {
+	var blocks []*types.Block
+	var receipts [][]*types.Receipt
+	for i := from; i <= to; i++ {
+		tx, err := types.GenerateEmptyTransactionAndSigner()
+		require.Nil(t, err)
+		blocks = append(blocks, &types.Block{
+			Header: &types.BlockHeader{
+				Number:     i,
+				ParentHash: types.NewHashByStr("0x1"),
+				StateRoot:  types.NewHashByStr("0x2"),
+				Timestamp:  int64(i),
+			},
+			Transactions: []*types.Transaction{tx},
+		})
+		receipts = append(receipts, []*types.Receipt{{
+			TxHash:            tx.GetHash(),
+			GasUsed:           21000,
+			Status:            types.ReceiptSUCCESS,
+			EffectiveGasPrice: big.NewInt(1),
+		}})
+	}
+	return blocks, receipts
+}
+
+func writeArchive(t *testing.T, header *Header) []byte {
+	blocks, receipts := mockBlocks(t, header.From, header.To)
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, header)
+	require.Nil(t, err)
+	for i, block := range blocks {
+		require.Nil(t, w.WriteBlock(block, receipts[i]))
+	}
+	require.Nil(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestArchive(t *testing.T) {
+	header := &Header{ChainID: 1356, GenesisHash: types.NewHashByStr("0xabc"), From: 3, To: 7}
+
+	t.Run("read and write", func(t *testing.T) {
+		data := writeArchive(t, header)
+		r, err := NewReader(bytes.NewReader(data))
+		require.Nil(t, err)
+		assert.EqualValues(t, 1356, r.Header().ChainID)
+		assert.Equal(t, header.GenesisHash.String(), r.Header().GenesisHash.String())
+
+		for i := header.From; i <= header.To; i++ {
+			block, receipts, err := r.Next()
+			require.Nil(t, err)
+			assert.Equal(t, i, block.Height())
+			require.Len(t, block.Transactions, 1)
+			require.Len(t, receipts, 1)
+			assert.Equal(t, block.Transactions[0].GetHash().String(), receipts[0].TxHash.String())
+		}
+		_, _, err = r.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("incomplete archive", func(t *testing.T) {
+		w, err := NewWriter(io.Discard, header)
+		require.Nil(t, err)
+		blocks, receipts := mockBlocks(t, 3, 4)
+		err = w.WriteBlock(blocks[1], receipts[1])
+		assert.ErrorContains(t, err, "unexpected block 4")
+		require.Nil(t, w.WriteBlock(blocks[0], receipts[0]))
+		assert.NotNil(t, w.Close())
+	})
+
+	t.Run("corrupted archive", func(t *testing.T) {
+		data := writeArchive(t, header)
+
+		_, err := NewReader(bytes.NewReader([]byte("not an archive")))
+		assert.ErrorIs(t, err, ErrInvalidMagic)
+
+		readAll := func(data []byte) error {
+			r, err := NewReader(bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			for {
+				if _, _, err := r.Next(); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+			}
+		}
+
+		corrupted := bytes.Clone(data)
+		corrupted[len(corrupted)/2] ^= 0xff
+		assert.ErrorIs(t, readAll(corrupted), ErrChecksumMismatch)
+
+		assert.ErrorIs(t, readAll(data[:len(data)-10]), ErrUnexpectedEnd)
+	})
+}
+
+func TestStateArchive(t *testing.T) {
+	entries := map[string]string{
+		"key1": "value1",
+		"key2": "",
+		"key3": "value3",
+	}
+	buf := &bytes.Buffer{}
+	w, err := NewStateWriter(buf, 1356, []byte("meta"))
+	require.Nil(t, err)
+	for k, v := range entries {
+		require.Nil(t, w.Put([]byte(k), []byte(v)))
+	}
+	require.Nil(t, w.Close())
+
+	r, err := NewStateReader(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	assert.EqualValues(t, 1356, r.ChainID())
+	assert.Equal(t, []byte("meta"), r.Meta())
+	read := make(map[string]string)
+	for {
+		key, value, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		read[string(key)] = string(value)
+	}
+	assert.Equal(t, entries, read)
+
+	_, err = NewReader(bytes.NewReader(buf.Bytes()))
+	assert.ErrorIs(t, err, ErrInvalidMagic)
+
+	r, err = NewStateReader(bytes.NewReader(buf.Bytes()[:buf.Len()-10]))
+	require.Nil(t, err)
+	for {
+		if _, _, err = r.Next(); err != nil {
+			break
+		}
+	}
+	assert.ErrorIs(t, err, ErrUnexpectedEnd)
+}
@@ -0,0 +1,187 @@
+package blockarchive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// Every archive file is a checksummed record stream:
+//
+//	magic | version | header record | record... | end record
+//
+// every record is framed as kind(1 byte) | payload length(uvarint) | payload | crc32(payload),
+// and the end record carries the number of records and the sha256 digest of all the bytes before it.
+const (
+	Version = 1
+
+	recordKindHeader = 0
+	recordKindEnd    = 2
+
+	// maxRecordSize limits the memory used by a corrupted length prefix
+	maxRecordSize = 1 << 30
+)
+
+var (
+	ErrInvalidMagic       = errors.New("not an archive file")
+	ErrUnsupportedVersion = errors.New("unsupported archive version")
+	ErrChecksumMismatch   = errors.New("archive checksum mismatch")
+	ErrUnexpectedEnd      = errors.New("archive is truncated")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type recordWriter struct {
+	w      *bufio.Writer
+	digest hash.Hash
+	count  uint64
+}
+
+func newRecordWriter(w io.Writer, magic string, header []byte) (*recordWriter, error) {
+	digest := sha256.New()
+	rw := &recordWriter{
+		w:      bufio.NewWriter(io.MultiWriter(w, digest)),
+		digest: digest,
+	}
+	if _, err := rw.w.WriteString(magic); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(rw.w, binary.BigEndian, uint32(Version)); err != nil {
+		return nil, err
+	}
+	if err := rw.writeRecord(recordKindHeader, header); err != nil {
+		return nil, err
+	}
+	rw.count = 0
+	return rw, nil
+}
+
+func (rw *recordWriter) writeRecord(kind byte, payload []byte) error {
+	var buf [1 + binary.MaxVarintLen64]byte
+	buf[0] = kind
+	n := binary.PutUvarint(buf[1:], uint64(len(payload)))
+	if _, err := rw.w.Write(buf[:1+n]); err != nil {
+		return err
+	}
+	if _, err := rw.w.Write(payload); err != nil {
+		return err
+	}
+	if err := binary.Write(rw.w, binary.BigEndian, crc32.Checksum(payload, crcTable)); err != nil {
+		return err
+	}
+	rw.count++
+	return nil
+}
+
+func (rw *recordWriter) close() error {
+	// the digest only covers the flushed bytes
+	if err := rw.w.Flush(); err != nil {
+		return err
+	}
+	payload := binary.BigEndian.AppendUint64(nil, rw.count)
+	payload = append(payload, rw.digest.Sum(nil)...)
+	if err := rw.writeRecord(recordKindEnd, payload); err != nil {
+		return err
+	}
+	return rw.w.Flush()
+}
+
+type recordReader struct {
+	r      *bufio.Reader
+	digest hash.Hash
+	count  uint64
+	done   bool
+}
+
+// Read and ReadByte hash the bytes consumed from the buffered reader.
+func (rr *recordReader) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	rr.digest.Write(p[:n])
+	return n, err
+}
+
+func (rr *recordReader) ReadByte() (byte, error) {
+	b, err := rr.r.ReadByte()
+	if err == nil {
+		rr.digest.Write([]byte{b})
+	}
+	return b, err
+}
+
+func newRecordReader(r io.Reader, magic string) (*recordReader, []byte, error) {
+	rr := &recordReader{
+		r:      bufio.NewReader(r),
+		digest: sha256.New(),
+	}
+
+	head := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(rr, head); err != nil {
+		return nil, nil, ErrInvalidMagic
+	}
+	if !bytes.Equal(head[:len(magic)], []byte(magic)) {
+		return nil, nil, ErrInvalidMagic
+	}
+	if version := binary.BigEndian.Uint32(head[len(magic):]); version != Version {
+		return nil, nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	kind, header, err := rr.readRecord()
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind != recordKindHeader {
+		return nil, nil, fmt.Errorf("unexpected record kind %d, expect the header", kind)
+	}
+	rr.count = 0
+	return rr, header, nil
+}
+
+// readRecord returns the next record, io.EOF is returned after the end record is verified.
+func (rr *recordReader) readRecord() (byte, []byte, error) {
+	if rr.done {
+		return 0, nil, io.EOF
+	}
+	// the digest of the end record covers all the bytes before it
+	sum := rr.digest.Sum(nil)
+
+	kind, err := rr.ReadByte()
+	if err != nil {
+		return 0, nil, ErrUnexpectedEnd
+	}
+	size, err := binary.ReadUvarint(rr)
+	if err != nil {
+		return 0, nil, ErrUnexpectedEnd
+	}
+	if size > maxRecordSize {
+		return 0, nil, fmt.Errorf("archive record size %d exceeds the limit", size)
+	}
+	payload := make([]byte, size+4)
+	if _, err := io.ReadFull(rr, payload); err != nil {
+		return 0, nil, ErrUnexpectedEnd
+	}
+	if crc32.Checksum(payload[:size], crcTable) != binary.BigEndian.Uint32(payload[size:]) {
+		return 0, nil, ErrChecksumMismatch
+	}
+	payload = payload[:size]
+
+	if kind != recordKindEnd {
+		rr.count++
+		return kind, payload, nil
+	}
+	if len(payload) != 8+sha256.Size {
+		return 0, nil, fmt.Errorf("invalid end record length %d", len(payload))
+	}
+	if count := binary.BigEndian.Uint64(payload[:8]); count != rr.count {
+		return 0, nil, fmt.Errorf("%w: %d records are read, expect %d", ErrUnexpectedEnd, rr.count, count)
+	}
+	if !bytes.Equal(payload[8:], sum) {
+		return 0, nil, ErrChecksumMismatch
+	}
+	rr.done = true
+	return 0, nil, io.EOF
+}
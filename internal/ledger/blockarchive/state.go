@@ -0,0 +1,84 @@
+package blockarchive
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// A state archive is a portable stream of the state trie kv entries at a block, the header
+// carries the chain id and the encoded snapshot meta of the block.
+const (
+	stateArchiveMagic = "AXMSTATE"
+
+	recordKindState = 3
+)
+
+// StateWriter writes state entries into an archive, Close must be called to finish the archive.
+type StateWriter struct {
+	rw *recordWriter
+}
+
+func NewStateWriter(w io.Writer, chainID uint64, meta []byte) (*StateWriter, error) {
+	header := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(meta)), chainID)
+	header = append(header, meta...)
+	rw, err := newRecordWriter(w, stateArchiveMagic, header)
+	if err != nil {
+		return nil, err
+	}
+	return &StateWriter{rw: rw}, nil
+}
+
+func (sw *StateWriter) Put(key, value []byte) error {
+	payload := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(key)+len(value)), uint64(len(key)))
+	payload = append(payload, key...)
+	payload = append(payload, value...)
+	return sw.rw.writeRecord(recordKindState, payload)
+}
+
+// Close writes the end record, the underlying writer is not closed.
+func (sw *StateWriter) Close() error {
+	return sw.rw.close()
+}
+
+// StateReader reads state entries from an archive, the checksums are verified while reading.
+type StateReader struct {
+	rr      *recordReader
+	chainID uint64
+	meta    []byte
+}
+
+func NewStateReader(r io.Reader) (*StateReader, error) {
+	rr, header, err := newRecordReader(r, stateArchiveMagic)
+	if err != nil {
+		return nil, err
+	}
+	if len(header) < 8 {
+		return nil, fmt.Errorf("invalid state archive header length %d", len(header))
+	}
+	return &StateReader{rr: rr, chainID: binary.BigEndian.Uint64(header[:8]), meta: header[8:]}, nil
+}
+
+func (sr *StateReader) ChainID() uint64 {
+	return sr.chainID
+}
+
+func (sr *StateReader) Meta() []byte {
+	return sr.meta
+}
+
+// Next returns the next state entry, io.EOF is returned after the whole archive is verified.
+func (sr *StateReader) Next() ([]byte, []byte, error) {
+	kind, payload, err := sr.rr.readRecord()
+	if err != nil {
+		return nil, nil, err
+	}
+	if kind != recordKindState {
+		return nil, nil, fmt.Errorf("unexpected record kind %d in state archive", kind)
+	}
+	keyLen, n := binary.Uvarint(payload)
+	if n <= 0 || uint64(len(payload)-n) < keyLen {
+		return nil, nil, fmt.Errorf("invalid state record %d", sr.rr.count)
+	}
+	return payload[n : n+int(keyLen)], payload[n+int(keyLen):], nil
+}
@@ -1,6 +1,7 @@
 package ledger
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,12 +9,14 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/sirupsen/logrus"
 
 	"github.com/axiomesh/axiom-kit/storage/blockfile"
 	"github.com/axiomesh/axiom-kit/storage/kv"
 	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/components"
 	"github.com/axiomesh/axiom-ledger/internal/ledger/utils"
 	"github.com/axiomesh/axiom-ledger/internal/storagemgr"
 	"github.com/axiomesh/axiom-ledger/pkg/loggers"
@@ -21,9 +24,40 @@ import (
 )
 
 var (
-	ErrNotFound = errors.New("not found in DB")
+	ErrNotFound                 = errors.New("not found in DB")
+	ErrAddressTxIndexerDisabled = errors.New("address tx indexer is disabled")
+	ErrSnapshotDisabled         = errors.New("snapshot is disabled")
 )
 
+const (
+	AddressTxDirectionFrom = "from"
+	AddressTxDirectionTo   = "to"
+	AddressTxDirectionSelf = "self"
+)
+
+// direction codes persisted in the address tx index
+const (
+	addressTxFrom byte = iota + 1
+	addressTxTo
+	addressTxSelf
+)
+
+var addressTxDirections = []string{"", AddressTxDirectionFrom, AddressTxDirectionTo, AddressTxDirectionSelf}
+
+// AddressTx is a transaction sent from or to an address
+type AddressTx struct {
+	TxHash      *types.Hash
+	BlockNumber uint64
+	TxIndex     uint64
+	Direction   string
+}
+
+// BundleTx is the bundle info of a tx batched in a bundle, Reverted is set if the tx failed in execution
+type BundleTx struct {
+	BundleID string `json:"bundleId"`
+	Reverted bool   `json:"reverted"`
+}
+
 var _ ChainLedger = (*ChainLedgerImpl)(nil)
 
 type ChainLedgerImpl struct {
@@ -219,6 +253,7 @@ func (l *ChainLedgerImpl) GetBlockTxList(height uint64) ([]*types.Transaction, e
 		if err != nil {
 			return nil, fmt.Errorf("unmarshal txs bytes error: %w", err)
 		}
+		l.restoreImpersonatedSenders(txs...)
 		l.blockTxsCache.Add(height, txs)
 	}
 	return txs, nil
@@ -246,12 +281,28 @@ func (l *ChainLedgerImpl) GetTransaction(hash *types.Hash) (*types.Transaction,
 			return nil, fmt.Errorf("get transactions with height %d from blockfile failed: %w", meta.BlockHeight, err)
 		}
 
-		return types.UnmarshalTransactionWithIndex(txsBytes, meta.Index)
+		tx, err := types.UnmarshalTransactionWithIndex(txsBytes, meta.Index)
+		if err != nil {
+			return nil, err
+		}
+		l.restoreImpersonatedSenders(tx)
+		return tx, nil
 	}
 
 	return txs[meta.Index], nil
 }
 
+// restoreImpersonatedSenders restores the senders of the decoded impersonated txs,
+// which can not be recovered from the signatures.
+func (l *ChainLedgerImpl) restoreImpersonatedSenders(txs ...*types.Transaction) {
+	if !l.repo.Config.IsImpersonationSupported() {
+		return
+	}
+	for _, tx := range txs {
+		components.RestoreImpersonatedSender(tx)
+	}
+}
+
 func (l *ChainLedgerImpl) GetTransactionCount(height uint64) (uint64, error) {
 	txHashesData := l.blockchainStore.Get(utils.CompositeKey(utils.BlockTxSetKey, height))
 	if txHashesData == nil {
@@ -305,6 +356,33 @@ func (l *ChainLedgerImpl) GetReceipt(hash *types.Hash) (*types.Receipt, error) {
 	return rs[meta.Index], nil
 }
 
+// GetBundleTx get the bundle info of the tx, ErrNotFound if the tx is not batched in a bundle
+func (l *ChainLedgerImpl) GetBundleTx(hash *types.Hash) (*BundleTx, error) {
+	data := l.blockchainStore.Get(utils.CompositeKey(utils.BundleTxKey, hash.String()))
+	if data == nil {
+		return nil, ErrNotFound
+	}
+	bundleTx := &BundleTx{}
+	if err := json.Unmarshal(data, bundleTx); err != nil {
+		return nil, fmt.Errorf("unmarshal bundle tx error: %w", err)
+	}
+	return bundleTx, nil
+}
+
+// PersistBundleTxs persist the bundle info of the txs keyed by tx hash
+func (l *ChainLedgerImpl) PersistBundleTxs(bundleTxs map[string]*BundleTx) error {
+	batcher := l.blockchainStore.NewBatch()
+	for hash, bundleTx := range bundleTxs {
+		data, err := json.Marshal(bundleTx)
+		if err != nil {
+			return fmt.Errorf("marshal bundle tx error: %w", err)
+		}
+		batcher.Put(utils.CompositeKey(utils.BundleTxKey, hash), data)
+	}
+	batcher.Commit()
+	return nil
+}
+
 func (l *ChainLedgerImpl) GetBlockReceipts(height uint64) ([]*types.Receipt, error) {
 	var rs []*types.Receipt
 	rs, ok := l.blockReceiptsCache.Get(height)
@@ -364,6 +442,7 @@ func (l *ChainLedgerImpl) doBatchPersistExecutionResult(batchBlock []*types.Bloc
 			return fmt.Errorf("prepare transactions failed: %w", err)
 		}
 		listOfTransactions = append(listOfTransactions, ts)
+		l.prepareAddressTxs(batcher, block, receipts)
 
 		h, e, err := l.prepareBlock(batcher, block)
 		if err != nil {
@@ -462,6 +541,74 @@ func (l *ChainLedgerImpl) prepareTransactions(batcher kv.Batch, block *types.Blo
 	return types.MarshalTransactions(block.Transactions)
 }
 
+func (l *ChainLedgerImpl) prepareAddressTxs(batcher kv.Batch, block *types.Block, receipts []*types.Receipt) {
+	if !l.repo.Config.Ledger.EnableAddressTxIndexer {
+		return
+	}
+	l.forEachAddressTx(block, receipts, func(addr *types.Address, index uint64, direction byte) {
+		value := append([]byte{direction}, block.Transactions[index].GetHash().Bytes()...)
+		batcher.Put(utils.CompositeAddressTxKey(addr, block.Header.Number, index), value)
+	})
+}
+
+// forEachAddressTx calls fn with the sender and the receiver of every tx in the block,
+// the receiver of a contract creation tx is the created contract
+func (l *ChainLedgerImpl) forEachAddressTx(block *types.Block, receipts []*types.Receipt, fn func(addr *types.Address, index uint64, direction byte)) {
+	for i, tx := range block.Transactions {
+		from := tx.GetFrom()
+		to := tx.GetTo()
+		if to == nil && i < len(receipts) {
+			to = receipts[i].ContractAddress
+		}
+		switch {
+		case from != nil && to != nil && from.String() == to.String():
+			fn(from, uint64(i), addressTxSelf)
+		default:
+			if from != nil {
+				fn(from, uint64(i), addressTxFrom)
+			}
+			if to != nil {
+				fn(to, uint64(i), addressTxTo)
+			}
+		}
+	}
+}
+
+// GetTransactionsByAddress returns the txs sent from or to the address in blocks [begin, end]
+// ordered by block height and tx index, skipping the first offset txs and returning at most limit txs
+func (l *ChainLedgerImpl) GetTransactionsByAddress(addr *types.Address, begin, end uint64, offset, limit uint64) ([]*AddressTx, error) {
+	if !l.repo.Config.Ledger.EnableAddressTxIndexer {
+		return nil, ErrAddressTxIndexerDisabled
+	}
+
+	res := make([]*AddressTx, 0)
+	if begin > end || limit == 0 {
+		return res, nil
+	}
+	it := l.blockchainStore.Iterator(utils.CompositeAddressTxKey(addr, begin, 0), utils.CompositeAddressTxKey(addr, end+1, 0))
+	prefixLen := len(utils.AddressTxKey) + common.AddressLength
+	for it.Next() {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		key, value := it.Key(), it.Value()
+		if len(key) != prefixLen+16 || len(value) != 1+common.HashLength || int(value[0]) >= len(addressTxDirections) {
+			return nil, fmt.Errorf("invalid address tx index: %x", key)
+		}
+		res = append(res, &AddressTx{
+			TxHash:      types.NewHash(value[1:]),
+			BlockNumber: binary.BigEndian.Uint64(key[prefixLen : prefixLen+8]),
+			TxIndex:     binary.BigEndian.Uint64(key[prefixLen+8:]),
+			Direction:   addressTxDirections[value[0]],
+		})
+		if uint64(len(res)) >= limit {
+			break
+		}
+	}
+	return res, nil
+}
+
 func (l *ChainLedgerImpl) prepareBlock(batcher kv.Batch, block *types.Block) (header []byte, extra []byte, err error) {
 	if block.Extra == nil {
 		block.Extra = &types.BlockExtra{}
@@ -513,6 +660,16 @@ func (l *ChainLedgerImpl) removeChainDataOnBlock(batch kv.Batch, height uint64)
 		return fmt.Errorf("get block with height %d failed: %w", height, err)
 	}
 
+	if l.repo.Config.Ledger.EnableAddressTxIndexer {
+		receipts, err := l.GetBlockReceipts(height)
+		if err != nil {
+			return fmt.Errorf("get receipts with height %d failed: %w", height, err)
+		}
+		l.forEachAddressTx(block, receipts, func(addr *types.Address, index uint64, _ byte) {
+			batch.Delete(utils.CompositeAddressTxKey(addr, height, index))
+		})
+	}
+
 	if err := l.bf.TruncateBlocks(height - 1); err != nil {
 		return fmt.Errorf("truncate blocks failed: %w", err)
 	}
@@ -522,6 +679,7 @@ func (l *ChainLedgerImpl) removeChainDataOnBlock(batch kv.Batch, height uint64)
 
 	for _, tx := range block.Transactions {
 		batch.Delete(utils.CompositeKey(utils.TransactionMetaKey, tx.GetHash().String()))
+		batch.Delete(utils.CompositeKey(utils.BundleTxKey, tx.GetHash().String()))
 	}
 
 	l.blockTxsCache.Remove(height)
@@ -598,6 +756,13 @@ func (l *ChainLedgerImpl) checkChainMeta() error {
 		if err != nil {
 			return err
 		}
+		if l.repo.Config.Ledger.EnableAddressTxIndexer {
+			receipts, err := l.GetBlockReceipts(l.chainMeta.Height)
+			if err != nil {
+				return fmt.Errorf("get blockfile receipts: %w", err)
+			}
+			l.prepareAddressTxs(batcher, currentBlock, receipts)
+		}
 		_, _, err = l.prepareBlock(batcher, currentBlock)
 		if err != nil {
 			return err
@@ -0,0 +1,318 @@
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/axiom-kit/storage/kv"
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/ledger/utils"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+const (
+	forkRequestTimeout = 10 * time.Second
+	forkRequestRetries = 3
+	forkRetryInterval  = time.Second
+)
+
+// forkState lazily reads the accounts and storage missing locally from the forked chain at the pinned block,
+// the fetched state is cached in the state storage, so each of them is fetched only once.
+//
+// The account destructed and the storage deleted locally must not be read from the forked chain again,
+// so they are marked with the height of the block deleting them, the marks above the height of the state
+// being read are ignored, and they are removed when the state is rolled back.
+type forkState struct {
+	logger      logrus.FieldLogger
+	backend     kv.Storage
+	client      *rpc.Client
+	blockNumber uint64
+}
+
+// newForkState returns nil if fork mode is disabled, the pinned block is persisted at the first start,
+// so the forked state keeps consistent after restarting.
+func newForkState(rep *repo.Repo, backend kv.Storage, logger logrus.FieldLogger) (*forkState, error) {
+	if rep.ConsensusConfig == nil || rep.Config.Consensus.Type != repo.ConsensusTypeSoloDev || rep.ConsensusConfig.SoloDev.ForkURL == "" {
+		return nil, nil
+	}
+	url := rep.ConsensusConfig.SoloDev.ForkURL
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("dial fork url %s failed: %w", url, err)
+	}
+	f := &forkState{
+		logger:  logger,
+		backend: backend,
+		client:  client,
+	}
+
+	blockNumber := rep.ConsensusConfig.SoloDev.ForkBlockNumber
+	if data := backend.Get([]byte(utils.ForkMetaKey)); data != nil {
+		pinned := utils.UnmarshalUint64(data)
+		if blockNumber != 0 && blockNumber != pinned {
+			return nil, fmt.Errorf("fork block number %d mismatches the pinned block %d, please remove the ledger to fork at another block", blockNumber, pinned)
+		}
+		blockNumber = pinned
+	} else {
+		if blockNumber == 0 {
+			var latest hexutil.Uint64
+			if err = f.call(&latest, "eth_blockNumber"); err != nil {
+				return nil, fmt.Errorf("get latest block number of fork url %s failed: %w", url, err)
+			}
+			blockNumber = uint64(latest)
+		}
+		backend.Put([]byte(utils.ForkMetaKey), utils.MarshalUint64(blockNumber))
+	}
+	f.blockNumber = blockNumber
+
+	logger.WithFields(logrus.Fields{
+		"url":    url,
+		"height": blockNumber,
+	}).Info("fork mode is enabled")
+	return f, nil
+}
+
+func (f *forkState) call(result any, method string, args ...any) error {
+	return f.retry(func(ctx context.Context) error {
+		return f.client.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (f *forkState) retry(fn func(ctx context.Context) error) error {
+	var err error
+	for i := 0; i < forkRequestRetries; i++ {
+		if i > 0 {
+			time.Sleep(forkRetryInterval)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), forkRequestTimeout)
+		err = fn(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		f.logger.Warnf("[Fork] request failed, retry: %d, err: %v", i, err)
+	}
+	return err
+}
+
+func (f *forkState) destructed(addr *types.Address, height uint64) bool {
+	data := f.backend.Get(utils.CompositeKey(utils.ForkDestructKey, addr.String()))
+	return data != nil && utils.UnmarshalUint64(data) <= height
+}
+
+func (f *forkState) storageDeleted(addr *types.Address, key []byte, height uint64) bool {
+	data := f.backend.Get(compositeForkStorageKey(utils.ForkStorageDeleteKey, addr, key))
+	return data != nil && utils.UnmarshalUint64(data) <= height
+}
+
+// account returns the account of the forked chain, or nil if it not exists.
+func (f *forkState) account(addr *types.Address, height uint64) (*types.InnerAccount, error) {
+	if f.destructed(addr, height) {
+		return nil, nil
+	}
+
+	cacheKey := utils.CompositeKey(utils.ForkAccountKey, addr.String())
+	if data := f.backend.Get(cacheKey); data != nil {
+		return unmarshalForkAccount(data), nil
+	}
+
+	var (
+		balance hexutil.Big
+		nonce   hexutil.Uint64
+		code    hexutil.Bytes
+		block   = hexutil.Uint64(f.blockNumber)
+		ethAddr = addr.ETHAddress()
+	)
+	batch := []rpc.BatchElem{
+		{Method: "eth_getBalance", Args: []any{ethAddr, block}, Result: &balance},
+		{Method: "eth_getTransactionCount", Args: []any{ethAddr, block}, Result: &nonce},
+		{Method: "eth_getCode", Args: []any{ethAddr, block}, Result: &code},
+	}
+	err := f.retry(func(ctx context.Context) error {
+		if err := f.client.BatchCallContext(ctx, batch); err != nil {
+			return err
+		}
+		for _, elem := range batch {
+			if elem.Error != nil {
+				return fmt.Errorf("%s: %w", elem.Method, elem.Error)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch account %s from fork failed: %w", addr, err)
+	}
+
+	account := &types.InnerAccount{
+		Nonce:   uint64(nonce),
+		Balance: (*big.Int)(&balance),
+	}
+	if len(code) > 0 {
+		account.CodeHash = crypto.Keccak256Hash(code).Bytes()
+		f.backend.Put(utils.CompositeCodeKey(addr, account.CodeHash), code)
+	}
+	// the empty account is also cached, which means the account not exists in the forked chain
+	data, err := account.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	f.backend.Put(cacheKey, data)
+	f.logger.Debugf("[Fork] fetch account, addr: %v, account: %v", addr, account)
+	return unmarshalForkAccount(data), nil
+}
+
+// storage returns the storage of the forked chain, or nil if it is empty or the account is not forked.
+func (f *forkState) storage(addr *types.Address, key []byte, height uint64) ([]byte, error) {
+	if f.destructed(addr, height) || f.storageDeleted(addr, key, height) {
+		return nil, nil
+	}
+	if unmarshalForkAccount(f.backend.Get(utils.CompositeKey(utils.ForkAccountKey, addr.String()))) == nil {
+		// the account is created locally
+		return nil, nil
+	}
+
+	cacheKey := compositeForkStorageKey(utils.ForkStorageKey, addr, key)
+	value := f.backend.Get(cacheKey)
+	if value == nil {
+		var ret hexutil.Bytes
+		if err := f.call(&ret, "eth_getStorageAt", addr.ETHAddress(), common.BytesToHash(key), hexutil.Uint64(f.blockNumber)); err != nil {
+			return nil, fmt.Errorf("fetch storage %s of account %s from fork failed: %w", hexutil.Encode(key), addr, err)
+		}
+		value = common.BytesToHash(ret).Bytes()
+		f.backend.Put(cacheKey, value)
+		f.logger.Debugf("[Fork] fetch storage, addr: %v, key: %v, state: %v", addr, &bytesLazyLogger{bytes: key}, &bytesLazyLogger{bytes: value})
+	}
+	if bytes.Equal(value, common.Hash{}.Bytes()) {
+		return nil, nil
+	}
+	return value, nil
+}
+
+func (f *forkState) markDestructed(batch kv.Batch, addr *types.Address, height uint64) {
+	batch.Put(utils.CompositeKey(utils.ForkDestructKey, addr.String()), utils.MarshalUint64(height))
+}
+
+func (f *forkState) markStorageDeleted(batch kv.Batch, addr *types.Address, key []byte, height uint64) {
+	batch.Put(compositeForkStorageKey(utils.ForkStorageDeleteKey, addr, key), utils.MarshalUint64(height))
+}
+
+// rollback removes the deletion marks above the height.
+func (f *forkState) rollback(height uint64) {
+	batch := f.backend.NewBatch()
+	for _, prefix := range []string{utils.ForkDestructKey, utils.ForkStorageDeleteKey} {
+		it := f.backend.Prefix([]byte(prefix))
+		for it.Next() {
+			if utils.UnmarshalUint64(it.Value()) > height {
+				batch.Delete(bytes.Clone(it.Key()))
+			}
+		}
+	}
+	batch.Commit()
+}
+
+func compositeForkStorageKey(prefix string, addr *types.Address, key []byte) []byte {
+	return append(append([]byte(prefix), addr.Bytes()...), key...)
+}
+
+// unmarshalForkAccount returns nil if the account not exists in the forked chain.
+func unmarshalForkAccount(data []byte) *types.InnerAccount {
+	if len(data) == 0 {
+		return nil
+	}
+	account := &types.InnerAccount{Balance: big.NewInt(0)}
+	if err := account.Unmarshal(data); err != nil {
+		panic(err)
+	}
+	if account.Nonce == 0 && account.Balance.Sign() == 0 && len(account.CodeHash) == 0 {
+		return nil
+	}
+	return account
+}
+
+// forkReader reads the forked state for a state ledger and its accounts. The state failed to fetch is
+// treated as missing and is not cached, the first error is kept until it is taken by ForkError, so that
+// the caller can discard the result built on the missing state.
+type forkReader struct {
+	*forkState
+	err error
+}
+
+func newForkReader(f *forkState) *forkReader {
+	if f == nil {
+		return nil
+	}
+	return &forkReader{forkState: f}
+}
+
+// newView returns the reader for the view of the state ledger, which keeps its own error.
+func (r *forkReader) newView() *forkReader {
+	if r == nil {
+		return nil
+	}
+	return newForkReader(r.forkState)
+}
+
+func (r *forkReader) setErr(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// ForkError returns and clears the first error of fetching the state from the forked chain.
+func (l *StateLedgerImpl) ForkError() error {
+	if l.fork == nil {
+		return nil
+	}
+	err := l.fork.err
+	l.fork.err = nil
+	return err
+}
+
+// loadForkAccount loads the account missing locally from the forked chain.
+func (l *StateLedgerImpl) loadForkAccount(account *SimpleAccount) IAccount {
+	if l.fork == nil {
+		return nil
+	}
+	innerAccount, err := l.fork.account(account.Addr, l.blockHeight)
+	if err != nil {
+		l.logger.Errorf("[GetAccount] get from fork failed, addr: %v, err: %v", account.Addr, err)
+		l.fork.setErr(err)
+		return nil
+	}
+	if innerAccount == nil {
+		return nil
+	}
+	account.originAccount = innerAccount
+	if len(innerAccount.CodeHash) > 0 {
+		code := l.backend.Get(utils.CompositeCodeKey(account.Addr, innerAccount.CodeHash))
+		account.originCode = code
+		account.dirtyCode = code
+	}
+	l.accounts[account.Addr.String()] = account
+	l.logger.Debugf("[GetAccount] get from fork, addr: %v, account: %v", account.Addr, account)
+	return account
+}
+
+// loadForkState loads the storage missing locally from the forked chain,
+// it returns false if the fetch failed, then the storage must not be cached.
+func (o *SimpleAccount) loadForkState(key []byte) ([]byte, bool) {
+	if o.fork == nil {
+		return nil, true
+	}
+	value, err := o.fork.storage(o.Addr, key, o.blockHeight)
+	if err != nil {
+		o.logger.Errorf("[GetState] get from fork failed, addr: %v, key: %v, err: %v", o.Addr, &bytesLazyLogger{bytes: key}, err)
+		o.fork.setErr(err)
+		return nil, false
+	}
+	return value, true
+}
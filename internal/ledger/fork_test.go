@@ -0,0 +1,245 @@
+package ledger
+
+import (
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethhexutil "github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-kit/storage/kv"
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/pkg/loggers"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+type forkTestAccount struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash]common.Hash
+}
+
+// forkTestService is the stand-in of the eth namespace of the forked chain
+type forkTestService struct {
+	latest       uint64
+	accounts     map[common.Address]*forkTestAccount
+	accountCalls atomic.Int64
+	storageCalls atomic.Int64
+	blocks       []uint64
+	unavailable  atomic.Bool
+}
+
+func (s *forkTestService) BlockNumber() ethhexutil.Uint64 {
+	return ethhexutil.Uint64(s.latest)
+}
+
+func (s *forkTestService) GetBalance(addr common.Address, block ethhexutil.Uint64) (*ethhexutil.Big, error) {
+	s.accountCalls.Add(1)
+	if s.unavailable.Load() {
+		return nil, errors.New("unavailable")
+	}
+	s.blocks = append(s.blocks, uint64(block))
+	if acc, ok := s.accounts[addr]; ok {
+		return (*ethhexutil.Big)(acc.balance), nil
+	}
+	return (*ethhexutil.Big)(big.NewInt(0)), nil
+}
+
+func (s *forkTestService) GetTransactionCount(addr common.Address, block ethhexutil.Uint64) ethhexutil.Uint64 {
+	if acc, ok := s.accounts[addr]; ok {
+		return ethhexutil.Uint64(acc.nonce)
+	}
+	return 0
+}
+
+func (s *forkTestService) GetCode(addr common.Address, block ethhexutil.Uint64) ethhexutil.Bytes {
+	if acc, ok := s.accounts[addr]; ok {
+		return acc.code
+	}
+	return nil
+}
+
+func (s *forkTestService) GetStorageAt(addr common.Address, key common.Hash, block ethhexutil.Uint64) (ethhexutil.Bytes, error) {
+	s.storageCalls.Add(1)
+	if s.unavailable.Load() {
+		return nil, errors.New("unavailable")
+	}
+	if acc, ok := s.accounts[addr]; ok {
+		return acc.storage[key].Bytes(), nil
+	}
+	return common.Hash{}.Bytes(), nil
+}
+
+func newForkTestServer(t *testing.T, service *forkTestService) string {
+	server := rpc.NewServer()
+	require.Nil(t, server.RegisterName("eth", service))
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func newForkTestRepo(t *testing.T, url string) *repo.Repo {
+	rep := createMockRepo(t)
+	rep.Config.Consensus.Type = repo.ConsensusTypeSoloDev
+	rep.ConsensusConfig.SoloDev.ForkURL = url
+	return rep
+}
+
+func TestStateLedger_Fork(t *testing.T) {
+	forked := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	slot1 := common.HexToHash("0x01")
+	slot2 := common.HexToHash("0x02")
+	service := &forkTestService{
+		latest: 10,
+		accounts: map[common.Address]*forkTestAccount{
+			forked: {
+				balance: big.NewInt(100),
+				nonce:   5,
+				code:    []byte{0x60, 0x00},
+				storage: map[common.Hash]common.Hash{
+					slot1: common.HexToHash("0x2a"),
+					slot2: common.HexToHash("0x07"),
+				},
+			},
+		},
+	}
+	lg, err := NewMemory(newForkTestRepo(t, newForkTestServer(t, service)))
+	require.Nil(t, err)
+	sl := lg.StateLedger.(*StateLedgerImpl)
+
+	forkedAddr := types.NewAddress(forked.Bytes())
+	localAddr := types.NewAddressByStr("0x2000000000000000000000000000000000000002")
+
+	sl.PrepareBlock(nil, 1)
+	assert.Equal(t, int64(100), sl.GetBalance(forkedAddr).Int64())
+	assert.Equal(t, uint64(5), sl.GetNonce(forkedAddr))
+	assert.Equal(t, []byte{0x60, 0x00}, sl.GetCode(forkedAddr))
+	exist, value := sl.GetState(forkedAddr, slot1.Bytes())
+	assert.True(t, exist)
+	assert.Equal(t, common.HexToHash("0x2a").Bytes(), value)
+	assert.Equal(t, int64(0), sl.GetBalance(localAddr).Int64())
+	exist, _ = sl.GetState(localAddr, slot1.Bytes())
+	assert.False(t, exist)
+
+	// the state is read at the pinned block, and the account created locally never reads the remote storage
+	assert.Equal(t, []uint64{10, 10}, service.blocks)
+	assert.Equal(t, int64(2), service.accountCalls.Load())
+	assert.Equal(t, int64(1), service.storageCalls.Load())
+
+	sl.SetBalance(forkedAddr, big.NewInt(200))
+	sl.SetState(forkedAddr, slot2.Bytes(), nil)
+	sl.Finalise()
+	stateRoot, err := sl.Commit()
+	require.Nil(t, err)
+
+	// the fetched state is cached, the state deleted locally is not read from the remote again
+	sl.PrepareBlock(stateRoot, 2)
+	assert.Equal(t, int64(200), sl.GetBalance(forkedAddr).Int64())
+	exist, _ = sl.GetState(forkedAddr, slot2.Bytes())
+	assert.False(t, exist)
+	view, err := sl.NewView(&types.BlockHeader{Number: 1, StateRoot: stateRoot}, false)
+	require.Nil(t, err)
+	assert.Equal(t, int64(200), view.GetBalance(forkedAddr).Int64())
+	exist, value = view.GetState(forkedAddr, slot1.Bytes())
+	assert.True(t, exist)
+	assert.Equal(t, common.HexToHash("0x2a").Bytes(), value)
+	assert.Equal(t, int64(2), service.accountCalls.Load())
+	assert.Equal(t, int64(2), service.storageCalls.Load())
+
+	// the deletion is discarded by rolling back
+	require.Nil(t, sl.RollbackState(0, nil))
+	sl.PrepareBlock(nil, 1)
+	exist, value = sl.GetState(forkedAddr, slot2.Bytes())
+	assert.True(t, exist)
+	assert.Equal(t, common.HexToHash("0x07").Bytes(), value)
+}
+
+func TestStateLedger_ForkUnavailable(t *testing.T) {
+	forked := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	slot := common.HexToHash("0x01")
+	service := &forkTestService{
+		latest: 10,
+		accounts: map[common.Address]*forkTestAccount{
+			forked: {
+				balance: big.NewInt(100),
+				storage: map[common.Hash]common.Hash{
+					slot: common.HexToHash("0x2a"),
+				},
+			},
+		},
+	}
+	lg, err := NewMemory(newForkTestRepo(t, newForkTestServer(t, service)))
+	require.Nil(t, err)
+	sl := lg.StateLedger.(*StateLedgerImpl)
+	forkedAddr := types.NewAddress(forked.Bytes())
+	sl.PrepareBlock(nil, 1)
+
+	// the account failed to fetch is read as missing, and the error is kept until it is taken
+	service.unavailable.Store(true)
+	snapshot := sl.Snapshot()
+	assert.Equal(t, int64(0), sl.GetBalance(forkedAddr).Int64())
+	assert.Equal(t, int64(forkRequestRetries), service.accountCalls.Load())
+	assert.ErrorContains(t, sl.ForkError(), "unavailable")
+	assert.Nil(t, sl.ForkError())
+	sl.RevertToSnapshot(snapshot)
+
+	// the failed fetch is not cached
+	service.unavailable.Store(false)
+	assert.Equal(t, int64(100), sl.GetBalance(forkedAddr).Int64())
+	assert.Nil(t, sl.ForkError())
+
+	service.unavailable.Store(true)
+	exist, _ := sl.GetState(forkedAddr, slot.Bytes())
+	assert.False(t, exist)
+	assert.NotNil(t, sl.ForkError())
+	service.unavailable.Store(false)
+	exist, value := sl.GetState(forkedAddr, slot.Bytes())
+	assert.True(t, exist)
+	assert.Equal(t, common.HexToHash("0x2a").Bytes(), value)
+	assert.Nil(t, sl.ForkError())
+
+	// the view keeps its own error
+	view, err := sl.NewView(&types.BlockHeader{Number: 0}, false)
+	require.Nil(t, err)
+	service.unavailable.Store(true)
+	view.GetBalance(types.NewAddressByStr("0x3000000000000000000000000000000000000003"))
+	assert.Nil(t, sl.ForkError())
+	assert.NotNil(t, view.ForkError())
+}
+
+func TestNewForkState(t *testing.T) {
+	service := &forkTestService{latest: 10}
+	url := newForkTestServer(t, service)
+	backend := kv.NewMemory()
+	logger := loggers.Logger(loggers.Storage)
+
+	rep := createMockRepo(t)
+	f, err := newForkState(rep, backend, logger)
+	require.Nil(t, err)
+	assert.Nil(t, f)
+
+	rep = newForkTestRepo(t, url)
+	f, err = newForkState(rep, backend, logger)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(10), f.blockNumber)
+
+	// the pinned block is kept after the remote grows
+	service.latest = 20
+	f, err = newForkState(rep, backend, logger)
+	require.Nil(t, err)
+	assert.Equal(t, uint64(10), f.blockNumber)
+
+	rep.ConsensusConfig.SoloDev.ForkBlockNumber = 15
+	_, err = newForkState(rep, backend, logger)
+	assert.NotNil(t, err)
+}
@@ -46,6 +46,15 @@ type ChainLedger interface {
 	// GetReceipt get the transaction receipt
 	GetReceipt(hash *types.Hash) (*types.Receipt, error)
 
+	// GetTransactionsByAddress get the transactions sent from or to the address in the block range
+	GetTransactionsByAddress(addr *types.Address, begin, end uint64, offset, limit uint64) ([]*AddressTx, error)
+
+	// GetBundleTx get the bundle info of the tx batched in a bundle
+	GetBundleTx(hash *types.Hash) (*BundleTx, error)
+
+	// PersistBundleTxs persist the bundle info of the txs keyed by tx hash
+	PersistBundleTxs(bundleTxs map[string]*BundleTx) error
+
 	// PersistExecutionResult persist the execution result
 	PersistExecutionResult(block *types.Block, receipts []*types.Receipt) error
 
@@ -90,6 +99,9 @@ type StateLedger interface {
 
 	IterateTrie(snapshotMeta *SnapshotMeta, kv kv.Storage, errC chan error)
 
+	// ExportTrie emits all the kv entries of the state trie at target block.
+	ExportTrie(blockHeader *types.BlockHeader, fn func(key, value []byte) error) error
+
 	GetTrieSnapshotMeta() (*SnapshotMeta, error)
 
 	VerifyTrie(blockHeader *types.BlockHeader) (bool, error)
@@ -103,6 +115,12 @@ type StateLedger interface {
 	CurrentBlockHeight() uint64
 
 	GetStateDelta(blockNumber uint64) *types.StateDelta
+
+	GetStateJournal(blockNumber uint64) (*types.SnapshotJournal, error)
+
+	// ForkError returns and clears the first error of fetching the state from the forked chain,
+	// the state failed to fetch is read as missing, so the result built on it must be discarded.
+	ForkError() error
 }
 
 // StateAccessor manipulates the state data
@@ -8,6 +8,7 @@ import (
 	"github.com/axiomesh/axiom-kit/storage/blockfile"
 	"github.com/axiomesh/axiom-kit/storage/kv"
 	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/storagemgr"
 	"github.com/axiomesh/axiom-ledger/pkg/repo"
 )
 
@@ -21,6 +22,8 @@ type BlockData struct {
 	Block      *types.Block
 	Receipts   []*types.Receipt
 	TxHashList []*types.Hash
+	// BundleTxs is the bundle info of the bundle txs in the block keyed by tx hash
+	BundleTxs map[string]*BundleTx
 }
 
 type SnapInfo struct {
@@ -70,12 +73,52 @@ func NewLedger(rep *repo.Repo) (*Ledger, error) {
 	return NewLedgerWithStores(rep, nil, nil, nil, nil)
 }
 
+// NewReplayLedger opens the ledger in repo at the state of target block for re-executing the blocks after it,
+// all the state writes are kept in memory, so the persisted ledger is never modified.
+func NewReplayLedger(rep *repo.Repo, height uint64) (*Ledger, error) {
+	chainLedger, err := NewChainLedger(rep, "")
+	if err != nil {
+		return nil, fmt.Errorf("init chain ledger failed: %w", err)
+	}
+	stateStorage, err := storagemgr.OpenWithMetrics(repo.GetStoragePath(rep.RepoRoot, storagemgr.Ledger), storagemgr.Ledger)
+	if err != nil {
+		return nil, fmt.Errorf("create stateDB: %w", err)
+	}
+	trieIndexerKv, err := storagemgr.OpenWithMetrics(repo.GetStoragePath(rep.RepoRoot, storagemgr.TrieIndexer), storagemgr.TrieIndexer)
+	if err != nil {
+		return nil, err
+	}
+	// the snapshot only holds the latest state, so the state is read from trie
+	stateLedger, err := newStateLedgerWithTrieIndexer(rep, storagemgr.NewOverlayStorage(stateStorage), nil, storagemgr.NewOverlayStorage(trieIndexerKv))
+	if err != nil {
+		return nil, fmt.Errorf("init state ledger failed: %w", err)
+	}
+
+	blockHeader, err := chainLedger.GetBlockHeader(height)
+	if err != nil {
+		return nil, fmt.Errorf("get block %d failed: %w", height, err)
+	}
+	if err := stateLedger.RollbackState(height, blockHeader.StateRoot); err != nil {
+		return nil, fmt.Errorf("load state at height %d failed: %w", height, err)
+	}
+	return &Ledger{
+		ChainLedger: chainLedger,
+		StateLedger: stateLedger,
+	}, nil
+}
+
 // PersistBlockData persists block data
 func (l *Ledger) PersistBlockData(blockData *BlockData) {
 	current := time.Now()
 	block := blockData.Block
 	receipts := blockData.Receipts
 
+	// persist the bundle info before the block, so that the receipts are never queried without it
+	if len(blockData.BundleTxs) > 0 {
+		if err := l.ChainLedger.PersistBundleTxs(blockData.BundleTxs); err != nil {
+			panic(err)
+		}
+	}
 	if err := l.ChainLedger.PersistExecutionResult(block, receipts); err != nil {
 		panic(err)
 	}
@@ -33,6 +33,7 @@ import (
 	"github.com/axiomesh/axiom-kit/storage/kv/leveldb"
 	"github.com/axiomesh/axiom-kit/storage/kv/pebble"
 	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/components"
 	"github.com/axiomesh/axiom-ledger/internal/ledger/prune"
 	"github.com/axiomesh/axiom-ledger/internal/ledger/snapshot"
 	"github.com/axiomesh/axiom-ledger/internal/ledger/utils"
@@ -824,6 +825,209 @@ func TestGetBlockTxListByNumber(t *testing.T) {
 	}
 }
 
+func TestChainLedger_GetTransactionsByAddress(t *testing.T) {
+	rep := createMockRepo(t)
+	rep.Config.Ledger.EnableAddressTxIndexer = true
+	lg, err := NewLedger(rep)
+	require.Nil(t, err)
+	chainLedger := lg.ChainLedger
+
+	s1, err := types.GenerateSigner()
+	require.Nil(t, err)
+	s2, err := types.GenerateSigner()
+	require.Nil(t, err)
+	contract := types.NewAddress(LeftPadBytes([]byte{100}, 20))
+
+	_, err = chainLedger.GetTransactionsByAddress(s1.Addr, 0, 10, 0, 10)
+	require.Nil(t, err)
+
+	var hashes []*types.Hash
+	for i := uint64(0); i < 4; i++ {
+		transfer, err := types.GenerateTransactionWithSigner(i*3, s2.Addr, big.NewInt(1), nil, s1)
+		require.Nil(t, err)
+		create, err := types.GenerateTransactionWithSigner(i*3+1, nil, big.NewInt(0), []byte{1}, s1)
+		require.Nil(t, err)
+		self, err := types.GenerateTransactionWithSigner(i*3+2, s1.Addr, big.NewInt(1), nil, s1)
+		require.Nil(t, err)
+		block := &types.Block{
+			Header:       &types.BlockHeader{Number: i},
+			Transactions: []*types.Transaction{transfer, create, self},
+		}
+		receipts := []*types.Receipt{
+			{TxHash: transfer.GetHash(), EffectiveGasPrice: big.NewInt(0)},
+			{TxHash: create.GetHash(), EffectiveGasPrice: big.NewInt(0), ContractAddress: contract},
+			{TxHash: self.GetHash(), EffectiveGasPrice: big.NewInt(0)},
+		}
+		err = chainLedger.PersistExecutionResult(block, receipts)
+		require.Nil(t, err)
+		hashes = append(hashes, transfer.GetHash(), create.GetHash(), self.GetHash())
+	}
+
+	txs, err := chainLedger.GetTransactionsByAddress(s1.Addr, 0, 3, 0, 100)
+	require.Nil(t, err)
+	require.Len(t, txs, 12)
+	for i, tx := range txs {
+		assert.Equal(t, hashes[i].String(), tx.TxHash.String())
+		assert.EqualValues(t, i/3, tx.BlockNumber)
+		assert.EqualValues(t, i%3, tx.TxIndex)
+	}
+	assert.Equal(t, AddressTxDirectionFrom, txs[0].Direction)
+	assert.Equal(t, AddressTxDirectionFrom, txs[1].Direction)
+	assert.Equal(t, AddressTxDirectionSelf, txs[2].Direction)
+
+	txs, err = chainLedger.GetTransactionsByAddress(s2.Addr, 1, 2, 0, 100)
+	require.Nil(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, AddressTxDirectionTo, txs[0].Direction)
+	assert.EqualValues(t, 1, txs[0].BlockNumber)
+	assert.EqualValues(t, 2, txs[1].BlockNumber)
+
+	txs, err = chainLedger.GetTransactionsByAddress(contract, 0, 3, 1, 2)
+	require.Nil(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, AddressTxDirectionTo, txs[0].Direction)
+	assert.Equal(t, hashes[4].String(), txs[0].TxHash.String())
+	assert.Equal(t, hashes[7].String(), txs[1].TxHash.String())
+
+	err = chainLedger.RollbackBlockChain(1)
+	require.Nil(t, err)
+	txs, err = chainLedger.GetTransactionsByAddress(s1.Addr, 0, 3, 0, 100)
+	require.Nil(t, err)
+	assert.Len(t, txs, 6)
+	txs, err = chainLedger.GetTransactionsByAddress(contract, 0, 3, 0, 100)
+	require.Nil(t, err)
+	assert.Len(t, txs, 2)
+
+	rep.Config.Ledger.EnableAddressTxIndexer = false
+	_, err = chainLedger.GetTransactionsByAddress(s1.Addr, 0, 3, 0, 100)
+	assert.ErrorIs(t, err, ErrAddressTxIndexerDisabled)
+}
+
+func TestChainLedger_BundleTx(t *testing.T) {
+	rep := createMockRepo(t)
+	lg, err := NewLedger(rep)
+	require.Nil(t, err)
+
+	s, err := types.GenerateSigner()
+	require.Nil(t, err)
+	var txs []*types.Transaction
+	for i := uint64(0); i < 3; i++ {
+		tx, err := types.GenerateTransactionWithSigner(i, s.Addr, big.NewInt(1), nil, s)
+		require.Nil(t, err)
+		lg.PersistBlockData(&BlockData{
+			Block: &types.Block{
+				Header:       &types.BlockHeader{Number: i},
+				Transactions: []*types.Transaction{tx},
+			},
+			Receipts:  []*types.Receipt{{TxHash: tx.GetHash(), EffectiveGasPrice: big.NewInt(0)}},
+			BundleTxs: map[string]*BundleTx{tx.GetHash().String(): {BundleID: "bundle", Reverted: i == 2}},
+		})
+		txs = append(txs, tx)
+	}
+
+	bundleTx, err := lg.ChainLedger.GetBundleTx(txs[2].GetHash())
+	require.Nil(t, err)
+	assert.Equal(t, &BundleTx{BundleID: "bundle", Reverted: true}, bundleTx)
+	_, err = lg.ChainLedger.GetBundleTx(types.NewHashByStr("0x1"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	err = lg.ChainLedger.RollbackBlockChain(1)
+	require.Nil(t, err)
+	bundleTx, err = lg.ChainLedger.GetBundleTx(txs[1].GetHash())
+	require.Nil(t, err)
+	assert.Equal(t, &BundleTx{BundleID: "bundle"}, bundleTx)
+	_, err = lg.ChainLedger.GetBundleTx(txs[2].GetHash())
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func newImpersonatedTx(t *testing.T, from common.Address, nonce uint64) *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	inner := &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1356),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(0),
+		GasFeeCap: big.NewInt(0),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	}
+	inner.V, inner.R, inner.S = components.ImpersonationSignature(from)
+	tx := &types.Transaction{}
+	tx.FromCallArgs(types.CallArgs{From: &from})
+	tx.Inner = inner
+	require.Equal(t, from, tx.GetFrom().ETHAddress())
+	return tx
+}
+
+func TestChainLedger_ImpersonatedTx(t *testing.T) {
+	from := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	// the sender can not be recovered from the signature of the decoded tx
+	data, err := newImpersonatedTx(t, from, 0).Marshal()
+	require.Nil(t, err)
+	decoded := &types.Transaction{}
+	require.Nil(t, decoded.Unmarshal(data))
+	assert.NotEqual(t, types.NewAddress(from.Bytes()), decoded.GetFrom())
+	decoded = &types.Transaction{}
+	require.Nil(t, decoded.Unmarshal(data))
+	assert.True(t, components.RestoreImpersonatedSender(decoded))
+	assert.Equal(t, from, decoded.GetFrom().ETHAddress())
+	assert.Equal(t, newImpersonatedTx(t, from, 0).GetHash(), decoded.GetHash())
+
+	signer, err := types.GenerateSigner()
+	require.Nil(t, err)
+	signedTx, err := types.GenerateTransactionWithSigner(0, types.NewAddressByStr("0x00000000000000000000000000000000000000bb"), big.NewInt(1), nil, signer)
+	require.Nil(t, err)
+	assert.False(t, components.RestoreImpersonatedSender(signedTx))
+	assert.Equal(t, signer.Addr, signedTx.GetFrom())
+
+	persistAndReload := func(t *testing.T, consensusType string) (*ChainLedgerImpl, *types.Transaction) {
+		rep := createMockRepo(t)
+		rep.Config.Consensus.Type = consensusType
+		lg, err := NewLedger(rep)
+		require.Nil(t, err)
+		chainLedger := lg.ChainLedger.(*ChainLedgerImpl)
+
+		tx := newImpersonatedTx(t, from, 0)
+		signedTx, err := types.GenerateTransactionWithSigner(0, types.NewAddressByStr("0x00000000000000000000000000000000000000bb"), big.NewInt(1), nil, signer)
+		require.Nil(t, err)
+		block := &types.Block{
+			Header:       &types.BlockHeader{Number: 0},
+			Transactions: []*types.Transaction{tx, signedTx},
+		}
+		receipts := []*types.Receipt{
+			{TxHash: tx.GetHash(), EffectiveGasPrice: big.NewInt(0)},
+			{TxHash: signedTx.GetHash(), EffectiveGasPrice: big.NewInt(0)},
+		}
+		require.Nil(t, chainLedger.PersistExecutionResult(block, receipts))
+		// read the txs from the blockfile instead of the cache
+		chainLedger.blockTxsCache.Purge()
+		return chainLedger, tx
+	}
+
+	t.Run("solo_dev", func(t *testing.T) {
+		chainLedger, tx := persistAndReload(t, repo.ConsensusTypeSoloDev)
+
+		reloaded, err := chainLedger.GetTransaction(tx.GetHash())
+		require.Nil(t, err)
+		assert.Equal(t, from, reloaded.GetFrom().ETHAddress())
+
+		block, err := chainLedger.GetBlock(0)
+		require.Nil(t, err)
+		require.Len(t, block.Transactions, 2)
+		assert.Equal(t, from, block.Transactions[0].GetFrom().ETHAddress())
+		assert.Equal(t, signer.Addr, block.Transactions[1].GetFrom())
+	})
+
+	t.Run("other consensus", func(t *testing.T) {
+		chainLedger, tx := persistAndReload(t, repo.ConsensusTypeRbft)
+
+		reloaded, err := chainLedger.GetTransaction(tx.GetHash())
+		require.Nil(t, err)
+		assert.NotEqual(t, types.NewAddress(from.Bytes()), reloaded.GetFrom())
+	})
+}
+
 func TestGetTransaction(t *testing.T) {
 	testcase := map[string]struct {
 		kvType string
@@ -2590,6 +2794,85 @@ func TestStateLedger_IterateStorageTrie(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, block5.Header.StateRoot.String(), meta.BlockHeader.StateRoot.String())
 	})
+
+	t.Run("test resume iterating trie of block 5", func(t *testing.T) {
+		block5 := &types.Block{
+			Header: &types.BlockHeader{
+				Number:    5,
+				StateRoot: stateRoot5,
+			},
+		}
+		s5 := kv.NewMemory()
+
+		// mock an iteration interrupted after the account trie and the first storage trie
+		batch := s5.NewBatch()
+		put := func(key, value []byte) error {
+			batch.Put(key, value)
+			return nil
+		}
+		var storageRoots []common.Hash
+		err := sl.exportTrie(stateRoot5.ETHHash(), true, put, func(storageRoot common.Hash) error {
+			batch.Put(utils.CompositeKey(utils.TrieIterStorageRootKey, len(storageRoots)), storageRoot.Bytes())
+			storageRoots = append(storageRoots, storageRoot)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, len(storageRoots))
+		err = sl.exportTrie(storageRoots[0], false, put, nil)
+		assert.Nil(t, err)
+		err = putTrieIterCursor(batch, &trieIterCursor{BlockNumber: 5, AccountTrieDone: true, StorageTries: uint64(len(storageRoots)), Next: 1})
+		assert.Nil(t, err)
+		batch.Commit()
+
+		errC5 := make(chan error)
+		go sl.IterateTrie(&SnapshotMeta{BlockHeader: block5.Header, EpochInfo: &types.EpochInfo{
+			Epoch: 1,
+		}, Nodes: &consensus.QuorumValidators{Validators: []*consensus.QuorumValidator{{Id: 1, PeerId: "P2PNodeID-1"}}}}, s5, errC5)
+		err, ok := <-errC5
+		assert.True(t, ok)
+		assert.Nil(t, err)
+		assert.False(t, s5.Has([]byte(utils.TrieIterCursorKey)))
+		assert.False(t, s5.Has(utils.CompositeKey(utils.TrieIterStorageRootKey, 0)))
+
+		sl5, _ := sl.NewView(block5.Header, false)
+		sl5.(*StateLedgerImpl).backend = s5
+		sl5.(*StateLedgerImpl).refreshAccountTrie(block5.Header.StateRoot)
+		verify, err := sl5.VerifyTrie(block5.Header)
+		assert.True(t, verify)
+		assert.Nil(t, err)
+		exist, val := sl5.GetState(account3, []byte("k2"))
+		assert.True(t, exist)
+		assert.Equal(t, []byte("v22"), val)
+	})
+
+	t.Run("test ignore cursor of another block", func(t *testing.T) {
+		block3 := &types.Block{
+			Header: &types.BlockHeader{
+				Number:    3,
+				StateRoot: stateRoot3,
+			},
+		}
+		s3 := kv.NewMemory()
+		batch := s3.NewBatch()
+		err := putTrieIterCursor(batch, &trieIterCursor{BlockNumber: 5, AccountTrieDone: true})
+		assert.Nil(t, err)
+		batch.Commit()
+
+		errC3 := make(chan error)
+		go sl.IterateTrie(&SnapshotMeta{BlockHeader: block3.Header, EpochInfo: &types.EpochInfo{
+			Epoch: 1,
+		}, Nodes: &consensus.QuorumValidators{Validators: []*consensus.QuorumValidator{{Id: 1, PeerId: "P2PNodeID-1"}}}}, s3, errC3)
+		err, ok := <-errC3
+		assert.True(t, ok)
+		assert.Nil(t, err)
+
+		sl3, _ := sl.NewView(block3.Header, false)
+		sl3.(*StateLedgerImpl).backend = s3
+		sl3.(*StateLedgerImpl).refreshAccountTrie(block3.Header.StateRoot)
+		verify, err := sl3.VerifyTrie(block3.Header)
+		assert.True(t, verify)
+		assert.Nil(t, err)
+	})
 }
 
 func TestStateLedger_GetTrieSnapshotMeta(t *testing.T) {
@@ -3155,6 +3438,108 @@ func TestLedger_NewView(t *testing.T) {
 	})
 }
 
+func TestStateLedger_ArchiveMode(t *testing.T) {
+	newArchiveRepo := func(enableArchive bool) *repo.Repo {
+		rep := createMockRepo(t)
+		rep.Config.Ledger.EnablePrune = true
+		rep.Config.Ledger.EnableArchive = enableArchive
+		return rep
+	}
+	archiveKey := utils.CompositeKey(utils.PruneJournalKey, utils.ArchiveHeightStr)
+	minHeightKey := utils.CompositeKey(utils.PruneJournalKey, utils.MinHeightStr)
+	maxHeightKey := utils.CompositeKey(utils.PruneJournalKey, utils.MaxHeightStr)
+
+	t.Run("enable archive on unpruned state", func(t *testing.T) {
+		stateStorage := kv.NewMemory()
+		stateStorage.Put(minHeightKey, utils.MarshalUint64(0))
+		stateStorage.Put(maxHeightKey, utils.MarshalUint64(300))
+		sl, err := newStateLedger(newArchiveRepo(true), stateStorage, nil)
+		require.Nil(t, err)
+		assert.Equal(t, utils.MarshalUint64(0), stateStorage.Get(archiveKey))
+
+		// pruner moves the journal range forward, but the state is still kept by archive node
+		stateStorage.Put(minHeightKey, utils.MarshalUint64(44))
+		_, err = sl.NewView(&types.BlockHeader{Number: 10, StateRoot: &types.Hash{}}, false)
+		assert.Nil(t, err)
+		_, err = sl.NewView(&types.BlockHeader{Number: 301, StateRoot: &types.Hash{}}, false)
+		var historyErr *HistoryUnavailableError
+		require.ErrorAs(t, err, &historyErr)
+		assert.EqualValues(t, 0, historyErr.Min)
+		assert.EqualValues(t, 300, historyErr.Max)
+
+		// restart with archive mode
+		_, err = newStateLedger(newArchiveRepo(true), stateStorage, nil)
+		assert.Nil(t, err)
+
+		// disable archive mode
+		_, err = newStateLedger(newArchiveRepo(false), stateStorage, nil)
+		assert.Nil(t, err)
+		assert.False(t, stateStorage.Has(archiveKey))
+	})
+
+	t.Run("enable archive on pruned state", func(t *testing.T) {
+		stateStorage := kv.NewMemory()
+		stateStorage.Put(minHeightKey, utils.MarshalUint64(44))
+		stateStorage.Put(maxHeightKey, utils.MarshalUint64(300))
+		_, err := newStateLedger(newArchiveRepo(true), stateStorage, nil)
+		assert.ErrorContains(t, err, "cannot enable archive mode")
+
+		sl, err := newStateLedger(newArchiveRepo(false), stateStorage, nil)
+		require.Nil(t, err)
+		_, err = sl.NewView(&types.BlockHeader{Number: 10, StateRoot: &types.Hash{}}, false)
+		var historyErr *HistoryUnavailableError
+		require.ErrorAs(t, err, &historyErr)
+		assert.EqualValues(t, 44, historyErr.Min)
+	})
+}
+
+func TestStateLedger_GetStateJournal(t *testing.T) {
+	lg, _ := initLedger(t, "", "pebble")
+	sl := lg.StateLedger.(*StateLedgerImpl)
+	addr0 := types.NewAddress(LeftPadBytes([]byte{100}, 20))
+	addr1 := types.NewAddress(LeftPadBytes([]byte{101}, 20))
+
+	sl.PrepareBlock(nil, 0)
+	sl.SetBalance(addr0, big.NewInt(1))
+	sl.SetState(addr0, []byte("a"), []byte("1"))
+	sl.Finalise()
+	stateRoot1, err := sl.Commit()
+	require.Nil(t, err)
+	lg.PersistBlockData(genBlockData(0, stateRoot1))
+
+	sl.PrepareBlock(stateRoot1, 1)
+	sl.SetBalance(addr1, big.NewInt(2))
+	sl.SetState(addr0, []byte("a"), []byte("2"))
+	sl.Finalise()
+	stateRoot2, err := sl.Commit()
+	require.Nil(t, err)
+	lg.PersistBlockData(genBlockData(1, stateRoot2))
+
+	journal, err := sl.GetStateJournal(1)
+	require.Nil(t, err)
+	require.Len(t, journal.Journals, 2)
+	for _, entry := range journal.Journals {
+		switch entry.Address.String() {
+		case addr0.String():
+			assert.EqualValues(t, 1, entry.PrevAccount.Balance.Uint64())
+			assert.Equal(t, map[string][]byte{"a": []byte("1")}, entry.PrevStates)
+		case addr1.String():
+			assert.Nil(t, entry.PrevAccount)
+		default:
+			t.Fatalf("unexpected account %s", entry.Address)
+		}
+	}
+
+	_, err = sl.GetStateJournal(2)
+	var historyErr *HistoryUnavailableError
+	require.ErrorAs(t, err, &historyErr)
+	assert.EqualValues(t, 1, historyErr.Max)
+
+	sl.snapshot = nil
+	_, err = sl.GetStateJournal(1)
+	assert.ErrorIs(t, err, ErrSnapshotDisabled)
+}
+
 type mockAccountResult struct {
 	Address      common.Address      `json:"address"`
 	AccountProof []string            `json:"accountProof"`
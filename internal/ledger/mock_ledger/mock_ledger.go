@@ -391,6 +391,45 @@ func (c *ChainLedgerGetBlockTxListCall) DoAndReturn(f func(uint64) ([]*types.Tra
 	return c
 }
 
+// GetBundleTx mocks base method.
+func (m *MockChainLedger) GetBundleTx(hash *types.Hash) (*ledger.BundleTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundleTx", hash)
+	ret0, _ := ret[0].(*ledger.BundleTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundleTx indicates an expected call of GetBundleTx.
+func (mr *MockChainLedgerMockRecorder) GetBundleTx(hash any) *ChainLedgerGetBundleTxCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundleTx", reflect.TypeOf((*MockChainLedger)(nil).GetBundleTx), hash)
+	return &ChainLedgerGetBundleTxCall{Call: call}
+}
+
+// ChainLedgerGetBundleTxCall wrap *gomock.Call
+type ChainLedgerGetBundleTxCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ChainLedgerGetBundleTxCall) Return(arg0 *ledger.BundleTx, arg1 error) *ChainLedgerGetBundleTxCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ChainLedgerGetBundleTxCall) Do(f func(*types.Hash) (*ledger.BundleTx, error)) *ChainLedgerGetBundleTxCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ChainLedgerGetBundleTxCall) DoAndReturn(f func(*types.Hash) (*ledger.BundleTx, error)) *ChainLedgerGetBundleTxCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetChainMeta mocks base method.
 func (m *MockChainLedger) GetChainMeta() *types.ChainMeta {
 	m.ctrl.T.Helper()
@@ -585,6 +624,45 @@ func (c *ChainLedgerGetTransactionMetaCall) DoAndReturn(f func(*types.Hash) (*ty
 	return c
 }
 
+// GetTransactionsByAddress mocks base method.
+func (m *MockChainLedger) GetTransactionsByAddress(addr *types.Address, begin, end, offset, limit uint64) ([]*ledger.AddressTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByAddress", addr, begin, end, offset, limit)
+	ret0, _ := ret[0].([]*ledger.AddressTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByAddress indicates an expected call of GetTransactionsByAddress.
+func (mr *MockChainLedgerMockRecorder) GetTransactionsByAddress(addr, begin, end, offset, limit any) *ChainLedgerGetTransactionsByAddressCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByAddress", reflect.TypeOf((*MockChainLedger)(nil).GetTransactionsByAddress), addr, begin, end, offset, limit)
+	return &ChainLedgerGetTransactionsByAddressCall{Call: call}
+}
+
+// ChainLedgerGetTransactionsByAddressCall wrap *gomock.Call
+type ChainLedgerGetTransactionsByAddressCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ChainLedgerGetTransactionsByAddressCall) Return(arg0 []*ledger.AddressTx, arg1 error) *ChainLedgerGetTransactionsByAddressCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ChainLedgerGetTransactionsByAddressCall) Do(f func(*types.Address, uint64, uint64, uint64, uint64) ([]*ledger.AddressTx, error)) *ChainLedgerGetTransactionsByAddressCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ChainLedgerGetTransactionsByAddressCall) DoAndReturn(f func(*types.Address, uint64, uint64, uint64, uint64) ([]*ledger.AddressTx, error)) *ChainLedgerGetTransactionsByAddressCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // LoadChainMeta mocks base method.
 func (m *MockChainLedger) LoadChainMeta() (*types.ChainMeta, error) {
 	m.ctrl.T.Helper()
@@ -624,6 +702,44 @@ func (c *ChainLedgerLoadChainMetaCall) DoAndReturn(f func() (*types.ChainMeta, e
 	return c
 }
 
+// PersistBundleTxs mocks base method.
+func (m *MockChainLedger) PersistBundleTxs(bundleTxs map[string]*ledger.BundleTx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PersistBundleTxs", bundleTxs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PersistBundleTxs indicates an expected call of PersistBundleTxs.
+func (mr *MockChainLedgerMockRecorder) PersistBundleTxs(bundleTxs any) *ChainLedgerPersistBundleTxsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PersistBundleTxs", reflect.TypeOf((*MockChainLedger)(nil).PersistBundleTxs), bundleTxs)
+	return &ChainLedgerPersistBundleTxsCall{Call: call}
+}
+
+// ChainLedgerPersistBundleTxsCall wrap *gomock.Call
+type ChainLedgerPersistBundleTxsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ChainLedgerPersistBundleTxsCall) Return(arg0 error) *ChainLedgerPersistBundleTxsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ChainLedgerPersistBundleTxsCall) Do(f func(map[string]*ledger.BundleTx) error) *ChainLedgerPersistBundleTxsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ChainLedgerPersistBundleTxsCall) DoAndReturn(f func(map[string]*ledger.BundleTx) error) *ChainLedgerPersistBundleTxsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // PersistExecutionResult mocks base method.
 func (m *MockChainLedger) PersistExecutionResult(block *types.Block, receipts []*types.Receipt) error {
 	m.ctrl.T.Helper()
@@ -1274,6 +1390,44 @@ func (c *StateLedgerExistCall) DoAndReturn(f func(*types.Address) bool) *StateLe
 	return c
 }
 
+// ExportTrie mocks base method.
+func (m *MockStateLedger) ExportTrie(blockHeader *types.BlockHeader, fn func([]byte, []byte) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportTrie", blockHeader, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportTrie indicates an expected call of ExportTrie.
+func (mr *MockStateLedgerMockRecorder) ExportTrie(blockHeader, fn any) *StateLedgerExportTrieCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTrie", reflect.TypeOf((*MockStateLedger)(nil).ExportTrie), blockHeader, fn)
+	return &StateLedgerExportTrieCall{Call: call}
+}
+
+// StateLedgerExportTrieCall wrap *gomock.Call
+type StateLedgerExportTrieCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *StateLedgerExportTrieCall) Return(arg0 error) *StateLedgerExportTrieCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *StateLedgerExportTrieCall) Do(f func(*types.BlockHeader, func([]byte, []byte) error) error) *StateLedgerExportTrieCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *StateLedgerExportTrieCall) DoAndReturn(f func(*types.BlockHeader, func([]byte, []byte) error) error) *StateLedgerExportTrieCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Finalise mocks base method.
 func (m *MockStateLedger) Finalise() {
 	m.ctrl.T.Helper()
@@ -1310,6 +1464,44 @@ func (c *StateLedgerFinaliseCall) DoAndReturn(f func()) *StateLedgerFinaliseCall
 	return c
 }
 
+// ForkError mocks base method.
+func (m *MockStateLedger) ForkError() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForkError")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForkError indicates an expected call of ForkError.
+func (mr *MockStateLedgerMockRecorder) ForkError() *StateLedgerForkErrorCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForkError", reflect.TypeOf((*MockStateLedger)(nil).ForkError))
+	return &StateLedgerForkErrorCall{Call: call}
+}
+
+// StateLedgerForkErrorCall wrap *gomock.Call
+type StateLedgerForkErrorCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *StateLedgerForkErrorCall) Return(arg0 error) *StateLedgerForkErrorCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *StateLedgerForkErrorCall) Do(f func() error) *StateLedgerForkErrorCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *StateLedgerForkErrorCall) DoAndReturn(f func() error) *StateLedgerForkErrorCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GenerateSnapshot mocks base method.
 func (m *MockStateLedger) GenerateSnapshot(blockHeader *types.BlockHeader, errC chan error) {
 	m.ctrl.T.Helper()
@@ -1842,6 +2034,45 @@ func (c *StateLedgerGetStateDeltaCall) DoAndReturn(f func(uint64) *types.StateDe
 	return c
 }
 
+// GetStateJournal mocks base method.
+func (m *MockStateLedger) GetStateJournal(blockNumber uint64) (*types.SnapshotJournal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStateJournal", blockNumber)
+	ret0, _ := ret[0].(*types.SnapshotJournal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStateJournal indicates an expected call of GetStateJournal.
+func (mr *MockStateLedgerMockRecorder) GetStateJournal(blockNumber any) *StateLedgerGetStateJournalCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateJournal", reflect.TypeOf((*MockStateLedger)(nil).GetStateJournal), blockNumber)
+	return &StateLedgerGetStateJournalCall{Call: call}
+}
+
+// StateLedgerGetStateJournalCall wrap *gomock.Call
+type StateLedgerGetStateJournalCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *StateLedgerGetStateJournalCall) Return(arg0 *types.SnapshotJournal, arg1 error) *StateLedgerGetStateJournalCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *StateLedgerGetStateJournalCall) Do(f func(uint64) (*types.SnapshotJournal, error)) *StateLedgerGetStateJournalCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *StateLedgerGetStateJournalCall) DoAndReturn(f func(uint64) (*types.SnapshotJournal, error)) *StateLedgerGetStateJournalCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetTrieSnapshotMeta mocks base method.
 func (m *MockStateLedger) GetTrieSnapshotMeta() (*ledger.SnapshotMeta, error) {
 	m.ctrl.T.Helper()
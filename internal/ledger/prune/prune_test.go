@@ -12,6 +12,7 @@ import (
 	"github.com/axiomesh/axiom-kit/log"
 	"github.com/axiomesh/axiom-kit/storage/kv"
 	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/ledger/utils"
 	"github.com/axiomesh/axiom-ledger/internal/storagemgr"
 	"github.com/axiomesh/axiom-ledger/pkg/repo"
 )
@@ -425,6 +426,50 @@ func TestPruningFlushByMaxFlushTime(t *testing.T) {
 	require.Equal(t, 10, len(tc.states.diffs))
 }
 
+func TestPruningArchive(t *testing.T) {
+	logger := log.NewWithModule("prune_test")
+	pStateStorage := kv.NewMemory()
+
+	rep := createMockRepo(t)
+	rep.Config.Ledger.EnableArchive = true
+	accountTrieCache := storagemgr.NewCacheWrapper(32, true)
+	storageTrieCache := storagemgr.NewCacheWrapper(32, true)
+	tc := NewPruneCache(rep, pStateStorage, accountTrieCache, storageTrieCache, logger)
+
+	batch := pStateStorage.NewBatch()
+	blockNum := tc.rep.Config.Ledger.StateLedgerReservedHistoryBlockNum + maxFlushBlockNum
+	for i := 0; i < blockNum; i++ {
+		pruneSet := map[string]struct{}{}
+		if i > 0 {
+			// the node written in last block is stale
+			pruneSet["k"+strconv.Itoa(i)] = struct{}{}
+		}
+		tc.Update(batch, uint64(i+1), &types.StateDelta{
+			Journal: []*types.TrieJournal{
+				{
+					RootHash:    common.HexToHash("0x4d5e855f8fb3fe5ed1eb123d4feb2a8f96b025fca63a19f02b8727d3d4f8ef28"),
+					RootNodeKey: &types.NodeKey{Version: uint64(i + 1), Path: []byte("path"), Type: []byte("type")},
+					DirtySet: map[string]types.Node{
+						"k" + strconv.Itoa(i+1): makeLeafNode("v" + strconv.Itoa(i+1)),
+					},
+					PruneSet: pruneSet,
+				},
+			},
+		})
+	}
+	batch.Commit()
+	time.Sleep(2*checkFlushTimeInterval + 300*time.Millisecond)
+
+	require.Equal(t, tc.rep.Config.Ledger.StateLedgerReservedHistoryBlockNum, len(tc.states.diffs))
+	minHeight, _ := tc.GetRange()
+	require.EqualValues(t, maxFlushBlockNum+1, minHeight)
+	// stale nodes are kept in archive mode
+	for i := 1; i <= maxFlushBlockNum; i++ {
+		require.Equal(t, makeLeafNode("v"+strconv.Itoa(i)).Encode(), tc.ledgerStorage.Get([]byte("k"+strconv.Itoa(i))))
+		require.Nil(t, tc.ledgerStorage.Get(utils.CompositeKey(utils.PruneJournalKey, i)))
+	}
+}
+
 func TestPruneCacheNil(t *testing.T) {
 	rep := createMockRepo(t)
 	rep.Config.Ledger.EnablePrune = false
@@ -51,6 +51,8 @@ func (p *prunner) pruning() {
 	if p.rep.Config.Ledger.StateLedgerReservedHistoryBlockNum > reserve {
 		reserve = p.rep.Config.Ledger.StateLedgerReservedHistoryBlockNum
 	}
+	// archive node keeps all stale trie nodes, only the journals out of the reserved window are dropped
+	archive := p.rep.Config.Ledger.EnableArchive
 
 	var (
 		ticker                                   = time.NewTicker(checkFlushTimeInterval)
@@ -119,12 +121,16 @@ func (p *prunner) pruning() {
 			if _, has := accountTriePruneSet[k]; !has {
 				pendingBatch.Put([]byte(k), v)
 				p.accountTrieCache.Set([]byte(k), v)
+			} else if archive {
+				pendingBatch.Put([]byte(k), v)
 			}
 		}
-		for k := range accountTriePruneSet {
-			if _, has := accountTrieWriteSet[k]; !has {
-				pendingBatch.Delete([]byte(k))
-				p.accountTrieCache.Del([]byte(k))
+		if !archive {
+			for k := range accountTriePruneSet {
+				if _, has := accountTrieWriteSet[k]; !has {
+					pendingBatch.Delete([]byte(k))
+					p.accountTrieCache.Del([]byte(k))
+				}
 			}
 		}
 
@@ -133,12 +139,16 @@ func (p *prunner) pruning() {
 			if _, has := storageTriePruneSet[k]; !has {
 				pendingBatch.Put([]byte(k), v)
 				p.storageTrieCache.Set([]byte(k), v)
+			} else if archive {
+				pendingBatch.Put([]byte(k), v)
 			}
 		}
-		for k := range storageTriePruneSet {
-			if _, has := storageTrieWriteSet[k]; !has {
-				pendingBatch.Delete([]byte(k))
-				p.storageTrieCache.Del([]byte(k))
+		if !archive {
+			for k := range storageTriePruneSet {
+				if _, has := storageTrieWriteSet[k]; !has {
+					pendingBatch.Delete([]byte(k))
+					p.storageTrieCache.Del([]byte(k))
+				}
 			}
 		}
 
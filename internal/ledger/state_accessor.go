@@ -27,7 +27,9 @@ const MinJournalHeight = 10
 func (l *StateLedgerImpl) GetOrCreateAccount(addr *types.Address) IAccount {
 	account := l.GetAccount(addr)
 	if account == nil {
-		account = NewAccount(l.blockHeight, l.backend, l.storageTrieCache, l.pruneCache, addr, l.changer, l.snapshot)
+		newAccount := NewAccount(l.blockHeight, l.backend, l.storageTrieCache, l.pruneCache, addr, l.changer, l.snapshot)
+		newAccount.fork = l.fork
+		account = newAccount
 		account.SetCreated(true)
 		l.changer.append(createObjectChange{account: addr})
 		l.accounts[addr.String()] = account
@@ -50,12 +52,13 @@ func (l *StateLedgerImpl) GetAccount(address *types.Address) IAccount {
 	}
 
 	account := NewAccount(l.blockHeight, l.backend, l.storageTrieCache, l.pruneCache, address, l.changer, l.snapshot)
+	account.fork = l.fork
 
 	// try getting account from snapshot first
 	if l.snapshot != nil {
 		if innerAccount, err := l.snapshot.Account(address); err == nil {
 			if innerAccount == nil {
-				return nil
+				return l.loadForkAccount(account)
 			}
 			account.originAccount = innerAccount
 			if !bytes.Equal(innerAccount.CodeHash, nil) {
@@ -89,6 +92,9 @@ func (l *StateLedgerImpl) GetAccount(address *types.Address) IAccount {
 		l.logger.Debugf("[GetAccount] get from account trie，addr: %v, account: %v", addr, account)
 		return account
 	}
+	if forkAccount := l.loadForkAccount(account); forkAccount != nil {
+		return forkAccount
+	}
 	l.logger.Debugf("[GetAccount] account not found，addr: %v", addr)
 	return nil
 }
@@ -269,6 +275,9 @@ func (l *StateLedgerImpl) Commit() (*types.Hash, error) {
 					return nil, err
 				}
 			}
+			if l.fork != nil {
+				l.fork.markDestructed(kvBatch, account.Addr, height)
+			}
 			destructSet[account.Addr.String()] = struct{}{}
 			continue
 		}
@@ -287,6 +296,9 @@ func (l *StateLedgerImpl) Commit() (*types.Hash, error) {
 		for key, valBytes := range account.pendingState {
 			if !bytes.Equal(account.originState[key], valBytes) {
 				dirtyEntries[key] = valBytes
+				if valBytes == nil && l.fork != nil {
+					l.fork.markStorageDeleted(kvBatch, account.Addr, []byte(key), height)
+				}
 			}
 			storageSet[addr][key] = valBytes
 		}
@@ -430,6 +442,10 @@ func (l *StateLedgerImpl) RollbackState(height uint64, stateRoot *types.Hash) er
 	}
 	l.refreshAccountTrie(stateRoot)
 
+	if l.fork != nil {
+		l.fork.rollback(height)
+	}
+
 	return nil
 }
 
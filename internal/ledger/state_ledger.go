@@ -30,6 +30,17 @@ var (
 	ErrorRollbackToHigherNumber = errors.New("rollback to higher blockchain height")
 )
 
+// HistoryUnavailableError is returned when the state at the target block is not kept by the node.
+type HistoryUnavailableError struct {
+	Number uint64
+	Min    uint64
+	Max    uint64
+}
+
+func (e *HistoryUnavailableError) Error() string {
+	return fmt.Sprintf("history at target block %v is invalid, the valid range is from %v to %v", e.Number, e.Min, e.Max)
+}
+
 // maxBatchSize defines the maximum size of the data in single batch write operation, which is 64 MB.
 const maxBatchSize = 64 * 1024 * 1024
 
@@ -66,6 +77,12 @@ type StateLedgerImpl struct {
 
 	snapshot *snapshot.Snapshot
 
+	// reads the state missing locally from the forked chain, nil if fork mode is disabled
+	fork *forkReader
+
+	// the first block whose state is kept by archive mode
+	archiveHeight uint64
+
 	transientStorage transientStorage
 }
 
@@ -137,8 +154,11 @@ func (l *StateLedgerImpl) NewView(blockHeader *types.BlockHeader, enableSnapshot
 	l.logger.Debugf("[NewView] height: %v, stateRoot: %v", blockHeader.Number, blockHeader.StateRoot)
 	if l.repo.Config.Ledger.EnablePrune {
 		min, max := l.GetHistoryRange()
+		if l.repo.Config.Ledger.EnableArchive {
+			min = l.archiveHeight
+		}
 		if blockHeader.Number < min || blockHeader.Number > max {
-			return nil, fmt.Errorf("history at target block %v is invalid, the valid range is from %v to %v", blockHeader.Number, min, max)
+			return nil, &HistoryUnavailableError{Number: blockHeader.Number, Min: min, Max: max}
 		}
 	}
 
@@ -156,6 +176,8 @@ func (l *StateLedgerImpl) NewView(blockHeader *types.BlockHeader, enableSnapshot
 		accessList:       NewAccessList(),
 		logs:             newEvmLogs(),
 		blockHeight:      blockHeader.Number,
+		archiveHeight:    l.archiveHeight,
+		fork:             l.fork.newView(),
 	}
 	if enableSnapshot {
 		lg.snapshot = l.snapshot
@@ -172,6 +194,24 @@ func (l *StateLedgerImpl) GetStateDelta(blockNumber uint64) *types.StateDelta {
 	return l.pruneCache.GetStateDelta(blockNumber)
 }
 
+// GetStateJournal returns the changed accounts and storage slots of the block with their previous values,
+// only the journals of the recent blocks are kept.
+func (l *StateLedgerImpl) GetStateJournal(blockNumber uint64) (*types.SnapshotJournal, error) {
+	if l.snapshot == nil {
+		return nil, ErrSnapshotDisabled
+	}
+	min, max := l.snapshot.GetJournalRange()
+	if blockNumber < min || blockNumber > max {
+		return nil, &HistoryUnavailableError{Number: blockNumber, Min: min, Max: max}
+	}
+	journal := l.snapshot.GetBlockJournal(blockNumber)
+	if journal == nil {
+		// the state is not changed in the block
+		journal = &types.SnapshotJournal{}
+	}
+	return journal, nil
+}
+
 func (l *StateLedgerImpl) Finalise() {
 	for _, account := range l.accounts {
 		keys := account.Finalise()
@@ -185,76 +225,211 @@ func (l *StateLedgerImpl) Finalise() {
 	l.ClearChangerAndRefund()
 }
 
+// trieIterCursor records how far IterateTrie has gone, so that an interrupted iteration
+// can continue from the first unfinished trie instead of starting over.
+type trieIterCursor struct {
+	BlockNumber     uint64 `json:"block_number"`
+	AccountTrieDone bool   `json:"account_trie_done"`
+	StorageTries    uint64 `json:"storage_tries"`
+	Next            uint64 `json:"next"`
+}
+
+func getTrieIterCursor(kv kv.Storage, blockNumber uint64) (*trieIterCursor, error) {
+	raw := kv.Get([]byte(utils.TrieIterCursorKey))
+	if len(raw) == 0 {
+		return &trieIterCursor{BlockNumber: blockNumber}, nil
+	}
+	cursor := &trieIterCursor{}
+	if err := json.Unmarshal(raw, cursor); err != nil {
+		return nil, err
+	}
+	// the cursor was left by the iteration of another block, start over
+	if cursor.BlockNumber != blockNumber {
+		return &trieIterCursor{BlockNumber: blockNumber}, nil
+	}
+	return cursor, nil
+}
+
+func putTrieIterCursor(batch kv.Batch, cursor *trieIterCursor) error {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	batch.Put([]byte(utils.TrieIterCursorKey), raw)
+	return nil
+}
+
 // IterateTrie iterate the whole account trie and all contract storage tries of target block, and store them in kv.
+// The progress is persisted in kv along with the trie data, an interrupted iteration of the same block resumes
+// from the first unfinished trie.
 func (l *StateLedgerImpl) IterateTrie(snapshotMeta *SnapshotMeta, kv kv.Storage, errC chan error) {
-	stateRoot := snapshotMeta.BlockHeader.StateRoot.ETHHash()
-	l.logger.Infof("[IterateTrie] blockhash: %v, rootHash: %v", snapshotMeta.BlockHeader.Hash(), stateRoot)
+	l.logger.Infof("[IterateTrie] blockhash: %v, rootHash: %v", snapshotMeta.BlockHeader.Hash(), snapshotMeta.BlockHeader.StateRoot)
+	cursor, err := getTrieIterCursor(kv, snapshotMeta.BlockHeader.Number)
+	if err != nil {
+		errC <- err
+		return
+	}
+	if cursor.AccountTrieDone {
+		l.logger.Infof("[IterateTrie] resume from storage trie %d/%d", cursor.Next, cursor.StorageTries)
+	}
+
+	if err := l.rollbackPruneCache(snapshotMeta.BlockHeader); err != nil {
+		errC <- err
+		return
+	}
+
 	batch := kv.NewBatch()
+	put := func(key, value []byte) error {
+		batch.Put(key, value)
+		// data size exceed threshold, flush to disk
+		if batch.Size() > maxBatchSize {
+			batch.Commit()
+			batch.Reset()
+			l.logger.Infof("[IterateTrie] write batch periodically")
+		}
+		return nil
+	}
 
-	// in validate node, we should rebuild prune cache before iterate trie
-	if l.pruneCache != nil {
-		if err := l.pruneCache.Rollback(snapshotMeta.BlockHeader.Number, false); err != nil {
+	if !cursor.AccountTrieDone {
+		var storageTries uint64
+		if err := l.exportTrie(snapshotMeta.BlockHeader.StateRoot.ETHHash(), true, put, func(storageRoot common.Hash) error {
+			batch.Put(utils.CompositeKey(utils.TrieIterStorageRootKey, storageTries), storageRoot.Bytes())
+			storageTries++
+			return nil
+		}); err != nil {
+			errC <- err
+			return
+		}
+		cursor.AccountTrieDone = true
+		cursor.StorageTries = storageTries
+		cursor.Next = 0
+		if err := putTrieIterCursor(batch, cursor); err != nil {
 			errC <- err
+			return
 		}
+		// storage trie roots are read from kv below
+		batch.Commit()
+		batch.Reset()
 	}
-	batch.Put(utils.CompositeKey(utils.PruneJournalKey, utils.MinHeightStr), utils.MarshalUint64(snapshotMeta.BlockHeader.Number))
-	batch.Put(utils.CompositeKey(utils.PruneJournalKey, utils.MaxHeightStr), utils.MarshalUint64(snapshotMeta.BlockHeader.Number))
 
-	queue := []common.Hash{stateRoot}
-	for len(queue) > 0 {
-		trieRoot := queue[0]
-		iter := jmt.NewIterator(trieRoot, l.backend, l.pruneCache, 10000, 300*time.Second)
-		l.logger.Debugf("[IterateTrie] trie root=%v", trieRoot)
-		go iter.Iterate()
+	for ; cursor.Next < cursor.StorageTries; cursor.Next++ {
+		storageRoot := kv.Get(utils.CompositeKey(utils.TrieIterStorageRootKey, cursor.Next))
+		if len(storageRoot) == 0 {
+			errC <- fmt.Errorf("storage trie root %d is not found", cursor.Next)
+			return
+		}
+		if err := l.exportTrie(common.BytesToHash(storageRoot), false, put, nil); err != nil {
+			errC <- err
+			return
+		}
+		if err := putTrieIterCursor(batch, &trieIterCursor{
+			BlockNumber:     cursor.BlockNumber,
+			AccountTrieDone: true,
+			StorageTries:    cursor.StorageTries,
+			Next:            cursor.Next + 1,
+		}); err != nil {
+			errC <- err
+			return
+		}
+	}
 
-		for {
-			node, err := iter.Next()
-			if err != nil {
-				if err == jmt.ErrorNoMoreData {
-					break
-				} else {
-					errC <- err
-					return
-				}
+	for i := uint64(0); i < cursor.StorageTries; i++ {
+		batch.Delete(utils.CompositeKey(utils.TrieIterStorageRootKey, i))
+	}
+	batch.Delete([]byte(utils.TrieIterCursorKey))
+	if err := PutTrieSnapshotMeta(batch, snapshotMeta); err != nil {
+		errC <- err
+		return
+	}
+	batch.Commit()
+	l.logger.Infof("[IterateTrie] iterate trie successfully")
+
+	errC <- nil
+}
+
+// ExportTrie iterates the whole account trie and all contract storage tries of target block,
+// and emits the trie nodes, contract codes and trie roots which are needed to rebuild the state.
+func (l *StateLedgerImpl) ExportTrie(blockHeader *types.BlockHeader, fn func(key, value []byte) error) error {
+	if err := l.rollbackPruneCache(blockHeader); err != nil {
+		return err
+	}
+
+	var storageRoots []common.Hash
+	if err := l.exportTrie(blockHeader.StateRoot.ETHHash(), true, fn, func(storageRoot common.Hash) error {
+		storageRoots = append(storageRoots, storageRoot)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, storageRoot := range storageRoots {
+		if err := l.exportTrie(storageRoot, false, fn, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *StateLedgerImpl) rollbackPruneCache(blockHeader *types.BlockHeader) error {
+	// in validate node, we should rebuild prune cache before iterate trie
+	if l.pruneCache != nil {
+		return l.pruneCache.Rollback(blockHeader.Number, false)
+	}
+	return nil
+}
+
+// exportTrie emits the nodes and the root of a single trie. For the account trie, it also emits
+// the contract codes and reports the storage trie roots through onStorageRoot.
+func (l *StateLedgerImpl) exportTrie(trieRoot common.Hash, isAccountTrie bool, fn func(key, value []byte) error, onStorageRoot func(storageRoot common.Hash) error) error {
+	iter := jmt.NewIterator(trieRoot, l.backend, l.pruneCache, 10000, 300*time.Second)
+	l.logger.Debugf("[ExportTrie] trie root=%v", trieRoot)
+	go iter.Iterate()
+
+	for {
+		node, err := iter.Next()
+		if err != nil {
+			if err == jmt.ErrorNoMoreData {
+				break
 			}
-			batch.Put(node.RawKey, node.RawValue)
-			// data size exceed threshold, flush to disk
-			if batch.Size() > maxBatchSize {
-				batch.Commit()
-				batch.Reset()
-				l.logger.Infof("[IterateTrie] write batch periodically")
+			return err
+		}
+		if err := fn(node.RawKey, node.RawValue); err != nil {
+			return err
+		}
+		if isAccountTrie && len(node.LeafValue) > 0 {
+			// resolve potential contract account
+			acc := &types.InnerAccount{Balance: big.NewInt(0)}
+			if err := acc.Unmarshal(node.LeafValue); err != nil {
+				panic(err)
 			}
-			if trieRoot == stateRoot && len(node.LeafValue) > 0 {
-				// resolve potential contract account
-				acc := &types.InnerAccount{Balance: big.NewInt(0)}
-				if err := acc.Unmarshal(node.LeafValue); err != nil {
-					panic(err)
+			// contract may have code but no storage
+			if len(acc.CodeHash) > 0 {
+				codeKey := utils.CompositeCodeKey(types.NewAddress(types.HexToBytes(node.LeafKey)), acc.CodeHash)
+				if err := fn(codeKey, l.backend.Get(codeKey)); err != nil {
+					return err
 				}
-				if acc.StorageRoot != (common.Hash{}) {
-					// set contract code
-					codeKey := utils.CompositeCodeKey(types.NewAddress(types.HexToBytes(node.LeafKey)), acc.CodeHash)
-					batch.Put(codeKey, l.backend.Get(codeKey))
-					// prepare storage trie root
-					queue = append(queue, acc.StorageRoot)
+			}
+			if acc.StorageRoot != (common.Hash{}) {
+				// prepare storage trie root
+				if err := onStorageRoot(acc.StorageRoot); err != nil {
+					return err
 				}
 			}
 		}
-		queue = queue[1:]
-		l.logger.Infof("[IterateTrie] trieRoot=%v, rootNodeKey from kv=%v", trieRoot, l.backend.Get(trieRoot[:]))
-		batch.Put(trieRoot[:], l.backend.Get(trieRoot[:]))
 	}
+	l.logger.Infof("[ExportTrie] trieRoot=%v, rootNodeKey from kv=%v", trieRoot, l.backend.Get(trieRoot[:]))
+	return fn(trieRoot[:], l.backend.Get(trieRoot[:]))
+}
 
+// PutTrieSnapshotMeta marks the trie in kv as the state of snapshot block, so that the node can start in snapshot mode.
+func PutTrieSnapshotMeta(batch kv.Batch, snapshotMeta *SnapshotMeta) error {
 	snapshotMetaBytes, err := snapshotMeta.Marshal()
 	if err != nil {
-		errC <- err
-		return
+		return err
 	}
+	batch.Put(utils.CompositeKey(utils.PruneJournalKey, utils.MinHeightStr), utils.MarshalUint64(snapshotMeta.BlockHeader.Number))
+	batch.Put(utils.CompositeKey(utils.PruneJournalKey, utils.MaxHeightStr), utils.MarshalUint64(snapshotMeta.BlockHeader.Number))
 	batch.Put([]byte(utils.SnapshotMetaKey), snapshotMetaBytes)
-
-	batch.Commit()
-	l.logger.Infof("[IterateTrie] iterate trie successfully")
-
-	errC <- nil
+	return nil
 }
 
 func (l *StateLedgerImpl) GetTrieSnapshotMeta() (*SnapshotMeta, error) {
@@ -350,14 +525,17 @@ func (l *StateLedgerImpl) Prove(rootHash common.Hash, key []byte) (*jmt.ProofRes
 }
 
 func newStateLedger(rep *repo.Repo, stateStorage, snapshotStorage kv.Storage) (StateLedger, error) {
-	stateCachedStorage := storagemgr.NewCachedStorage(stateStorage, 128).(*storagemgr.CachedStorage)
-	accountTrieCache := storagemgr.NewCacheWrapper(rep.Config.Ledger.StateLedgerAccountTrieCacheMegabytesLimit, true)
-	storageTrieCache := storagemgr.NewCacheWrapper(rep.Config.Ledger.StateLedgerStorageTrieCacheMegabytesLimit, true)
-
 	trieIndexerKv, err := storagemgr.OpenWithMetrics(repo.GetStoragePath(rep.RepoRoot, storagemgr.TrieIndexer), storagemgr.TrieIndexer)
 	if err != nil {
 		return nil, err
 	}
+	return newStateLedgerWithTrieIndexer(rep, stateStorage, snapshotStorage, trieIndexerKv)
+}
+
+func newStateLedgerWithTrieIndexer(rep *repo.Repo, stateStorage, snapshotStorage, trieIndexerKv kv.Storage) (StateLedger, error) {
+	stateCachedStorage := storagemgr.NewCachedStorage(stateStorage, 128).(*storagemgr.CachedStorage)
+	accountTrieCache := storagemgr.NewCacheWrapper(rep.Config.Ledger.StateLedgerAccountTrieCacheMegabytesLimit, true)
+	storageTrieCache := storagemgr.NewCacheWrapper(rep.Config.Ledger.StateLedgerStorageTrieCacheMegabytesLimit, true)
 
 	ledger := &StateLedgerImpl{
 		repo:             rep,
@@ -378,11 +556,52 @@ func newStateLedger(rep *repo.Repo, stateStorage, snapshotStorage kv.Storage) (S
 		ledger.snapshot = snapshot.NewSnapshot(rep, snapshotStorage, ledger.logger)
 	}
 
+	if err := ledger.loadArchiveHeight(); err != nil {
+		return nil, err
+	}
+
+	fork, err := newForkState(rep, ledger.backend, ledger.logger)
+	if err != nil {
+		return nil, err
+	}
+	ledger.fork = newForkReader(fork)
+
 	ledger.refreshAccountTrie(nil)
 
 	return ledger, nil
 }
 
+// loadArchiveHeight loads the first block kept by archive mode, archive mode can only be enabled
+// on the state which has never been pruned, otherwise the history before min height is already lost.
+func (l *StateLedgerImpl) loadArchiveHeight() error {
+	archiveKey := utils.CompositeKey(utils.PruneJournalKey, utils.ArchiveHeightStr)
+	if !l.repo.Config.Ledger.EnableArchive {
+		if l.backend.Has(archiveKey) {
+			l.logger.Warnf("archive mode is disabled, the state out of the reserved history will be pruned")
+			l.backend.Delete(archiveKey)
+		}
+		return nil
+	}
+
+	if data := l.backend.Get(archiveKey); data != nil {
+		l.archiveHeight = utils.UnmarshalUint64(data)
+		l.logger.Infof("archive mode is enabled, the state is kept from block %v", l.archiveHeight)
+		return nil
+	}
+
+	minHeight := uint64(0)
+	if data := l.backend.Get(utils.CompositeKey(utils.PruneJournalKey, utils.MinHeightStr)); data != nil {
+		minHeight = utils.UnmarshalUint64(data)
+	}
+	if minHeight != 0 {
+		return fmt.Errorf("cannot enable archive mode, the state before block %v has been pruned, please resync the node with archive mode enabled", minHeight)
+	}
+	l.backend.Put(archiveKey, utils.MarshalUint64(minHeight))
+	l.archiveHeight = minHeight
+	l.logger.Infof("archive mode is enabled, the state is kept from block %v", l.archiveHeight)
+	return nil
+}
+
 // NewStateLedger create a new ledger instance
 func NewStateLedger(rep *repo.Repo, storageDir string) (StateLedger, error) {
 	stateStoragePath := repo.GetStoragePath(rep.RepoRoot, storagemgr.Ledger)
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"strconv"
 
@@ -11,21 +12,33 @@ import (
 )
 
 const (
-	BlockHashKey       = "block-hash-"
-	BlockTxSetKey      = "block-tx-set-"
-	TransactionMetaKey = "tx-meta-"
-	ChainMetaKey       = "chain-meta"
-	PruneJournalKey    = "prune-nodeInfo-"
-	SnapshotKey        = "snap-"
-	SnapshotMetaKey    = "snap-meta"
-	RollbackBlockKey   = "rollback-block"
-	RollbackStateKey   = "rollback-state"
-	TrieNodeIndexKey   = "tni-"
+	BlockHashKey           = "block-hash-"
+	BlockTxSetKey          = "block-tx-set-"
+	TransactionMetaKey     = "tx-meta-"
+	ChainMetaKey           = "chain-meta"
+	PruneJournalKey        = "prune-nodeInfo-"
+	SnapshotKey            = "snap-"
+	SnapshotMetaKey        = "snap-meta"
+	RollbackBlockKey       = "rollback-block"
+	RollbackStateKey       = "rollback-state"
+	TrieNodeIndexKey       = "tni-"
+	TrieIterCursorKey      = "trie-iter-cursor"
+	TrieIterStorageRootKey = "trie-iter-root-"
+	AddressTxKey           = "addr-tx-"
+	BundleTxKey            = "bundle-tx-"
+
+	ForkMetaKey          = "fork-meta"
+	ForkAccountKey       = "fork-acc-"
+	ForkStorageKey       = "fork-st-"
+	ForkDestructKey      = "fork-del-acc-"
+	ForkStorageDeleteKey = "fork-del-st-"
 )
 
 const (
 	MinHeightStr = "minHeight"
 	MaxHeightStr = "maxHeight"
+
+	ArchiveHeightStr = "archiveHeight"
 )
 
 var keyCache = storagemgr.NewCacheWrapper(64, false)
@@ -59,6 +72,13 @@ func CompositeCodeKey(addr *types.Address, codeHash []byte) []byte {
 	return append(addr.Bytes(), codeHash...)
 }
 
+// CompositeAddressTxKey orders the txs of the address by block height and tx index
+func CompositeAddressTxKey(addr *types.Address, height uint64, index uint64) []byte {
+	key := append([]byte(AddressTxKey), addr.Bytes()...)
+	key = binary.BigEndian.AppendUint64(key, height)
+	return binary.BigEndian.AppendUint64(key, index)
+}
+
 func MarshalUint64(data uint64) []byte {
 	return []byte(strconv.FormatUint(data, 10))
 }
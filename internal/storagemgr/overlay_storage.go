@@ -0,0 +1,111 @@
+package storagemgr
+
+import (
+	"sync"
+
+	"github.com/axiomesh/axiom-kit/storage/kv"
+)
+
+// OverlayStorage keeps all the writes in memory and reads through to the base storage,
+// so the base storage is never modified. The iterators only see the data of the base storage.
+type OverlayStorage struct {
+	kv.Storage
+	lock  sync.RWMutex
+	dirty map[string]*overlayEntry
+}
+
+type overlayEntry struct {
+	value   []byte
+	deleted bool
+}
+
+func NewOverlayStorage(base kv.Storage) kv.Storage {
+	return &OverlayStorage{
+		Storage: base,
+		dirty:   make(map[string]*overlayEntry),
+	}
+}
+
+func (o *OverlayStorage) Get(key []byte) []byte {
+	o.lock.RLock()
+	entry, ok := o.dirty[string(key)]
+	o.lock.RUnlock()
+	if ok {
+		if entry.deleted {
+			return nil
+		}
+		return entry.value
+	}
+	return o.Storage.Get(key)
+}
+
+func (o *OverlayStorage) Has(key []byte) bool {
+	o.lock.RLock()
+	entry, ok := o.dirty[string(key)]
+	o.lock.RUnlock()
+	if ok {
+		return !entry.deleted
+	}
+	return o.Storage.Has(key)
+}
+
+func (o *OverlayStorage) Put(key, value []byte) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.dirty[string(key)] = &overlayEntry{value: append([]byte{}, value...)}
+}
+
+func (o *OverlayStorage) Delete(key []byte) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.dirty[string(key)] = &overlayEntry{deleted: true}
+}
+
+// Close drops the writes, the base storage is not closed.
+func (o *OverlayStorage) Close() error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.dirty = make(map[string]*overlayEntry)
+	return nil
+}
+
+func (o *OverlayStorage) NewBatch() kv.Batch {
+	return &overlayBatch{storage: o}
+}
+
+type overlayBatch struct {
+	storage *OverlayStorage
+	keys    [][]byte
+	entries []*overlayEntry
+	size    int
+}
+
+func (b *overlayBatch) Put(key, value []byte) {
+	b.keys = append(b.keys, append([]byte{}, key...))
+	b.entries = append(b.entries, &overlayEntry{value: append([]byte{}, value...)})
+	b.size += len(key) + len(value)
+}
+
+func (b *overlayBatch) Delete(key []byte) {
+	b.keys = append(b.keys, append([]byte{}, key...))
+	b.entries = append(b.entries, &overlayEntry{deleted: true})
+	b.size += len(key)
+}
+
+func (b *overlayBatch) Commit() {
+	b.storage.lock.Lock()
+	defer b.storage.lock.Unlock()
+	for i, key := range b.keys {
+		b.storage.dirty[string(key)] = b.entries[i]
+	}
+}
+
+func (b *overlayBatch) Size() int {
+	return b.size
+}
+
+func (b *overlayBatch) Reset() {
+	b.keys = nil
+	b.entries = nil
+	b.size = 0
+}
@@ -0,0 +1,41 @@
+package storagemgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/axiomesh/axiom-kit/storage/kv"
+)
+
+func TestOverlayStorage(t *testing.T) {
+	base := kv.NewMemory()
+	base.Put([]byte("k1"), []byte("v1"))
+	base.Put([]byte("k2"), []byte("v2"))
+	o := NewOverlayStorage(base)
+
+	assert.Equal(t, []byte("v1"), o.Get([]byte("k1")))
+	o.Put([]byte("k1"), []byte("v1'"))
+	o.Delete([]byte("k2"))
+	o.Put([]byte("k3"), []byte("v3"))
+	assert.Equal(t, []byte("v1'"), o.Get([]byte("k1")))
+	assert.False(t, o.Has([]byte("k2")))
+	assert.Nil(t, o.Get([]byte("k2")))
+	assert.Equal(t, []byte("v3"), o.Get([]byte("k3")))
+
+	batch := o.NewBatch()
+	batch.Put([]byte("k2"), []byte("v2'"))
+	batch.Delete([]byte("k3"))
+	assert.Nil(t, o.Get([]byte("k2")))
+	batch.Commit()
+	assert.Equal(t, []byte("v2'"), o.Get([]byte("k2")))
+	assert.False(t, o.Has([]byte("k3")))
+
+	// the base storage is not modified
+	assert.Equal(t, []byte("v1"), base.Get([]byte("k1")))
+	assert.Equal(t, []byte("v2"), base.Get([]byte("k2")))
+	assert.False(t, base.Has([]byte("k3")))
+
+	assert.Nil(t, o.Close())
+	assert.Equal(t, []byte("v1"), o.Get([]byte("k1")))
+}
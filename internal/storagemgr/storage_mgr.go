@@ -27,6 +27,7 @@ const (
 	TxPool      = "txpool"
 	Sync        = "sync"
 	TrieIndexer = "trie_indexer"
+	InternalTx  = "internal_tx"
 )
 
 var globalStorageMgr = &storageMgr{
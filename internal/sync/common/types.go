@@ -15,6 +15,9 @@ const (
 	SyncChainDataRequestPipe  = "sync_chain_data_pipe_v1_request"
 	SyncChainDataResponsePipe = "sync_chain_data_pipe_v1_response"
 
+	SyncHeaderRequestPipe  = "sync_header_pipe_v1_request"
+	SyncHeaderResponsePipe = "sync_header_pipe_v1_response"
+
 	MaxRetryCount = 5
 )
 
@@ -46,6 +49,7 @@ type SyncParams struct {
 	TargetHeight     uint64
 	QuorumCheckpoint *consensus.SignedCheckpoint
 	EpochChanges     []*consensus.EpochChange
+	Resumed          bool // whether the sync continues the persisted progress of an interrupted sync
 }
 
 type LocalEvent struct {
@@ -220,4 +224,5 @@ type SyncProgress struct {
 	TargetHeight       uint64 `json:"targetHeight"`       // Target block height where sync ended
 	SyncMode           string `json:"syncMode"`           // Sync mode (full or snapshot)
 	Peers              []Node `json:"peers"`              // List of remote peers in sync
+	Resumed            bool   `json:"resumed"`            // Whether the sync is resumed from persisted progress
 }
@@ -0,0 +1,757 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-kit/types/pb"
+	"github.com/axiomesh/axiom-ledger/internal/network"
+	"github.com/axiomesh/axiom-ledger/internal/sync/common"
+	network2 "github.com/axiomesh/axiom-p2p"
+)
+
+var errFetchTimeout = errors.New("fetch timeout")
+
+// fetcher sends the request of a height to a peer by the request pipe,
+// and dispatches the response received from the response pipe to the fetch waiting for it.
+type fetcher struct {
+	reqPipe  network.Pipe
+	respPipe network.Pipe
+	respType pb.Message_Type
+	newReq   func(height uint64) common.SyncRequestMessage
+	newResp  func() common.SyncResponseMessage
+	logger   logrus.FieldLogger
+
+	lock    sync.Mutex
+	pending map[uint64]*pendingFetch
+}
+
+type pendingFetch struct {
+	peerID string
+	respCh chan common.SyncResponseMessage
+}
+
+func (f *fetcher) listen(ctx context.Context) {
+	for {
+		msg := f.respPipe.Receive(ctx)
+		if msg == nil {
+			return
+		}
+
+		p2pMsg := &pb.Message{}
+		if err := p2pMsg.UnmarshalVT(msg.Data); err != nil {
+			f.logger.Warnf("Unmarshal sync response failed: %s", err)
+			continue
+		}
+		if p2pMsg.Type != f.respType {
+			f.logger.Warnf("Receive invalid sync response type: %s", p2pMsg.Type)
+			continue
+		}
+		resp := f.newResp()
+		if err := resp.UnmarshalVT(p2pMsg.Data); err != nil {
+			f.logger.Warnf("Unmarshal sync response failed: %s", err)
+			continue
+		}
+
+		f.lock.Lock()
+		p, ok := f.pending[resp.GetHeight()]
+		if ok && p.peerID == p2pMsg.From {
+			delete(f.pending, resp.GetHeight())
+			p.respCh <- resp
+		}
+		f.lock.Unlock()
+		if !ok || p.peerID != p2pMsg.From {
+			f.logger.WithFields(logrus.Fields{
+				"height": resp.GetHeight(),
+				"from":   p2pMsg.From,
+			}).Debug("Receive sync response which is not requested, we will ignore it")
+		}
+	}
+}
+
+func (f *fetcher) fetch(ctx context.Context, peerID string, height uint64, timeout time.Duration) (common.SyncResponseMessage, error) {
+	data, err := f.newReq(height).MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pendingFetch{peerID: peerID, respCh: make(chan common.SyncResponseMessage, 1)}
+	f.lock.Lock()
+	f.pending[height] = p
+	f.lock.Unlock()
+	defer func() {
+		f.lock.Lock()
+		if f.pending[height] == p {
+			delete(f.pending, height)
+		}
+		f.lock.Unlock()
+	}()
+
+	if err = f.reqPipe.Send(ctx, peerID, data); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, errFetchTimeout
+	case resp := <-p.respCh:
+		if resp.GetStatus() != pb.Status_SUCCESS {
+			return nil, fmt.Errorf("receive invalid response: %s, error: %s", resp.GetStatus().String(), resp.GetError())
+		}
+		return resp, nil
+	}
+}
+
+type fetchPeer struct {
+	*common.Peer
+	failures atomic.Uint64
+	removed  atomic.Bool
+}
+
+type fetchedHeader struct {
+	peer   *fetchPeer
+	header *types.BlockHeader
+}
+
+// headerFirstSync syncs blocks in three overlapped stages:
+//  1. the headers are fetched concurrently and linked backward from every trusted checkpoint,
+//     so the hash of every block is verified before its body is requested;
+//  2. the bodies are fetched from all peers concurrently, and every body is validated independently
+//     against its verified hash, at most PipelineWindow blocks ahead of the committed height;
+//  3. the contiguous validated blocks are posted to the executor in batches, which never cross a checkpoint.
+type headerFirstSync struct {
+	sm          *SyncManager
+	ctx         context.Context
+	logger      logrus.FieldLogger
+	checkpoints []*pb.CheckpointState
+	window      uint64
+	batchSize   uint64
+	timeout     time.Duration
+
+	headerFetcher *fetcher
+	bodyFetcher   *fetcher
+
+	peerLock       sync.Mutex
+	peers          []*fetchPeer
+	availablePeers int
+
+	hashLock       sync.RWMutex
+	hashes         map[uint64]string // verified block hashes which are not committed
+	verifiedHeight atomic.Uint64
+	nextHeight     atomic.Uint64 // next height to commit
+	progressCh     chan struct{}
+
+	bodyCh chan common.CommitData
+	errCh  chan error
+	doneCh chan struct{}
+}
+
+func (sm *SyncManager) newHeaderFirstSync() (*headerFirstSync, error) {
+	checkpoints, err := sm.validateCheckpoints()
+	if err != nil {
+		return nil, err
+	}
+	if len(sm.peers) == 0 {
+		return nil, errors.New("no available peers")
+	}
+
+	window := sm.conf.PipelineWindow
+	if window == 0 {
+		window = sm.conf.MaxChunkSize
+	}
+	if window == 0 {
+		window = 1
+	}
+	batchSize := sm.conf.MaxChunkSize
+	if batchSize == 0 || batchSize > window {
+		batchSize = window
+	}
+
+	hs := &headerFirstSync{
+		sm:          sm,
+		ctx:         sm.syncCtx,
+		logger:      sm.logger,
+		checkpoints: checkpoints,
+		window:      window,
+		batchSize:   batchSize,
+		timeout:     sm.conf.RequesterRetryTimeout.ToDuration(),
+		headerFetcher: &fetcher{
+			reqPipe:  sm.headerRequestPipe,
+			respPipe: sm.headerResponsePipe,
+			respType: pb.Message_SYNC_BLOCK_RESPONSE,
+			newReq: func(height uint64) common.SyncRequestMessage {
+				return &pb.SyncBlockRequest{Height: height}
+			},
+			newResp: func() common.SyncResponseMessage {
+				return &pb.SyncBlockResponse{}
+			},
+			logger:  sm.logger,
+			pending: make(map[uint64]*pendingFetch),
+		},
+		bodyFetcher: &fetcher{
+			respType: common.CommitDataResponseType[sm.mode],
+			newReq: func(height uint64) common.SyncRequestMessage {
+				if sm.mode == common.SyncModeSnapshot {
+					return &pb.SyncChainDataRequest{Height: height}
+				}
+				return &pb.SyncBlockRequest{Height: height}
+			},
+			newResp: common.CommitDataResponseConstructor[sm.mode],
+			logger:  sm.logger,
+			pending: make(map[uint64]*pendingFetch),
+		},
+		availablePeers: len(sm.peers),
+		hashes:         make(map[uint64]string),
+		progressCh:     make(chan struct{}, 1),
+		bodyCh:         make(chan common.CommitData, window),
+		errCh:          make(chan error, 1),
+		doneCh:         make(chan struct{}),
+	}
+	switch sm.mode {
+	case common.SyncModeFull:
+		hs.bodyFetcher.reqPipe, hs.bodyFetcher.respPipe = sm.blockRequestPipe, sm.blockDataResponsePipe
+	case common.SyncModeSnapshot:
+		hs.bodyFetcher.reqPipe, hs.bodyFetcher.respPipe = sm.chainDataRequestPipe, sm.chainDataResponsePipe
+	}
+	for _, p := range sm.peers {
+		hs.peers = append(hs.peers, &fetchPeer{Peer: p})
+	}
+	hs.verifiedHeight.Store(sm.curHeight - 1)
+	hs.nextHeight.Store(sm.curHeight)
+	return hs, nil
+}
+
+// validateCheckpoints checks the epoch changes and the quorum checkpoint form a chain from the current height
+// to the target height, and returns them as the trusted checkpoints in ascending order.
+func (sm *SyncManager) validateCheckpoints() ([]*pb.CheckpointState, error) {
+	if sm.quorumCheckpoint.GetCheckpoint() == nil {
+		return nil, errors.New("quorum checkpoint is nil")
+	}
+	if sm.quorumCheckpoint.Height() != sm.targetHeight {
+		return nil, fmt.Errorf("quorum checkpoint height %d is not equal to target height %d", sm.quorumCheckpoint.Height(), sm.targetHeight)
+	}
+
+	checkpoints := make([]*pb.CheckpointState, 0, len(sm.epochChanges)+1)
+	prevHeight, prevEpoch := sm.curHeight-1, uint64(0)
+	for _, epc := range sm.epochChanges {
+		ckpt := epc.GetCheckpoint().GetCheckpoint()
+		if ckpt == nil {
+			return nil, errors.New("epoch change checkpoint is nil")
+		}
+		if ckpt.Height() <= prevHeight || ckpt.Height() > sm.targetHeight {
+			return nil, fmt.Errorf("epoch change checkpoint height %d is out of range (%d, %d]", ckpt.Height(), prevHeight, sm.targetHeight)
+		}
+		if ckpt.Epoch < prevEpoch {
+			return nil, fmt.Errorf("epoch change checkpoint epoch %d is less than previous epoch %d", ckpt.Epoch, prevEpoch)
+		}
+		if ckpt.Digest() == "" {
+			return nil, fmt.Errorf("epoch change checkpoint digest is empty, height: %d", ckpt.Height())
+		}
+		checkpoints = append(checkpoints, &pb.CheckpointState{Height: ckpt.Height(), Digest: ckpt.Digest()})
+		prevHeight, prevEpoch = ckpt.Height(), ckpt.Epoch
+	}
+
+	if sm.quorumCheckpoint.GetCheckpoint().Epoch < prevEpoch {
+		return nil, fmt.Errorf("quorum checkpoint epoch %d is less than epoch change epoch %d", sm.quorumCheckpoint.GetCheckpoint().Epoch, prevEpoch)
+	}
+	if sm.quorumCheckpoint.Digest() == "" {
+		return nil, errors.New("quorum checkpoint digest is empty")
+	}
+	if prevHeight == sm.targetHeight {
+		if checkpoints[len(checkpoints)-1].Digest != sm.quorumCheckpoint.Digest() {
+			return nil, fmt.Errorf("epoch change checkpoint is not equal to quorum checkpoint:[height:%d epoch change hash:%s, quorum hash:%s]",
+				prevHeight, checkpoints[len(checkpoints)-1].Digest, sm.quorumCheckpoint.Digest())
+		}
+		return checkpoints, nil
+	}
+	return append(checkpoints, &pb.CheckpointState{Height: sm.quorumCheckpoint.Height(), Digest: sm.quorumCheckpoint.Digest()}), nil
+}
+
+func (sm *SyncManager) startHeaderFirstSync(syncCount uint64, startTime time.Time, syncTaskDoneCh chan error) {
+	finish := func(err error) {
+		if stopErr := sm.stopSync(); stopErr != nil {
+			sm.logger.WithFields(logrus.Fields{
+				"err": stopErr,
+			}).Error("Stop sync failed")
+		}
+		syncTaskDoneCh <- err
+	}
+
+	hs, err := sm.newHeaderFirstSync()
+	if err != nil {
+		sm.logger.WithFields(logrus.Fields{
+			"err": err,
+		}).Error("Prepare header first sync failed")
+		finish(err)
+		return
+	}
+	sm.logger.WithFields(logrus.Fields{
+		"start":       sm.curHeight,
+		"target":      sm.targetHeight,
+		"checkpoints": len(hs.checkpoints),
+		"peers":       len(hs.peers),
+		"window":      hs.window,
+	}).Info("Start header first sync")
+
+	go hs.headerFetcher.listen(hs.ctx)
+	go hs.bodyFetcher.listen(hs.ctx)
+	go hs.fetchHeaders()
+	go hs.fetchBodies()
+	go hs.commit()
+
+	for {
+		select {
+		case <-hs.ctx.Done():
+			return
+		case ev := <-sm.recvEventCh:
+			if ev.EventType == common.EventType_GetSyncProgress {
+				req, ok := ev.Event.(*common.GetSyncProgressReq)
+				if !ok {
+					sm.logger.Errorf("invalid event type: %v", ev)
+					continue
+				}
+				progress := sm.handleSyncProgress()
+				progress.CurrentSyncHeight = hs.nextHeight.Load()
+				req.Resp <- progress
+			}
+		case err = <-hs.errCh:
+			sm.logger.WithFields(logrus.Fields{
+				"err": err,
+			}).Error("Header first sync failed")
+			finish(err)
+			return
+		case <-hs.doneCh:
+			sm.logger.WithFields(logrus.Fields{
+				"count":  syncCount,
+				"target": sm.targetHeight,
+				"elapse": time.Since(startTime).Seconds(),
+			}).Info("Block sync done")
+			blockSyncDuration.WithLabelValues(strconv.Itoa(int(syncCount))).Observe(time.Since(startTime).Seconds())
+			finish(nil)
+			return
+		}
+	}
+}
+
+func (hs *headerFirstSync) fail(err error) {
+	select {
+	case hs.errCh <- err:
+	default:
+	}
+}
+
+func (hs *headerFirstSync) notifyProgress() {
+	select {
+	case hs.progressCh <- struct{}{}:
+	default:
+	}
+}
+
+// startWorkers starts PeerConcurrency workers for every peer to fetch the heights in the queue,
+// the height failed is put back into the queue to be fetched by other workers.
+func (hs *headerFirstSync) startWorkers(queue chan uint64, fetch func(p *fetchPeer, height uint64) error) {
+	concurrency := hs.sm.conf.PeerConcurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	for _, p := range hs.peers {
+		for i := uint64(0); i < concurrency; i++ {
+			go func(p *fetchPeer) {
+				for !p.removed.Load() {
+					select {
+					case <-hs.ctx.Done():
+						return
+					case height := <-queue:
+						if err := fetch(p, height); err != nil {
+							if hs.ctx.Err() != nil {
+								return
+							}
+							hs.logger.WithFields(logrus.Fields{
+								"peer":   p.Id,
+								"height": height,
+								"err":    err,
+							}).Warning("Fetch failed, retry it")
+							hs.addFailure(p)
+							select {
+							case <-hs.ctx.Done():
+								return
+							case queue <- height:
+							}
+						}
+					}
+				}
+			}(p)
+		}
+	}
+}
+
+func (hs *headerFirstSync) available() int {
+	hs.peerLock.Lock()
+	defer hs.peerLock.Unlock()
+	return hs.availablePeers
+}
+
+// addFailure removes the peer whose failures reach TimeoutCountLimit, except the last available one.
+func (hs *headerFirstSync) addFailure(p *fetchPeer) {
+	if p.failures.Add(1) < hs.sm.conf.TimeoutCountLimit {
+		return
+	}
+	hs.peerLock.Lock()
+	defer hs.peerLock.Unlock()
+	if p.removed.Load() || hs.availablePeers == 1 {
+		return
+	}
+	p.removed.Store(true)
+	hs.availablePeers--
+	hs.logger.WithFields(logrus.Fields{
+		"peer":     p.Id,
+		"failures": p.failures.Load(),
+	}).Warning("Remove peer which fails too many times")
+}
+
+func (hs *headerFirstSync) fetchHeaders() {
+	queue := make(chan uint64, hs.window)
+	fetchedCh := make(chan *fetchedHeader, hs.window)
+	hs.startWorkers(queue, func(p *fetchPeer, height uint64) error {
+		resp, err := hs.headerFetcher.fetch(hs.ctx, p.PeerID, height, hs.timeout)
+		if err != nil {
+			return err
+		}
+		header := &types.BlockHeader{}
+		if err = header.Unmarshal(resp.(*pb.SyncBlockResponse).GetBlock()); err != nil {
+			return fmt.Errorf("unmarshal header failed: %w", err)
+		}
+		if header.Number != height {
+			return fmt.Errorf("receive header of height %d", header.Number)
+		}
+		select {
+		case <-hs.ctx.Done():
+			return hs.ctx.Err()
+		case fetchedCh <- &fetchedHeader{peer: p, header: header}:
+		}
+		return nil
+	})
+
+	parentHash := hs.sm.latestCheckedState.Digest
+	low := hs.sm.curHeight
+	for _, ckpt := range hs.checkpoints {
+		start := time.Now()
+		hashes, err := hs.linkHeaders(queue, fetchedCh, low, ckpt, parentHash)
+		if err != nil {
+			hs.fail(err)
+			return
+		}
+
+		hs.hashLock.Lock()
+		for i, hash := range hashes {
+			hs.hashes[low+uint64(i)] = hash
+		}
+		hs.hashLock.Unlock()
+		hs.verifiedHeight.Store(ckpt.Height)
+		hs.notifyProgress()
+
+		hs.logger.WithFields(logrus.Fields{
+			"start":  low,
+			"target": ckpt.Height,
+			"cost":   time.Since(start),
+		}).Info("Headers are verified by checkpoint")
+		low = ckpt.Height + 1
+		parentHash = ckpt.Digest
+	}
+}
+
+// linkHeaders fetches the headers in [low, checkpoint height] in batches backward from the checkpoint,
+// every header must have the hash referenced by its child, and the header at low must reference the parent hash.
+func (hs *headerFirstSync) linkHeaders(queue chan uint64, fetchedCh chan *fetchedHeader, low uint64,
+	ckpt *pb.CheckpointState, parentHash string) ([]string, error) {
+	hashes := make([]string, ckpt.Height-low+1)
+	expect := ckpt.Digest
+	high := ckpt.Height
+	for {
+		batchLow := low
+		if high-low+1 > hs.window {
+			batchLow = high - hs.window + 1
+		}
+		for height := batchLow; height <= high; height++ {
+			select {
+			case <-hs.ctx.Done():
+				return nil, hs.ctx.Err()
+			case queue <- height:
+			}
+		}
+
+		headers := make(map[uint64]*fetchedHeader)
+		mismatchedPeers := make(map[string]struct{})
+		for height := high; height >= batchLow; {
+			f, ok := headers[height]
+			if !ok {
+				select {
+				case <-hs.ctx.Done():
+					return nil, hs.ctx.Err()
+				case f = <-fetchedCh:
+					headers[f.header.Number] = f
+				}
+				continue
+			}
+
+			if f.header.Hash().String() != expect || f.header.ParentHash == nil {
+				hs.logger.WithFields(logrus.Fields{
+					"height":      height,
+					"peer":        f.peer.Id,
+					"expect hash": expect,
+					"actual hash": f.header.Hash().String(),
+				}).Warning("Header hash is not equal to the hash referenced by its child")
+				invalidBlockNumber.WithLabelValues("invalid_header").Inc()
+				// if all available peers have the same mismatched header, the checkpoint is wrong
+				mismatchedPeers[f.peer.PeerID] = struct{}{}
+				if len(mismatchedPeers) >= hs.available() {
+					return nil, fmt.Errorf("checkpoint is not equal to the header hash of all peers:[height:%d checkpoint height:%d, "+
+						"expect hash:%s, header hash:%s]", height, ckpt.Height, expect, f.header.Hash().String())
+				}
+				hs.addFailure(f.peer)
+				delete(headers, height)
+				select {
+				case <-hs.ctx.Done():
+					return nil, hs.ctx.Err()
+				case queue <- height:
+				}
+				continue
+			}
+			hashes[height-low] = expect
+			expect = f.header.ParentHash.String()
+			mismatchedPeers = make(map[string]struct{})
+			if height == batchLow {
+				break
+			}
+			height--
+		}
+
+		if batchLow == low {
+			break
+		}
+		high = batchLow - 1
+	}
+
+	if expect != parentHash {
+		return nil, fmt.Errorf("header chain is not linked to the local block:[height:%d local hash:%s, parent hash:%s]",
+			low-1, parentHash, expect)
+	}
+	return hashes, nil
+}
+
+func (hs *headerFirstSync) verifiedHash(height uint64) string {
+	hs.hashLock.RLock()
+	defer hs.hashLock.RUnlock()
+	return hs.hashes[height]
+}
+
+func (hs *headerFirstSync) fetchBodies() {
+	queue := make(chan uint64, hs.window)
+	hs.startWorkers(queue, func(p *fetchPeer, height uint64) error {
+		resp, err := hs.bodyFetcher.fetch(hs.ctx, p.PeerID, height, hs.timeout)
+		if err != nil {
+			if errors.Is(err, errFetchTimeout) {
+				invalidBlockNumber.WithLabelValues("timeout_response").Inc()
+			}
+			return err
+		}
+		commitData, err := decodeCommitData(hs.sm.mode, resp)
+		if err != nil {
+			return err
+		}
+		if commitData.GetHeight() != height || commitData.GetHash() != hs.verifiedHash(height) {
+			invalidBlockNumber.WithLabelValues("invalid_block").Inc()
+			return fmt.Errorf("block hash is not equal to verified header hash:[height:%d verified hash:%s, block hash:%s]",
+				height, hs.verifiedHash(height), commitData.GetHash())
+		}
+		if err = hs.sm.validateBlockBody(commitData); err != nil {
+			invalidBlockNumber.WithLabelValues("invalid_block").Inc()
+			return err
+		}
+		select {
+		case <-hs.ctx.Done():
+			return hs.ctx.Err()
+		case hs.bodyCh <- commitData:
+		}
+		return nil
+	})
+
+	for height := hs.sm.curHeight; height <= hs.sm.targetHeight; height++ {
+		for height > hs.verifiedHeight.Load() || height >= hs.nextHeight.Load()+hs.window {
+			select {
+			case <-hs.ctx.Done():
+				return
+			case <-hs.progressCh:
+			}
+		}
+		select {
+		case <-hs.ctx.Done():
+			return
+		case queue <- height:
+		}
+	}
+}
+
+// batchEnd returns the end height of the batch starting from the height, which is cut at checkpoints and batchSize.
+func (hs *headerFirstSync) batchEnd(height uint64) uint64 {
+	end := height + hs.batchSize - 1
+	for _, ckpt := range hs.checkpoints {
+		if ckpt.Height >= height {
+			if ckpt.Height < end {
+				end = ckpt.Height
+			}
+			break
+		}
+	}
+	return end
+}
+
+func (hs *headerFirstSync) commit() {
+	pending := make(map[uint64]common.CommitData)
+	next := hs.sm.curHeight
+	ready := next - 1
+	for {
+		select {
+		case <-hs.ctx.Done():
+			return
+		case commitData := <-hs.bodyCh:
+			pending[commitData.GetHeight()] = commitData
+		}
+		for pending[ready+1] != nil {
+			ready++
+		}
+
+		for end := hs.batchEnd(next); ready >= end; end = hs.batchEnd(next) {
+			batch := make([]common.CommitData, 0, end-next+1)
+			for height := next; height <= end; height++ {
+				batch = append(batch, pending[height])
+				delete(pending, height)
+			}
+
+			start := time.Now()
+			hs.sm.modeConstructor.PostCommitData(batch)
+			pushBlock2ExecutorDuration.WithLabelValues(strconv.Itoa(len(batch))).Observe(time.Since(start).Seconds())
+			hs.logger.WithFields(logrus.Fields{
+				"start":  next,
+				"target": end,
+			}).Info("Post verified blocks to executor")
+
+			hs.hashLock.Lock()
+			for height := next; height <= end; height++ {
+				delete(hs.hashes, height)
+			}
+			hs.hashLock.Unlock()
+			next = end + 1
+			hs.nextHeight.Store(next)
+			hs.notifyProgress()
+
+			if end == hs.sm.targetHeight {
+				close(hs.doneCh)
+				return
+			}
+		}
+	}
+}
+
+func decodeCommitData(mode common.SyncMode, resp common.SyncResponseMessage) (common.CommitData, error) {
+	block := &types.Block{}
+	switch mode {
+	case common.SyncModeFull:
+		blockResp, ok := resp.(*pb.SyncBlockResponse)
+		if !ok {
+			return nil, errors.New("convert sync block response failed")
+		}
+		if err := block.Unmarshal(blockResp.GetBlock()); err != nil {
+			return nil, fmt.Errorf("unmarshal block failed: %w", err)
+		}
+		if block.Header == nil {
+			return nil, errors.New("block header is nil")
+		}
+		return &common.BlockData{Block: block}, nil
+	case common.SyncModeSnapshot:
+		chainResp, ok := resp.(*pb.SyncChainDataResponse)
+		if !ok {
+			return nil, errors.New("convert sync chainData response failed")
+		}
+		if err := block.Unmarshal(chainResp.GetBlock()); err != nil {
+			return nil, fmt.Errorf("unmarshal block failed: %w", err)
+		}
+		if block.Header == nil {
+			return nil, errors.New("block header is nil")
+		}
+		receipts, err := types.UnmarshalReceipts(chainResp.GetReceipts())
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal receipts failed: %w", err)
+		}
+		return &common.ChainData{Block: block, Receipts: receipts}, nil
+	}
+	return nil, fmt.Errorf("invalid sync mode: %d", mode)
+}
+
+func (sm *SyncManager) listenSyncHeaderRequest() {
+	for {
+		msg := sm.headerRequestPipe.Receive(sm.ctx)
+		if msg == nil {
+			sm.logger.Info("Stop listen sync header request")
+			return
+		}
+
+		data, height, err := sm.handleHeaderRequest(msg)
+		if err != nil {
+			sm.logger.Errorf("Handle sync header request failed: %s", err)
+			continue
+		}
+		if err = sm.sendPipeResponse(sm.headerResponsePipe, msg.From, data, height); err != nil {
+			sm.logger.Errorf("Send sync header response failed: %s", err)
+			continue
+		}
+	}
+}
+
+// handleHeaderRequest responds the marshaled header in the block field of SyncBlockResponse.
+func (sm *SyncManager) handleHeaderRequest(msg *network2.PipeMsg) ([]byte, uint64, error) {
+	req := &pb.SyncBlockRequest{}
+	if err := req.UnmarshalVT(msg.Data); err != nil {
+		return nil, 0, fmt.Errorf("unmarshal sync header request failed: %w", err)
+	}
+
+	resp := &pb.SyncBlockResponse{Height: req.Height, Status: pb.Status_SUCCESS}
+	header, err := sm.getBlockHeaderFunc(req.Height)
+	if err == nil {
+		resp.Block, err = header.Marshal()
+	}
+	if err != nil {
+		sm.logger.WithFields(logrus.Fields{
+			"from":   msg.From,
+			"height": req.Height,
+			"err":    err,
+		}).Error("Get header failed")
+		resp.Block = nil
+		resp.Status = pb.Status_ERROR
+		resp.Error = err.Error()
+	}
+
+	data, err := resp.MarshalVT()
+	if err != nil {
+		return nil, 0, err
+	}
+	p2pMsg := &pb.Message{
+		From: sm.network.PeerID(),
+		Type: pb.Message_SYNC_BLOCK_RESPONSE,
+		Data: data,
+	}
+	msgData, err := p2pMsg.MarshalVT()
+	return msgData, req.Height, err
+}
@@ -0,0 +1,361 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomesh/axiom-bft/common/consensus"
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-kit/types/pb"
+	"github.com/axiomesh/axiom-ledger/internal/sync/common"
+	"github.com/axiomesh/axiom-ledger/pkg/loggers"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+func newTestCheckpoint(epoch, height uint64, digest string) *consensus.Checkpoint {
+	return &consensus.Checkpoint{
+		Epoch: epoch,
+		ExecuteState: &consensus.Checkpoint_ExecuteState{
+			Height: height,
+			Digest: digest,
+		},
+	}
+}
+
+func newTestEpochChange(epoch, height uint64, digest string) *consensus.EpochChange {
+	return &consensus.EpochChange{
+		Checkpoint: &consensus.QuorumCheckpoint{
+			Checkpoint: newTestCheckpoint(epoch, height, digest),
+		},
+	}
+}
+
+func TestValidateCheckpoints(t *testing.T) {
+	testCases := []struct {
+		name         string
+		quorum       *consensus.SignedCheckpoint
+		epochChanges []*consensus.EpochChange
+		expectErr    string
+		expect       []uint64
+	}{
+		{
+			name:   "only quorum checkpoint",
+			quorum: &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(1, 300, "hash300")},
+			expect: []uint64{300},
+		},
+		{
+			name:         "epoch change at target height",
+			quorum:       &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(3, 300, "hash300")},
+			epochChanges: []*consensus.EpochChange{newTestEpochChange(1, 100, "hash100"), newTestEpochChange(2, 200, "hash200"), newTestEpochChange(3, 300, "hash300")},
+			expect:       []uint64{100, 200, 300},
+		},
+		{
+			name:         "epoch change below target height",
+			quorum:       &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(3, 300, "hash300")},
+			epochChanges: []*consensus.EpochChange{newTestEpochChange(1, 100, "hash100"), newTestEpochChange(2, 200, "hash200")},
+			expect:       []uint64{100, 200, 300},
+		},
+		{
+			name:      "nil quorum checkpoint",
+			expectErr: "quorum checkpoint is nil",
+		},
+		{
+			name:      "quorum checkpoint is not target",
+			quorum:    &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(1, 299, "hash299")},
+			expectErr: "is not equal to target height",
+		},
+		{
+			name:         "epoch change below current height",
+			quorum:       &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(1, 300, "hash300")},
+			epochChanges: []*consensus.EpochChange{newTestEpochChange(1, 1, "hash1")},
+			expectErr:    "is out of range",
+		},
+		{
+			name:         "epoch change height not increasing",
+			quorum:       &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(2, 300, "hash300")},
+			epochChanges: []*consensus.EpochChange{newTestEpochChange(1, 200, "hash200"), newTestEpochChange(2, 100, "hash100")},
+			expectErr:    "is out of range",
+		},
+		{
+			name:         "epoch change epoch decreasing",
+			quorum:       &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(2, 300, "hash300")},
+			epochChanges: []*consensus.EpochChange{newTestEpochChange(2, 100, "hash100"), newTestEpochChange(1, 200, "hash200")},
+			expectErr:    "is less than previous epoch",
+		},
+		{
+			name:         "quorum checkpoint epoch decreasing",
+			quorum:       &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(1, 300, "hash300")},
+			epochChanges: []*consensus.EpochChange{newTestEpochChange(2, 100, "hash100")},
+			expectErr:    "is less than epoch change epoch",
+		},
+		{
+			name:         "epoch change conflicts with quorum checkpoint",
+			quorum:       &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(1, 300, "hash300")},
+			epochChanges: []*consensus.EpochChange{newTestEpochChange(1, 300, "wrong hash")},
+			expectErr:    "epoch change checkpoint is not equal to quorum checkpoint",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sm := &SyncManager{
+				curHeight:        2,
+				targetHeight:     300,
+				quorumCheckpoint: tc.quorum,
+				epochChanges:     tc.epochChanges,
+			}
+			checkpoints, err := sm.validateCheckpoints()
+			if tc.expectErr != "" {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tc.expectErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, len(tc.expect), len(checkpoints))
+			for i, height := range tc.expect {
+				require.Equal(t, height, checkpoints[i].Height)
+			}
+		})
+	}
+}
+
+func TestHeaderFirstSync(t *testing.T) {
+	n := 4
+	syncs, ledgers, genesisHash := newMockBlockSyncs(t, n)
+	defer stopSyncs(syncs)
+	syncs[0].conf.HeaderFirst = true
+	syncs[0].conf.PeerConcurrency = 5
+
+	localId := "0"
+	remoteId := "1"
+	prepareLedger(t, ledgers, localId, 300, genesisHash)
+
+	// mock wrong blocks in different epochs of different peers
+	for id, height := range map[string]uint64{"2": 7, "3": 150} {
+		parentBlock, err := ledgers[remoteId].GetBlock(height - 1)
+		require.Nil(t, err)
+		wrongBlock := &types.Block{
+			Header: &types.BlockHeader{
+				Number:     height,
+				ParentHash: parentBlock.Hash(),
+			},
+		}
+		err = ledgers[id].PersistExecutionResult(wrongBlock, genReceipts(wrongBlock))
+		require.Nil(t, err)
+	}
+
+	for i := 0; i < n; i++ {
+		_, err := syncs[i].Prepare()
+		require.Nil(t, err)
+		syncs[i].Start()
+	}
+
+	peers := []*common.Node{{Id: 1, PeerID: "1"}, {Id: 2, PeerID: "2"}, {Id: 3, PeerID: "3"}}
+	latestBlockHash := ledgers[localId].GetChainMeta().BlockHash.String()
+	var epochChanges []*consensus.EpochChange
+	for _, height := range []uint64{100, 200, 300} {
+		block, err := ledgers[remoteId].GetBlock(height)
+		require.Nil(t, err)
+		epochChanges = append(epochChanges, newTestEpochChange(0, height, block.Hash().String()))
+	}
+	quorumCkpt300 := &consensus.SignedCheckpoint{
+		Checkpoint: newTestCheckpoint(0, 300, ledgers[remoteId].GetChainMeta().BlockHash.String()),
+	}
+
+	syncTaskDoneCh := make(chan error, 1)
+	err := syncs[0].StartSync(genSyncParams(peers, latestBlockHash, 2, 2, 300, quorumCkpt300, epochChanges...), syncTaskDoneCh)
+	require.Nil(t, err)
+	err = waitSyncTaskDone(syncTaskDoneCh)
+	require.Nil(t, err)
+
+	// the batches are cut at epoch changes, and all blocks are the right ones
+	expectHeight := uint64(2)
+	for _, end := range []uint64{100, 200, 300} {
+		waitCommitData(t, syncs[0].Commit(), func(t *testing.T, data any) {
+			blocks := data.([]common.CommitData)
+			require.Equal(t, end, blocks[len(blocks)-1].GetHeight())
+			for _, commitData := range blocks {
+				block, err := ledgers[remoteId].GetBlock(expectHeight)
+				require.Nil(t, err)
+				require.Equal(t, expectHeight, commitData.GetHeight())
+				require.Equal(t, block.Hash().String(), commitData.GetHash())
+				expectHeight++
+			}
+		})
+	}
+	require.False(t, syncs[0].syncStatus.Load())
+}
+
+func TestHeaderFirstSyncWithSnapshotMode(t *testing.T) {
+	n := 4
+	syncs, ledgers, genesisHash := newMockBlockSyncs(t, n)
+	defer stopSyncs(syncs)
+	syncs[0].conf.HeaderFirst = true
+
+	localId := "0"
+	remoteId := "1"
+	prepareLedger(t, ledgers, localId, 300, genesisHash)
+
+	peers := []*common.Node{{Id: 1, PeerID: "1"}, {Id: 2, PeerID: "2"}, {Id: 3, PeerID: "3"}}
+	latestBlockHash := ledgers[localId].GetChainMeta().BlockHash.String()
+	quorumCkpt300 := &consensus.SignedCheckpoint{
+		Checkpoint: newTestCheckpoint(3, 300, ledgers[remoteId].GetChainMeta().BlockHash.String()),
+	}
+
+	err := syncs[0].SwitchMode(common.SyncModeSnapshot)
+	require.Nil(t, err)
+	data, err := syncs[0].Prepare(common.WithPeers(peers),
+		common.WithStartEpochChangeNum(1),
+		common.WithLatestPersistEpoch(0),
+		common.WithSnapCurrentEpoch(3),
+	)
+	require.Nil(t, err)
+	epcs := data.Data.([]*consensus.EpochChange)
+
+	for i := 1; i < n; i++ {
+		_, err = syncs[i].Prepare()
+		require.Nil(t, err)
+		syncs[i].Start()
+	}
+
+	syncTaskDoneCh := make(chan error, 1)
+	err = syncs[0].StartSync(genSyncParams(peers, latestBlockHash, 2, 2, 300, quorumCkpt300, epcs...), syncTaskDoneCh)
+	require.Nil(t, err)
+	err = waitSyncTaskDone(syncTaskDoneCh)
+	require.Nil(t, err)
+
+	for _, end := range []uint64{100, 200, 300} {
+		waitCommitData(t, syncs[0].Commit(), func(t *testing.T, chainData any) {
+			snapData := chainData.(*common.SnapCommitData)
+			require.Equal(t, end, snapData.EpochState.Checkpoint.Height())
+			require.Equal(t, end, snapData.Data[len(snapData.Data)-1].GetHeight())
+			_, ok := snapData.Data[0].(*common.ChainData)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestHeaderFirstSyncWithWrongCheckpoint(t *testing.T) {
+	testCases := []struct {
+		name        string
+		wrongHeight uint64
+	}{
+		{
+			name:        "wrong epoch change checkpoint",
+			wrongHeight: 100,
+		},
+		{
+			name:        "wrong quorum checkpoint",
+			wrongHeight: 150,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := 4
+			syncs, ledgers, genesisHash := newMockBlockSyncs(t, n)
+			defer stopSyncs(syncs)
+			syncs[0].conf.HeaderFirst = true
+
+			localId := "0"
+			remoteId := "1"
+			prepareLedger(t, ledgers, localId, 150, genesisHash)
+			for i := 0; i < n; i++ {
+				_, err := syncs[i].Prepare()
+				require.Nil(t, err)
+				syncs[i].Start()
+			}
+
+			digest := func(height uint64) string {
+				if height == tc.wrongHeight {
+					return "wrong digest"
+				}
+				block, err := ledgers[remoteId].GetBlock(height)
+				require.Nil(t, err)
+				return block.Hash().String()
+			}
+			peers := []*common.Node{{Id: 1, PeerID: "1"}, {Id: 2, PeerID: "2"}, {Id: 3, PeerID: "3"}}
+			latestBlockHash := ledgers[localId].GetChainMeta().BlockHash.String()
+			quorumCkpt150 := &consensus.SignedCheckpoint{Checkpoint: newTestCheckpoint(0, 150, digest(150))}
+
+			syncTaskDoneCh := make(chan error, 1)
+			err := syncs[0].StartSync(genSyncParams(peers, latestBlockHash, 2, 2, 150, quorumCkpt150, newTestEpochChange(0, 100, digest(100))), syncTaskDoneCh)
+			require.Nil(t, err)
+			err = waitSyncTaskDone(syncTaskDoneCh)
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "checkpoint is not equal to the header hash of all peers")
+			require.False(t, syncs[0].syncStatus.Load())
+		})
+	}
+}
+
+func newTestHeaderFirstSync(ctx context.Context, window uint64) *headerFirstSync {
+	return &headerFirstSync{
+		sm: &SyncManager{
+			conf: repo.Sync{PeerConcurrency: 1, TimeoutCountLimit: 10},
+		},
+		ctx:            ctx,
+		logger:         loggers.Logger(loggers.BlockSync),
+		window:         window,
+		peers:          []*fetchPeer{{Peer: &common.Peer{Id: 1, PeerID: "1"}}},
+		availablePeers: 1,
+		progressCh:     make(chan struct{}, 1),
+		bodyCh:         make(chan common.CommitData, window),
+		errCh:          make(chan error, 1),
+	}
+}
+
+func TestHeaderFirstSyncStopWithFullQueue(t *testing.T) {
+	t.Run("worker", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		hs := newTestHeaderFirstSync(ctx, 1)
+		goroutines := runtime.NumGoroutine()
+
+		queue := make(chan uint64, 1)
+		fetching := make(chan uint64)
+		release := make(chan struct{})
+		hs.startWorkers(queue, func(p *fetchPeer, height uint64) error {
+			fetching <- height
+			<-release
+			return errors.New("fetch failed")
+		})
+		queue <- 1
+		require.Equal(t, uint64(1), <-fetching)
+		// the failed height can not be put back into the full queue
+		queue <- 2
+		close(release)
+		time.Sleep(100 * time.Millisecond)
+
+		cancel()
+		// Eventually is not used as it runs the condition in another goroutine
+		deadline := time.Now().Add(5 * time.Second)
+		for runtime.NumGoroutine() > goroutines {
+			require.True(t, time.Now().Before(deadline), "worker is blocked by the full queue")
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("link headers", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		hs := newTestHeaderFirstSync(ctx, 10)
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := hs.linkHeaders(make(chan uint64, 1), make(chan *fetchedHeader), 1, &pb.CheckpointState{Height: 10}, "")
+			errCh <- err
+		}()
+
+		cancel()
+		select {
+		case err := <-errCh:
+			require.ErrorIs(t, err, context.Canceled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("link headers is blocked by the full queue")
+		}
+	})
+}
@@ -48,6 +48,7 @@ type SyncManager struct {
 	startHeight         uint64              // startHeight
 	curHeight           uint64              // current commitData which we need sync
 	targetHeight        uint64              // sync target commitData height
+	resumed             bool                // whether the sync is resumed from persisted progress
 	recvBlockSize       atomic.Int64        // current chunk had received commitData size
 	latestCheckedState  *pb.CheckpointState // latest checked commitData state
 	requesters          sync.Map            // requester map
@@ -67,6 +68,8 @@ type SyncManager struct {
 	chainDataResponsePipe network.Pipe
 	blockRequestPipe      network.Pipe
 	blockDataResponsePipe network.Pipe
+	headerRequestPipe     network.Pipe
+	headerResponsePipe    network.Pipe
 
 	commitDataCache []common.CommitData // store commitData of a chunk temporary
 
@@ -139,6 +142,19 @@ func NewSyncManager(logger logrus.FieldLogger, getChainMetaFn func() *types.Chai
 	}
 	syncMgr.chainDataResponsePipe = chainRespPipe
 
+	// init sync header pipe
+	headerReqPipe, err := syncMgr.network.CreatePipe(syncMgr.ctx, common.SyncHeaderRequestPipe)
+	if err != nil {
+		return nil, err
+	}
+	syncMgr.headerRequestPipe = headerReqPipe
+
+	headerRespPipe, err := syncMgr.network.CreatePipe(syncMgr.ctx, common.SyncHeaderResponsePipe)
+	if err != nil {
+		return nil, err
+	}
+	syncMgr.headerResponsePipe = headerRespPipe
+
 	// init syncStatus
 	syncMgr.syncStatus.Store(false)
 
@@ -165,6 +181,7 @@ func (sm *SyncManager) StartSync(params *common.SyncParams, syncTaskDoneCh chan
 
 	// 2. update commitData sync info
 	sm.InitBlockSyncInfo(activePeers, params.LatestBlockHash, params.Quorum, params.CurHeight, params.TargetHeight, params.QuorumCheckpoint, params.EpochChanges...)
+	sm.resumed = params.Resumed
 
 	// 3. send sync state request to all validators, waiting for Quorum response
 	if params.LatestBlockHash != (ethcommon.Hash{}).String() {
@@ -185,6 +202,12 @@ func (sm *SyncManager) StartSync(params *common.SyncParams, syncTaskDoneCh chan
 		return err
 	}
 
+	// in header first mode, download headers, bodies and commit blocks in pipeline
+	if sm.conf.HeaderFirst {
+		go sm.startHeaderFirstSync(syncCount, now, syncTaskDoneCh)
+		return nil
+	}
+
 	// 5. start listen sync commitData response
 	go sm.listenSyncCommitDataResponse()
 
@@ -287,6 +310,7 @@ func (sm *SyncManager) handleSyncProgress() *common.SyncProgress {
 		CurrentSyncHeight: sm.curHeight,
 		TargetHeight:      sm.targetHeight,
 		SyncMode:          common.SyncModeMap[sm.mode],
+		Resumed:           sm.resumed,
 		Peers: lo.FlatMap(sm.peers, func(p *common.Peer, _ int) []common.Node {
 			return []common.Node{
 				{
@@ -984,6 +1008,10 @@ func (sm *SyncManager) sendCommitDataResponse(mode common.SyncMode, to string, r
 	case common.SyncModeSnapshot:
 		pipe = sm.chainDataResponsePipe
 	}
+	return sm.sendPipeResponse(pipe, to, respData, height)
+}
+
+func (sm *SyncManager) sendPipeResponse(pipe network.Pipe, to string, respData []byte, height uint64) error {
 	if err := retry.Retry(func(attempt uint) error {
 		err := pipe.Send(sm.ctx, to, respData)
 		if err != nil {
@@ -1286,6 +1314,9 @@ func (sm *SyncManager) Start() {
 
 	// start handle sync chain data request in snap mode
 	go sm.listenSyncChainDataRequest()
+
+	// start handle sync header request in header first mode
+	go sm.listenSyncHeaderRequest()
 	sm.logger.Info("Start listen sync request")
 
 	sm.modeConstructor.Start()
@@ -54,6 +54,8 @@ func TestNewSyncManager(t *testing.T) {
 		common.SyncBlockResponsePipe:     1,
 		common.SyncChainDataRequestPipe:  1,
 		common.SyncChainDataResponsePipe: 1,
+		common.SyncHeaderRequestPipe:     1,
+		common.SyncHeaderResponsePipe:    1,
 	}
 
 	net.EXPECT().CreatePipe(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, name string) (network2.Pipe, error) {
@@ -697,6 +699,7 @@ func TestHandleTimeoutBlockMsg(t *testing.T) {
 		require.True(t, progress.InSync)
 		require.False(t, progress.CatchUp)
 		require.Equal(t, uint64(2), progress.StartSyncBlock)
+		require.False(t, progress.Resumed)
 
 		err = waitSyncTaskDone(syncTaskDoneCh)
 		require.Nil(t, err)
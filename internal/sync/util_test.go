@@ -335,6 +335,31 @@ func (net *mockMiniNetwork) newMockBlockResponsePipe(nets map[string]*mockMiniNe
 	return mockPipe
 }
 
+func (net *mockMiniNetwork) newMockHeaderPipe(nets map[string]*mockMiniNetwork, ctrl *gomock.Controller, localId string, pipeDb func(*mockMiniNetwork) chan *network.PipeMsg) network.Pipe {
+	mockPipe := mock_network.NewMockPipe(ctrl)
+	mockPipe.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, to string, data []byte) error {
+			pipeDb(nets[to]) <- &network.PipeMsg{
+				From: localId,
+				Data: data,
+			}
+			return nil
+		}).AnyTimes()
+
+	ch := pipeDb(net)
+	mockPipe.EXPECT().Receive(gomock.Any()).DoAndReturn(
+		func(ctx context.Context) *network.PipeMsg {
+			select {
+			case <-ctx.Done():
+				return nil
+			case msg := <-ch:
+				return msg
+			}
+		}).AnyTimes()
+
+	return mockPipe
+}
+
 type pipeMsgIndexM struct {
 	lock           sync.RWMutex
 	blockReqCache  map[string]chan *network.PipeMsg
@@ -352,6 +377,8 @@ type mockMiniNetwork struct {
 	blockRespPipeDb     chan *network.PipeMsg
 	chainDataReqPipeDb  chan *network.PipeMsg
 	chainDataRespPipeDb chan *network.PipeMsg
+	headerReqPipeDb     chan *network.PipeMsg
+	headerRespPipeDb    chan *network.PipeMsg
 }
 
 func newMockMiniNetworks(n int) map[string]*mockMiniNetwork {
@@ -362,6 +389,8 @@ func newMockMiniNetworks(n int) map[string]*mockMiniNetwork {
 			blockRespPipeDb:     make(chan *network.PipeMsg, 1000),
 			chainDataReqPipeDb:  make(chan *network.PipeMsg, 1000),
 			chainDataRespPipeDb: make(chan *network.PipeMsg, 1000),
+			headerReqPipeDb:     make(chan *network.PipeMsg, 1000),
+			headerRespPipeDb:    make(chan *network.PipeMsg, 1000),
 		}
 		nets[strconv.Itoa(i)] = mock
 	}
@@ -432,6 +461,14 @@ func initMockMiniNetwork(t *testing.T, ledgers map[string]*mockLedger, nets map[
 					return mock.newMockBlockResponsePipe(nets, mode, ctrl, localId), nil
 				}
 				return mock.newMockBlockResponsePipe(nets, mode, ctrl, localId, wrong...), nil
+			case common.SyncHeaderRequestPipe:
+				return mock.newMockHeaderPipe(nets, ctrl, localId, func(net *mockMiniNetwork) chan *network.PipeMsg {
+					return net.headerReqPipeDb
+				}), nil
+			case common.SyncHeaderResponsePipe:
+				return mock.newMockHeaderPipe(nets, ctrl, localId, func(net *mockMiniNetwork) chan *network.PipeMsg {
+					return net.headerRespPipeDb
+				}), nil
 			default:
 				return nil, fmt.Errorf("invalid pipe id: %s", pipeID)
 			}
@@ -0,0 +1,377 @@
+package txpool
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/btree"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	commonpool "github.com/axiomesh/axiom-kit/txpool"
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+// the number of the recently batched bundle txs kept for the execution
+const bundleTxCacheSize = 10000
+
+// txBundle is a set of txs which must be placed consecutively in one batch or not at all.
+type txBundle[T any, Constraint types.TXConstraint[T]] struct {
+	id       string
+	txs      []*internalTransaction[T, Constraint]
+	maxBlock uint64
+}
+
+// AddBundle adds the txs as a bundle which is only kept in the txpool of this node, the bundle txs are placed
+// consecutively in the batch generated by this node or not at all, and the bundle is dropped once the chain
+// height reaches maxBlock. The bundle txs of the same account must have consecutive nonces.
+// If the batch is restored after a view change, the bundle txs are dropped instead of being batched one by one.
+func (p *txPoolImpl[T, Constraint]) AddBundle(txs []*T, maxBlock uint64) (string, error) {
+	req := &reqBundleTxs[T, Constraint]{
+		txs:      txs,
+		maxBlock: maxBlock,
+		respCh:   make(chan *respBundleTxs),
+	}
+	ev := &addTxsEvent{
+		EventType: bundleTxsEvent,
+		Event:     req,
+	}
+	p.postEvent(ev)
+
+	resp := <-req.respCh
+	return resp.bundleID, resp.err
+}
+
+// GetBundleID returns the bundle id of the tx recently batched by this node, the executor reads it
+// when the tx is executed to persist the bundle info with the block.
+func (p *txPoolImpl[T, Constraint]) GetBundleID(txHash string) (string, bool) {
+	return p.bundleTxIDs.Get(txHash)
+}
+
+func calcBundleID(hashes []string) string {
+	data := make([][]byte, 0, len(hashes))
+	for _, hash := range hashes {
+		data = append(data, types.NewHashByStr(hash).Bytes())
+	}
+	return types.NewHash(crypto.Keccak256(data...)).String()
+}
+
+func (p *txPoolImpl[T, Constraint]) handleAddBundle(txs []*T, maxBlock uint64) (string, error) {
+	if len(txs) == 0 {
+		return "", ErrBundleEmpty
+	}
+	if maxBlock <= p.getChainHeight() {
+		return "", errors.Wrapf(ErrBundleExpired, "max block: %d, current height: %d", maxBlock, p.getChainHeight())
+	}
+	if maxBlock > p.getChainHeight()+p.maxBundleBlockDistance {
+		return "", errors.Wrapf(ErrBundleTooFar, "max block: %d, current height: %d, max distance: %d", maxBlock, p.getChainHeight(), p.maxBundleBlockDistance)
+	}
+	if uint64(len(txs)) > p.chainState.EpochInfo.ConsensusParams.BlockMaxTxNum {
+		return "", errors.Wrapf(ErrBundleTooLarge, "bundle size: %d", len(txs))
+	}
+	if uint64(len(p.txStore.bundles)) >= p.maxBundles {
+		return "", errors.Wrapf(ErrBundleTooMany, "max bundles: %d", p.maxBundles)
+	}
+	// the bundle txs take the pool space before they are batched
+	if p.txStore.poolTxCount()+len(txs) > int(p.poolMaxSize) {
+		return "", ErrTxPoolFull
+	}
+
+	bundle := &txBundle[T, Constraint]{
+		txs:      make([]*internalTransaction[T, Constraint], 0, len(txs)),
+		maxBlock: maxBlock,
+	}
+	hashes := make([]string, 0, len(txs))
+	lastNonces := make(map[string]uint64)
+	for _, tx := range txs {
+		txHash := Constraint(tx).RbftGetTxHash()
+		account := Constraint(tx).RbftGetFrom()
+		nonce := Constraint(tx).RbftGetNonce()
+		if _, ok := p.txStore.txHashMap[txHash]; ok {
+			return "", errors.Wrapf(ErrBundleDuplicate, "tx %s is in txpool", txHash)
+		}
+		if _, ok := p.txStore.bundleTxs[txHash]; ok {
+			return "", errors.Wrapf(ErrBundleDuplicate, "tx %s is in another bundle", txHash)
+		}
+		if last, ok := lastNonces[account]; ok {
+			if nonce != last+1 {
+				return "", errors.Wrapf(ErrBundleNonceGap, "account %s, expected nonce %d, got %d", account, last+1, nonce)
+			}
+		} else if commitNonce := p.txStore.nonceCache.getCommitNonce(account); nonce < commitNonce {
+			return "", errors.Wrapf(ErrNonceTooLow, "account %s, commit nonce %d, got %d", account, commitNonce, nonce)
+		}
+		lastNonces[account] = nonce
+		hashes = append(hashes, txHash)
+		bundle.txs = append(bundle.txs, &internalTransaction[T, Constraint]{
+			rawTx:       tx,
+			local:       true,
+			lifeTime:    Constraint(tx).RbftGetTimeStamp(),
+			arrivedTime: time.Now().UnixNano(),
+			private:     true,
+			maxBlock:    maxBlock,
+			bundle:      true,
+		})
+	}
+	if err := p.checkBundleQuota(txs); err != nil {
+		return "", err
+	}
+	bundle.id = calcBundleID(hashes)
+
+	p.txStore.bundles = append(p.txStore.bundles, bundle)
+	for _, hash := range hashes {
+		p.txStore.bundleTxs[hash] = bundle
+	}
+	// trigger the batch timer to try to batch the bundle
+	p.setHasPendingRequest()
+	p.logger.WithFields(logrus.Fields{
+		"bundle":    bundle.id,
+		"size":      len(txs),
+		"max_block": maxBlock,
+	}).Info("Receive new bundle")
+	return bundle.id, nil
+}
+
+// checkBundleQuota checks whether the accounts have free slots for the bundle txs, the bundle txs will be
+// executable once they are batched, so they are counted as the pending txs of the accounts together with
+// the txs of the other bundles waiting to be batched.
+func (p *txPoolImpl[T, Constraint]) checkBundleQuota(txs []*T) error {
+	counts := make(map[string]uint64)
+	for _, tx := range txs {
+		counts[Constraint(tx).RbftGetFrom()]++
+	}
+	for _, bundle := range p.txStore.bundles {
+		for _, poolTx := range bundle.txs {
+			if _, ok := counts[poolTx.getAccount()]; ok {
+				counts[poolTx.getAccount()]++
+			}
+		}
+	}
+	for account, count := range counts {
+		commitNonce := p.txStore.nonceCache.getCommitNonce(account)
+		if pendingNonce := p.txStore.nonceCache.getPendingNonce(account); pendingNonce > commitNonce {
+			count += pendingNonce - commitNonce
+		}
+		if count > p.accountSlots {
+			return errors.Wrapf(ErrAccountSlotsExceeded, "account %s, pending and bundle txs: %d", account, count)
+		}
+	}
+	return nil
+}
+
+// fillBundles appends the executable bundles to the batch in arrived order until the batch is full,
+// returns the number of the bundle txs appended.
+func (p *txPoolImpl[T, Constraint]) fillBundles(txBatch *commonpool.RequestHashBatch[T, Constraint]) uint64 {
+	blockMaxTxNum := p.chainState.EpochInfo.ConsensusParams.BlockMaxTxNum
+	var filled uint64
+	remained := make([]*txBundle[T, Constraint], 0, len(p.txStore.bundles))
+	for _, bundle := range p.txStore.bundles {
+		if txBatch.BatchItemSize()+uint64(len(bundle.txs)) > blockMaxTxNum {
+			remained = append(remained, bundle)
+			continue
+		}
+		executable, err := p.checkBundleExecutable(bundle)
+		if err != nil {
+			p.logger.WithFields(logrus.Fields{
+				"bundle": bundle.id,
+				"err":    err,
+			}).Warning("Drop invalid bundle")
+			p.removeBundle(bundle, DropReasonInvalid)
+			continue
+		}
+		if !executable {
+			remained = append(remained, bundle)
+			continue
+		}
+		p.batchBundle(bundle, txBatch)
+		filled += uint64(len(bundle.txs))
+	}
+	p.txStore.bundles = remained
+	return filled
+}
+
+// checkBundleExecutable checks whether all the bundle txs are the next txs of their accounts,
+// the ready txs of the accounts in the pool must be batched before the bundle.
+func (p *txPoolImpl[T, Constraint]) checkBundleExecutable(bundle *txBundle[T, Constraint]) (bool, error) {
+	nextNonces := make(map[string]uint64)
+	for _, poolTx := range bundle.txs {
+		account := poolTx.getAccount()
+		nonce := poolTx.getNonce()
+		next, ok := nextNonces[account]
+		if !ok {
+			next = p.txStore.nonceCache.getPendingNonce(account)
+			for n := p.txStore.nonceCache.getCommitNonce(account); n < next; n++ {
+				if !p.txStore.batchedTxs[txPointer{account: account, nonce: n}] {
+					return false, nil
+				}
+			}
+		}
+		if nonce < next {
+			return false, errors.Wrapf(ErrNonceTooLow, "account %s, pending nonce %d, got %d", account, next, nonce)
+		}
+		if nonce > next {
+			return false, nil
+		}
+		// wait for the queued tx with the same nonce to be removed
+		if p.txStore.getPoolTxByTxnPointer(account, nonce) != nil {
+			return false, nil
+		}
+		if _, ok = p.txStore.txHashMap[poolTx.getHash()]; ok {
+			return false, errors.Wrapf(ErrDuplicateTx, "tx %s is in txpool", poolTx.getHash())
+		}
+		if err := p.validateTxData(poolTx.rawTx); err != nil {
+			return false, err
+		}
+		nextNonces[account] = next + 1
+	}
+	return true, nil
+}
+
+// batchBundle inserts the bundle txs into the pool as batched txs, so that they are handled
+// the same as the other batched txs when the batch is committed or restored.
+func (p *txPoolImpl[T, Constraint]) batchBundle(bundle *txBundle[T, Constraint], txBatch *commonpool.RequestHashBatch[T, Constraint]) {
+	accounts := make(map[string]uint64)
+	for _, poolTx := range bundle.txs {
+		account := poolTx.getAccount()
+		p.txStore.insertTxInPool(poolTx, false)
+		// the bundle txs are tracked as the private txs, so that they are dropped once the batch is restored
+		p.txStore.privateTxs[poolTx.getHash()] = poolTx
+		if !p.enablePricePriority {
+			p.txStore.priorityByTime.insertKey(poolTx)
+		}
+		p.txStore.nonceCache.setPendingNonce(account, poolTx.getNonce()+1)
+		p.txStore.batchedTxs[txPointer{account: account, nonce: poolTx.getNonce()}] = true
+		txBatch.FillBatchItem(poolTx.rawTx, poolTx.local)
+		accounts[account] = poolTx.getNonce()
+		delete(p.txStore.bundleTxs, poolTx.getHash())
+		p.bundleTxIDs.Add(poolTx.getHash(), bundle.id)
+	}
+
+	// the queued txs behind the bundle txs become ready
+	for account, lastNonce := range accounts {
+		if p.enablePricePriority {
+			p.txStore.priorityByPrice.updateAccountNonce(account, lastNonce)
+		}
+		list, ok := p.txStore.allTxs[account]
+		if !ok {
+			continue
+		}
+		readyTxs, nextDemandNonce := list.filterReady(lastNonce + 1)
+		if len(readyTxs) == 0 {
+			continue
+		}
+		p.txStore.nonceCache.setPendingNonce(account, nextDemandNonce)
+		for _, poolTx := range readyTxs {
+			if p.enablePricePriority {
+				p.txStore.priorityByPrice.push(poolTx)
+			} else {
+				p.txStore.priorityByTime.insertKey(poolTx)
+			}
+		}
+		p.increasePriorityNonBatchSize(uint64(len(readyTxs)))
+		p.txStore.decreaseParkingLotSize(uint64(len(readyTxs)))
+	}
+	p.logger.WithFields(logrus.Fields{
+		"bundle": bundle.id,
+		"size":   len(bundle.txs),
+	}).Info("Batch bundle")
+}
+
+func (p *txPoolImpl[T, Constraint]) removeBundle(bundle *txBundle[T, Constraint], reason DropReason) {
+	for _, poolTx := range bundle.txs {
+		delete(p.txStore.bundleTxs, poolTx.getHash())
+	}
+	p.notifyDroppedTxs(reason, bundle.txs)
+	traceRemovedTx("bundle", len(bundle.txs))
+	if !p.checkPoolFull() {
+		p.setNotFull()
+	}
+}
+
+// removeRestoredBundleTxs drops the bundle txs put back from the restored batches, otherwise they would be
+// batched one by one like the normal txs and the bundle would be split across batches. The bundle txs are
+// only in the pool after they are batched, so all the non-batched ones are restored.
+func (p *txPoolImpl[T, Constraint]) removeRestoredBundleTxs() {
+	restoredTxs := make(map[string][]*internalTransaction[T, Constraint])
+	for _, poolTx := range p.txStore.privateTxs {
+		if !poolTx.bundle {
+			continue
+		}
+		account := poolTx.getAccount()
+		if _, ok := p.txStore.batchedTxs[txPointer{account: account, nonce: poolTx.getNonce()}]; ok {
+			continue
+		}
+		restoredTxs[account] = append(restoredTxs[account], poolTx)
+	}
+	if len(restoredTxs) == 0 {
+		return
+	}
+
+	updateAccounts := make(map[string]uint64)
+	removeCount := 0
+	removePriorityCount := 0
+	for account, txs := range restoredTxs {
+		list, ok := p.txStore.allTxs[account]
+		if !ok {
+			p.logger.Errorf("account %s not found in pool", account)
+			continue
+		}
+		sort.Slice(txs, func(i, j int) bool {
+			return txs[i].getNonce() < txs[j].getNonce()
+		})
+		lowestNonce := txs[0].getNonce()
+
+		// the bundle txs and the txs behind them are not executable any more
+		if p.enablePricePriority {
+			removePriorityCount += len(p.txStore.priorityByPrice.removeTxBehindNonce(txs[0]))
+		} else {
+			removePriorityTxs := make([]*internalTransaction[T, Constraint], 0)
+			p.txStore.priorityByTime.data.Ascend(func(a btree.Item) bool {
+				tx := a.(*orderedIndexKey)
+				if tx.account == account && tx.nonce >= lowestNonce {
+					removePriorityTxs = append(removePriorityTxs, p.txStore.getPoolTxByTxnPointer(tx.account, tx.nonce))
+				}
+				return true
+			})
+			if err := p.txStore.priorityByTime.removeBatchKeys(account, removePriorityTxs); err != nil {
+				p.logger.Errorf("removeBatchKeys failed: %s", err)
+			}
+			removePriorityCount += len(removePriorityTxs)
+		}
+
+		if err := p.cleanTxsByAccount(account, list, txs, false); err != nil {
+			p.logger.Errorf("cleanTxsByAccount failed: %s", err)
+		} else {
+			removeCount += len(txs)
+			p.notifyDroppedTxs(DropReasonBundleRestored, txs)
+		}
+		p.revertPendingNonce(&txPointer{account: account, nonce: lowestNonce}, updateAccounts)
+	}
+
+	if p.txStore.priorityNonBatchSize < uint64(removePriorityCount) {
+		p.logger.Errorf("decrease nonBatchSize error, want decrease to %d, actual size %d", removePriorityCount, p.txStore.priorityNonBatchSize)
+		p.setPriorityNonBatchSize(0)
+	} else {
+		p.decreasePriorityNonBatchSize(uint64(removePriorityCount))
+	}
+	p.logger.Infof("Successfully remove restored bundle txs, count: %d", removeCount)
+	traceRemovedTx("restoredBundle", removeCount)
+}
+
+// removeExpiredBundles removes the bundles whose max block has been reached.
+func (p *txPoolImpl[T, Constraint]) removeExpiredBundles() {
+	height := p.getChainHeight()
+	remained := make([]*txBundle[T, Constraint], 0, len(p.txStore.bundles))
+	for _, bundle := range p.txStore.bundles {
+		if bundle.maxBlock > height {
+			remained = append(remained, bundle)
+			continue
+		}
+		p.logger.WithFields(logrus.Fields{
+			"bundle":    bundle.id,
+			"max_block": bundle.maxBlock,
+		}).Info("Remove expired bundle")
+		p.removeBundle(bundle, DropReasonExpired)
+	}
+	p.txStore.bundles = remained
+}
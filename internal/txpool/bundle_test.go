@@ -0,0 +1,155 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	commonpool "github.com/axiomesh/axiom-kit/txpool"
+	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
+)
+
+func TestTxPoolImpl_AddBundle(t *testing.T) {
+	ast := assert.New(t)
+	testcase := map[string]*txPoolImpl[types.Transaction, *types.Transaction]{
+		"price_priority": mockTxPoolImplWithTyp[types.Transaction, *types.Transaction](t, repo.GenerateBatchByGasPrice),
+		"time":           mockTxPoolImplWithTyp[types.Transaction, *types.Transaction](t, repo.GenerateBatchByTime),
+	}
+
+	for name, pool := range testcase {
+		t.Run(name, func(t *testing.T) {
+			pool.chainState.EpochInfo.ConsensusParams.BlockMaxTxNum = 10
+			pool.chainState.ChainMeta = &types.ChainMeta{Height: 10}
+			err := pool.Start()
+			ast.Nil(err)
+			defer pool.Stop()
+
+			s1, err := types.GenerateSigner()
+			ast.Nil(err)
+			s2, err := types.GenerateSigner()
+			ast.Nil(err)
+			s3, err := types.GenerateSigner()
+			ast.Nil(err)
+			txs1 := constructTxs(s1, 3)
+			txs2 := constructTxs(s2, 1)
+			txs3 := constructTxs(s3, 2)
+
+			_, err = pool.AddBundle(nil, 12)
+			ast.ErrorIs(err, ErrBundleEmpty)
+			_, err = pool.AddBundle([]*types.Transaction{txs1[1]}, 10)
+			ast.ErrorIs(err, ErrBundleExpired)
+			_, err = pool.AddBundle([]*types.Transaction{txs1[0], txs1[2]}, 12)
+			ast.ErrorIs(err, ErrBundleNonceGap)
+
+			err = pool.AddLocalTx(txs1[0])
+			ast.Nil(err)
+			bundleID, err := pool.AddBundle([]*types.Transaction{txs1[1], txs1[2], txs2[0]}, 12)
+			ast.Nil(err)
+			_, err = pool.AddBundle([]*types.Transaction{txs2[0]}, 12)
+			ast.ErrorIs(err, ErrBundleDuplicate)
+			// the bundle waits for the missing tx of nonce 0
+			pendingBundleID, err := pool.AddBundle([]*types.Transaction{txs3[1]}, 11)
+			ast.Nil(err)
+			ast.NotEqual(bundleID, pendingBundleID)
+			ast.Equal(TxStatusQueued, pool.GetTxStatus(txs1[1].RbftGetTxHash()).Status)
+			ast.True(pool.HasPendingRequestInPool())
+
+			batch, err := pool.GenerateRequestBatch(commonpool.GenBatchTimeoutEvent)
+			ast.Nil(err)
+			ast.Equal(4, len(batch.TxList))
+			ast.Equal(txs1[0].RbftGetTxHash(), batch.TxHashList[0])
+			ast.Equal(txs1[1].RbftGetTxHash(), batch.TxHashList[1])
+			ast.Equal(txs1[2].RbftGetTxHash(), batch.TxHashList[2])
+			ast.Equal(txs2[0].RbftGetTxHash(), batch.TxHashList[3])
+			ast.Equal(TxStatusBatched, pool.GetTxStatus(txs1[2].RbftGetTxHash()).Status)
+			ast.Equal(uint64(0), pool.txStore.priorityNonBatchSize)
+			ast.Equal(1, len(pool.txStore.bundles))
+
+			id, ok := pool.GetBundleID(txs2[0].RbftGetTxHash())
+			ast.True(ok)
+			ast.Equal(bundleID, id)
+			_, ok = pool.GetBundleID(txs1[0].RbftGetTxHash())
+			ast.False(ok)
+
+			// the not executable bundle does not block the pending status
+			_, err = pool.GenerateRequestBatch(commonpool.GenBatchTimeoutEvent)
+			ast.NotNil(err)
+			ast.False(pool.HasPendingRequestInPool())
+
+			// the batched bundle txs are dropped instead of being put back as the normal txs
+			pool.RestorePool()
+			ast.Equal(TxStatusPending, pool.GetTxStatus(txs1[0].RbftGetTxHash()).Status)
+			for _, tx := range []*types.Transaction{txs1[1], txs1[2], txs2[0]} {
+				status := pool.GetTxStatus(tx.RbftGetTxHash())
+				ast.Equal(TxStatusDropped, status.Status)
+				ast.Equal(DropReasonBundleRestored, status.Reason)
+			}
+			ast.Equal(uint64(1), pool.txStore.priorityNonBatchSize)
+			ast.Equal(uint64(1), pool.txStore.nonceCache.getPendingNonce(s1.Addr.String()))
+			ast.Equal(uint64(0), pool.txStore.nonceCache.getPendingNonce(s2.Addr.String()))
+
+			// the bundle is dropped once the chain height reaches the max block
+			pool.chainState.ChainMeta = &types.ChainMeta{Height: 11}
+			pool.RemoveStateUpdatingTxs([]*commonpool.WrapperTxPointer{})
+			status := pool.GetTxStatus(txs3[1].RbftGetTxHash())
+			ast.Equal(TxStatusDropped, status.Status)
+			ast.Equal(DropReasonExpired, status.Reason)
+			ast.Equal(0, len(pool.txStore.bundles))
+			ast.Equal(0, len(pool.txStore.bundleTxs))
+		})
+	}
+}
+
+func TestTxPoolImpl_BundleLimits(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImpl[types.Transaction, *types.Transaction](t)
+	pool.chainState.EpochInfo.ConsensusParams.BlockMaxTxNum = 10
+	pool.chainState.ChainMeta = &types.ChainMeta{Height: 10}
+	pool.maxBundles = 2
+	pool.maxBundleBlockDistance = 5
+	pool.accountSlots = 3
+	pool.poolMaxSize = 5
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	s1, err := types.GenerateSigner()
+	ast.Nil(err)
+	s2, err := types.GenerateSigner()
+	ast.Nil(err)
+	s3, err := types.GenerateSigner()
+	ast.Nil(err)
+	txs1 := constructTxs(s1, 4)
+	txs2 := constructTxs(s2, 3)
+	txs3 := constructTxs(s3, 1)
+
+	_, err = pool.AddBundle([]*types.Transaction{txs1[0]}, 16)
+	ast.ErrorIs(err, ErrBundleTooFar)
+
+	// the pending txs and the bundle txs are limited by the account slots together
+	err = pool.AddLocalTx(txs1[0])
+	ast.Nil(err)
+	_, err = pool.AddBundle([]*types.Transaction{txs1[1], txs1[2], txs1[3]}, 15)
+	ast.ErrorIs(err, ErrAccountSlotsExceeded)
+	_, err = pool.AddBundle([]*types.Transaction{txs1[1]}, 15)
+	ast.Nil(err)
+	_, err = pool.AddBundle([]*types.Transaction{txs1[2], txs1[3]}, 15)
+	ast.ErrorIs(err, ErrAccountSlotsExceeded)
+
+	// the bundle txs take the pool space
+	_, err = pool.AddBundle([]*types.Transaction{txs2[0], txs2[1], txs2[2], txs3[0]}, 15)
+	ast.ErrorIs(err, ErrTxPoolFull)
+	_, err = pool.AddBundle([]*types.Transaction{txs2[0], txs2[1]}, 15)
+	ast.Nil(err)
+	_, err = pool.AddBundle([]*types.Transaction{txs3[0]}, 15)
+	ast.ErrorIs(err, ErrBundleTooMany)
+
+	ast.Equal(4, pool.txStore.poolTxCount())
+	ast.False(pool.IsPoolFull())
+	err = pool.AddLocalTx(txs3[0])
+	ast.Nil(err)
+	ast.True(pool.IsPoolFull())
+	err = pool.AddLocalTx(txs2[2])
+	ast.ErrorIs(err, ErrTxPoolFull)
+}
@@ -13,6 +13,11 @@ type Config struct {
 	RepoRoot               string
 	Logger                 logrus.FieldLogger
 	PoolSize               uint64
+	AccountSlots           uint64
+	AccountQueue           uint64
+	GlobalQueue            uint64
+	MaxBundles             uint64
+	MaxBundleBlockDistance uint64
 	ToleranceNonceGap      uint64
 	ToleranceTime          time.Duration
 	ToleranceRemoveTime    time.Duration
@@ -21,6 +26,7 @@ type Config struct {
 	GetAccountNonce        GetAccountNonceFunc
 	GetAccountBalance      GetAccountBalanceFunc
 	EnableLocalsPersist    bool
+	EnableRemotesPersist   bool
 	PriceLimit             uint64
 	PriceBump              uint64
 	GenerateBatchType      string
@@ -32,6 +38,21 @@ func (c *Config) sanitize() {
 	if c.PoolSize == 0 {
 		c.PoolSize = DefaultPoolSize
 	}
+	if c.AccountSlots == 0 {
+		c.AccountSlots = DefaultAccountSlots
+	}
+	if c.AccountQueue == 0 {
+		c.AccountQueue = DefaultAccountQueue
+	}
+	if c.GlobalQueue == 0 {
+		c.GlobalQueue = DefaultGlobalQueue
+	}
+	if c.MaxBundles == 0 {
+		c.MaxBundles = DefaultMaxBundles
+	}
+	if c.MaxBundleBlockDistance == 0 {
+		c.MaxBundleBlockDistance = DefaultMaxBundleBlockDistance
+	}
 	if c.ToleranceTime == 0 {
 		c.ToleranceTime = DefaultToleranceTime
 	}
@@ -48,7 +69,8 @@ func (c *Config) sanitize() {
 		c.RotateTxLocalsInterval = DefaultRotateTxLocalsInterval
 	}
 
-	if c.GenerateBatchType != repo.GenerateBatchByTime && c.GenerateBatchType != repo.GenerateBatchByGasPrice {
+	if c.GenerateBatchType != repo.GenerateBatchByTime && c.GenerateBatchType != repo.GenerateBatchByGasPrice &&
+		c.GenerateBatchType != repo.GenerateBatchByTip {
 		c.GenerateBatchType = repo.GenerateBatchByTime
 	}
 	if c.PriceBump < DefaultPriceBump {
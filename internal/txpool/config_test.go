@@ -9,6 +9,9 @@ import (
 func TestSanitizeConfig(t *testing.T) {
 	c := Config{
 		PoolSize:               0,
+		AccountSlots:           0,
+		AccountQueue:           0,
+		GlobalQueue:            0,
 		ToleranceTime:          0,
 		ToleranceRemoveTime:    0,
 		CleanEmptyAccountTime:  0,
@@ -16,6 +19,9 @@ func TestSanitizeConfig(t *testing.T) {
 	}
 	c.sanitize()
 	require.Equal(t, uint64(DefaultPoolSize), c.PoolSize)
+	require.Equal(t, uint64(DefaultAccountSlots), c.AccountSlots)
+	require.Equal(t, uint64(DefaultAccountQueue), c.AccountQueue)
+	require.Equal(t, uint64(DefaultGlobalQueue), c.GlobalQueue)
 	require.Equal(t, DefaultToleranceTime, c.ToleranceTime)
 	require.Equal(t, DefaultToleranceRemoveTime, c.ToleranceRemoveTime)
 	require.Equal(t, DefaultCleanEmptyAccountTime, c.CleanEmptyAccountTime)
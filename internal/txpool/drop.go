@@ -0,0 +1,157 @@
+package txpool
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// DropReason is the reason why the tx is removed from the txpool before it is committed.
+type DropReason string
+
+const (
+	DropReasonTimeout   DropReason = "timeout"
+	DropReasonHighNonce DropReason = "high_nonce"
+	DropReasonReplaced  DropReason = "replaced"
+	DropReasonInvalid   DropReason = "invalid"
+	DropReasonEvicted   DropReason = "evicted"
+	DropReasonExpired   DropReason = "expired"
+	// the bundle tx is put back from a restored batch, it can not be batched together with the bundle again
+	DropReasonBundleRestored DropReason = "bundle_restored"
+)
+
+type TxStatus string
+
+const (
+	TxStatusUnknown   TxStatus = "unknown"
+	TxStatusPending   TxStatus = "pending"
+	TxStatusQueued    TxStatus = "queued"
+	TxStatusBatched   TxStatus = "batched"
+	TxStatusReplaced  TxStatus = "replaced"
+	TxStatusDropped   TxStatus = "dropped"
+	TxStatusCommitted TxStatus = "committed"
+)
+
+// the number of the recently dropped txs kept for the tx status query
+const droppedTxCacheSize = 10000
+
+// the number of the dropped tx events buffered for the subscribers, the events are discarded when it is full
+const droppedTxChanSize = 1024
+
+// DroppedTx is the event of the tx dropped from the txpool.
+type DroppedTx struct {
+	Hash    string
+	Account string
+	Nonce   uint64
+	Reason  DropReason
+	// ReplacedBy is the hash of the new tx, only set when the tx is replaced
+	ReplacedBy string
+	Timestamp  int64
+}
+
+// TxStatusInfo is the status of the tx in the txpool, Reason and ReplacedBy are only set for the dropped tx.
+type TxStatusInfo struct {
+	Status     TxStatus
+	Reason     DropReason
+	ReplacedBy string
+}
+
+// SubscribeDroppedTxEvent subscribes the txs dropped from the txpool, the events are delivered
+// asynchronously and discarded if the subscribers fall too far behind.
+func (p *txPoolImpl[T, Constraint]) SubscribeDroppedTxEvent(ch chan<- []*DroppedTx) event.Subscription {
+	return p.droppedTxFeed.Subscribe(ch)
+}
+
+func (p *txPoolImpl[T, Constraint]) notifyDroppedTxs(reason DropReason, txs []*internalTransaction[T, Constraint]) {
+	if len(txs) == 0 {
+		return
+	}
+	now := time.Now().Unix()
+	dropped := make([]*DroppedTx, 0, len(txs))
+	for _, tx := range txs {
+		droppedTx := &DroppedTx{
+			Hash:      tx.getHash(),
+			Account:   tx.getAccount(),
+			Nonce:     tx.getNonce(),
+			Reason:    reason,
+			Timestamp: now,
+		}
+		p.droppedTxs.Add(droppedTx.Hash, droppedTx)
+		dropped = append(dropped, droppedTx)
+	}
+	p.postDroppedTxs(dropped)
+}
+
+func (p *txPoolImpl[T, Constraint]) notifyReplacedTx(oldTx *internalTransaction[T, Constraint], newHash string) {
+	droppedTx := &DroppedTx{
+		Hash:       oldTx.getHash(),
+		Account:    oldTx.getAccount(),
+		Nonce:      oldTx.getNonce(),
+		Reason:     DropReasonReplaced,
+		ReplacedBy: newHash,
+		Timestamp:  time.Now().Unix(),
+	}
+	p.droppedTxs.Add(droppedTx.Hash, droppedTx)
+	p.postDroppedTxs([]*DroppedTx{droppedTx})
+}
+
+// postDroppedTxs hands the dropped txs to listenDroppedTxEvent, it never blocks the txpool event loop.
+func (p *txPoolImpl[T, Constraint]) postDroppedTxs(dropped []*DroppedTx) {
+	select {
+	case p.droppedTxCh <- dropped:
+	default:
+		p.logger.Warnf("dropped tx event channel is full, discard %d dropped txs", len(dropped))
+	}
+}
+
+// listenDroppedTxEvent delivers the dropped txs to the subscribers, it is not waited by Stop
+// because the delivery may be blocked by a slow subscriber.
+func (p *txPoolImpl[T, Constraint]) listenDroppedTxEvent() {
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case dropped := <-p.droppedTxCh:
+			p.droppedTxFeed.Send(dropped)
+		}
+	}
+}
+
+// GetTxStatus returns the status of the tx in the txpool, the committed txs are not tracked by the txpool,
+// so the caller should query the ledger for them.
+func (p *txPoolImpl[T, Constraint]) GetTxStatus(hash string) *TxStatusInfo {
+	req := &reqTxStatusMsg{
+		hash: hash,
+		ch:   make(chan *TxStatusInfo),
+	}
+	ev := &poolInfoEvent{
+		EventType: reqTxStatusEvent,
+		Event:     req,
+	}
+	p.postEvent(ev)
+	return <-req.ch
+}
+
+func (p *txPoolImpl[T, Constraint]) handleGetTxStatus(hash string) *TxStatusInfo {
+	if key, ok := p.txStore.txHashMap[hash]; ok {
+		if _, ok = p.txStore.batchedTxs[*key]; ok {
+			return &TxStatusInfo{Status: TxStatusBatched}
+		}
+		if key.nonce >= p.txStore.nonceCache.getPendingNonce(key.account) {
+			return &TxStatusInfo{Status: TxStatusQueued}
+		}
+		return &TxStatusInfo{Status: TxStatusPending}
+	}
+
+	if _, ok := p.txStore.bundleTxs[hash]; ok {
+		return &TxStatusInfo{Status: TxStatusQueued}
+	}
+
+	if droppedTx, ok := p.droppedTxs.Get(hash); ok {
+		if droppedTx.Reason == DropReasonReplaced {
+			return &TxStatusInfo{Status: TxStatusReplaced, Reason: droppedTx.Reason, ReplacedBy: droppedTx.ReplacedBy}
+		}
+		return &TxStatusInfo{Status: TxStatusDropped, Reason: droppedTx.Reason}
+	}
+	return &TxStatusInfo{Status: TxStatusUnknown}
+}
@@ -0,0 +1,91 @@
+package txpool
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+func TestTxPoolImpl_DroppedTxs(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImpl[types.Transaction, *types.Transaction](t)
+	pool.toleranceRemoveTime = 10 * time.Millisecond
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	droppedCh := make(chan []*DroppedTx, 10)
+	sub := pool.SubscribeDroppedTxEvent(droppedCh)
+	defer sub.Unsubscribe()
+
+	s, err := types.GenerateSigner()
+	ast.Nil(err)
+	pendingTx := constructPoolTxByGas(s, 0, big.NewInt(1000)).rawTx
+	queuedTx := constructPoolTxByGas(s, 2, big.NewInt(1000)).rawTx
+	pool.AddRemoteTxs([]*types.Transaction{pendingTx, queuedTx})
+	ast.Equal(TxStatusPending, pool.GetTxStatus(pendingTx.RbftGetTxHash()).Status)
+	ast.Equal(TxStatusQueued, pool.GetTxStatus(queuedTx.RbftGetTxHash()).Status)
+	ast.Equal(TxStatusUnknown, pool.GetTxStatus(types.NewHashByStr("0x1").String()).Status)
+
+	// replace the pending tx
+	newTx := constructPoolTxByGas(s, 0, big.NewInt(2000)).rawTx
+	pool.AddRemoteTxs([]*types.Transaction{newTx})
+	dropped := <-droppedCh
+	ast.Equal(1, len(dropped))
+	ast.Equal(pendingTx.RbftGetTxHash(), dropped[0].Hash)
+	ast.Equal(DropReasonReplaced, dropped[0].Reason)
+	ast.Equal(newTx.RbftGetTxHash(), dropped[0].ReplacedBy)
+	status := pool.GetTxStatus(pendingTx.RbftGetTxHash())
+	ast.Equal(TxStatusReplaced, status.Status)
+	ast.Equal(newTx.RbftGetTxHash(), status.ReplacedBy)
+	ast.Equal(TxStatusPending, pool.GetTxStatus(newTx.RbftGetTxHash()).Status)
+
+	// the queued tx is removed after timeout
+	time.Sleep(12 * time.Millisecond)
+	pool.handleRemoveTimeout(RemoveTx)
+	dropped = <-droppedCh
+	ast.Equal(1, len(dropped))
+	ast.Equal(queuedTx.RbftGetTxHash(), dropped[0].Hash)
+	ast.Equal(DropReasonTimeout, dropped[0].Reason)
+	status = pool.GetTxStatus(queuedTx.RbftGetTxHash())
+	ast.Equal(TxStatusDropped, status.Status)
+	ast.Equal(DropReasonTimeout, status.Reason)
+}
+
+func TestTxPoolImpl_DroppedTxsWithSlowSubscriber(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImpl[types.Transaction, *types.Transaction](t)
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	// the subscriber never reads the channel
+	slowCh := make(chan []*DroppedTx)
+	sub := pool.SubscribeDroppedTxEvent(slowCh)
+	defer sub.Unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < droppedTxChanSize+10; i++ {
+			pool.postDroppedTxs([]*DroppedTx{{Hash: types.NewHashByStr(fmt.Sprintf("0x%x", i+1)).String(), Reason: DropReasonEvicted}})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("posting dropped txs is blocked by the slow subscriber")
+	}
+
+	// the txpool event loop is not blocked either
+	s, err := types.GenerateSigner()
+	ast.Nil(err)
+	tx := constructPoolTxByGas(s, 0, big.NewInt(1000)).rawTx
+	pool.AddRemoteTxs([]*types.Transaction{tx})
+	ast.Equal(TxStatusPending, pool.GetTxStatus(tx.RbftGetTxHash()).Status)
+}
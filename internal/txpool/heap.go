@@ -3,6 +3,7 @@ package txpool
 import (
 	"container/heap"
 	"math"
+	"math/big"
 
 	"github.com/axiomesh/axiom-kit/types"
 	axm_heap "github.com/axiomesh/axiom-ledger/internal/components/heap"
@@ -189,3 +190,44 @@ func (tp *TxByPriceAndTime[T, Constraint]) remove(tx *internalTransaction[T, Con
 	}
 	return removed
 }
+
+// TxByTipAndTime sorts the transactions by the effective miner tip under the base fee,
+// the heap is re-sorted when the base fee changes.
+type TxByTipAndTime[T any, Constraint types.TXConstraint[T]] struct {
+	TxByPriceAndTime[T, Constraint]
+	baseFee *big.Int
+}
+
+func (tp *TxByTipAndTime[T, Constraint]) Less(i, j int) bool {
+	cmp := tp.TxByPriceAndTime[i].getEffectiveTip(tp.baseFee).Cmp(tp.TxByPriceAndTime[j].getEffectiveTip(tp.baseFee))
+	if cmp == 0 {
+		return tp.TxByPriceAndTime[i].getRawTimestamp() < tp.TxByPriceAndTime[j].getRawTimestamp()
+	}
+	return cmp > 0
+}
+
+func (tp *TxByTipAndTime[T, Constraint]) push(tx any) {
+	heap.Push(tp, tx)
+}
+
+func (tp *TxByTipAndTime[T, Constraint]) pop() any {
+	return heap.Pop(tp)
+}
+
+func (tp *TxByTipAndTime[T, Constraint]) remove(tx *internalTransaction[T, Constraint]) bool {
+	for i := 0; i < tp.Len(); i++ {
+		if tp.TxByPriceAndTime[i] == tx {
+			heap.Remove(tp, i)
+			return true
+		}
+	}
+	return false
+}
+
+func (tp *TxByTipAndTime[T, Constraint]) setBaseFee(baseFee *big.Int) {
+	if tp.baseFee.Cmp(baseFee) == 0 {
+		return
+	}
+	tp.baseFee = new(big.Int).Set(baseFee)
+	heap.Init(tp)
+}
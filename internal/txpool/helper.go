@@ -40,6 +40,11 @@ func traceRemovedTx(reason string, count int) {
 	removeTxNum.With(prometheus.Labels{"reason": "all"}).Add(float64(count))
 }
 
+func traceEvictedTx() {
+	evictTxNum.Inc()
+	traceRemovedTx("evicted", 1)
+}
+
 func traceProcessEvent(event string, duration time.Duration) {
 	processEventDuration.With(prometheus.Labels{"event": event}).Observe(duration.Seconds())
 	processEventDuration.With(prometheus.Labels{"event": "all"}).Observe(duration.Seconds())
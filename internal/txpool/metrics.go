@@ -45,6 +45,13 @@ var (
 		},
 		[]string{"reason"},
 	)
+	evictTxNum = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "txpool",
+			Name:      "evict_tx_counter",
+			Help:      "the total number of transactions which evicted by the cheaper gas price when txpool is full",
+		},
+	)
 	removeTxNum = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "txpool",
@@ -62,5 +69,6 @@ func init() {
 	prometheus.MustRegister(readyTxNum)
 	prometheus.MustRegister(rejectTxNum)
 	prometheus.MustRegister(removeTxNum)
+	prometheus.MustRegister(evictTxNum)
 	prometheus.MustRegister(queueTxNum)
 }
@@ -3,27 +3,48 @@ package txpool
 import (
 	"container/heap"
 	"errors"
+	"math/big"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/axiomesh/axiom-kit/types"
 )
 
+type pricedHeap[T any, Constraint types.TXConstraint[T]] interface {
+	Len() int
+	push(tx any)
+	pop() any
+	peek() *internalTransaction[T, Constraint]
+	remove(tx *internalTransaction[T, Constraint]) bool
+}
+
 type priceQueue[T any, Constraint types.TXConstraint[T]] struct {
-	priced        *TxByPriceAndTime[T, Constraint]               // every account exist only lowest nonce transaction in the queue
+	priced        pricedHeap[T, Constraint]                      // every account exist only lowest nonce transaction in the queue
 	dirtyAccounts map[string]*internalTransaction[T, Constraint] // account -> current pending nonce
 	logger        logrus.FieldLogger
 }
 
 func newPriceQueue[T any, Constraint types.TXConstraint[T]](logger logrus.FieldLogger) *priceQueue[T, Constraint] {
-	p := &priceQueue[T, Constraint]{
-		priced:        new(TxByPriceAndTime[T, Constraint]),
+	priced := make(TxByPriceAndTime[T, Constraint], 0)
+	heap.Init(&priced)
+	return &priceQueue[T, Constraint]{
+		priced:        &priced,
+		dirtyAccounts: make(map[string]*internalTransaction[T, Constraint]),
+		logger:        logger,
+	}
+}
+
+func newTipQueue[T any, Constraint types.TXConstraint[T]](baseFee *big.Int, logger logrus.FieldLogger) *priceQueue[T, Constraint] {
+	priced := &TxByTipAndTime[T, Constraint]{
+		TxByPriceAndTime: make(TxByPriceAndTime[T, Constraint], 0),
+		baseFee:          new(big.Int).Set(baseFee),
+	}
+	heap.Init(priced)
+	return &priceQueue[T, Constraint]{
+		priced:        priced,
 		dirtyAccounts: make(map[string]*internalTransaction[T, Constraint]),
 		logger:        logger,
 	}
-	*p.priced = make(TxByPriceAndTime[T, Constraint], 0)
-	heap.Init(p.priced)
-	return p
 }
 
 // push if the account is already in the dirtyAccounts, omit it
@@ -101,6 +122,26 @@ func newPriorityQueue[T any, Constraint types.TXConstraint[T]](fn func(string) u
 	}
 }
 
+// newTipPriorityQueue returns the priority queue which orders the txs by the effective miner tip,
+// only the lowest nonce tx of each account is in the queue, so the nonce order of account is preserved.
+func newTipPriorityQueue[T any, Constraint types.TXConstraint[T]](fn func(string) uint64, baseFee *big.Int, logger logrus.FieldLogger) *priorityQueue[T, Constraint] {
+	return &priorityQueue[T, Constraint]{
+		accountsM:    make(map[string]*accountQueue[T, Constraint]),
+		txsByPrice:   newTipQueue[T, Constraint](baseFee, logger),
+		nonBatchSize: 0,
+		getNonceFn:   fn,
+		logger:       logger,
+	}
+}
+
+// setBaseFee re-sorts the txs by the effective miner tip under the new base fee,
+// it is a no-op for the queue which orders the txs by gas price.
+func (p *priorityQueue[T, Constraint]) setBaseFee(baseFee *big.Int) {
+	if priced, ok := p.txsByPrice.priced.(*TxByTipAndTime[T, Constraint]); ok {
+		priced.setBaseFee(baseFee)
+	}
+}
+
 func (p *priorityQueue[T, Constraint]) push(tx *internalTransaction[T, Constraint]) {
 	from := tx.getAccount()
 	account, ok := p.accountsM[from]
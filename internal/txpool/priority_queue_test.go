@@ -189,6 +189,35 @@ func TestPriorityQueue_ReplaceTx(t *testing.T) {
 	require.Equal(t, uint64(1), q.size())
 }
 
+func TestPriorityQueue_TipOrder(t *testing.T) {
+	q := newTipPriorityQueue[types.Transaction, *types.Transaction](getNonce, big.NewInt(0), log.NewWithModule("priorityQueue"))
+	s1, _ := types.GenerateSigner()
+	s2, _ := types.GenerateSigner()
+	s3, _ := types.GenerateSigner()
+
+	legacyTx := constructPoolTxByGas(s1, 0, big.NewInt(100))
+	tx20 := constructPoolTxByTip(s2, 0, big.NewInt(300), big.NewInt(150))
+	tx21 := constructPoolTxByTip(s2, 1, big.NewInt(1000), big.NewInt(1000))
+	tx30 := constructPoolTxByTip(s3, 0, big.NewInt(200), big.NewInt(200))
+	q.push(legacyTx)
+	q.push(tx20)
+	q.push(tx21)
+	q.push(tx30)
+	require.Equal(t, 3, q.txsByPrice.length(), "only the lowest nonce tx of account is in the priced queue")
+
+	// base fee is 0, the effective tip is min(gasTipCap, gasFeeCap)
+	require.Equal(t, tx30, q.peek())
+
+	// base fee is 100, the effective tips are: legacyTx 0, tx20 150, tx30 100
+	q.setBaseFee(big.NewInt(100))
+	require.Equal(t, tx20, q.pop())
+	// tx21 follows tx20 with the highest tip
+	require.Equal(t, tx21, q.pop())
+	require.Equal(t, tx30, q.pop())
+	require.Equal(t, legacyTx, q.pop())
+	require.Equal(t, 0, q.txsByPrice.length())
+}
+
 func Benchmark_PriorityQueue(t *testing.B) {
 	testTable := []struct {
 		name        string
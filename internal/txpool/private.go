@@ -0,0 +1,83 @@
+package txpool
+
+import (
+	"github.com/pkg/errors"
+)
+
+// AddPrivateTx adds the local tx which is only kept in the txpool of this node, it is never broadcast to
+// other nodes and only included in the batch generated by this node. The tx is dropped once the chain
+// height reaches maxBlock, 0 means no limit.
+func (p *txPoolImpl[T, Constraint]) AddPrivateTx(tx *T, maxBlock uint64) error {
+	if maxBlock != 0 && maxBlock <= p.getChainHeight() {
+		return errors.Wrapf(ErrPrivateTxExpired, "max block: %d, current height: %d", maxBlock, p.getChainHeight())
+	}
+	req := &reqLocalTx[T, Constraint]{
+		tx:       tx,
+		private:  true,
+		maxBlock: maxBlock,
+		errCh:    make(chan error),
+	}
+
+	ev := &addTxsEvent{
+		EventType: localTxEvent,
+		Event:     req,
+	}
+	p.postEvent(ev)
+
+	return <-req.errCh
+}
+
+func (p *txPoolImpl[T, Constraint]) getChainHeight() uint64 {
+	if p.chainState.ChainMeta == nil {
+		return 0
+	}
+	return p.chainState.ChainMeta.Height
+}
+
+// markPrivateTx marks the tx which has been added to the pool as private,
+// and removes it from localTTLIndex to avoid the rebroadcast.
+func (p *txPoolImpl[T, Constraint]) markPrivateTx(tx *T, maxBlock uint64) {
+	pointer, ok := p.txStore.txHashMap[Constraint(tx).RbftGetTxHash()]
+	if !ok {
+		return
+	}
+	poolTx := p.txStore.getPoolTxByTxnPointer(pointer.account, pointer.nonce)
+	if poolTx == nil {
+		return
+	}
+	poolTx.private = true
+	poolTx.maxBlock = maxBlock
+	p.txStore.localTTLIndex.removeKey(poolTx)
+	p.txStore.privateTxs[poolTx.getHash()] = poolTx
+}
+
+// removeExpiredPrivateTxs removes the private txs whose max block has been reached,
+// the batched txs are ignored because they are being proposed.
+func (p *txPoolImpl[T, Constraint]) removeExpiredPrivateTxs() {
+	height := p.getChainHeight()
+	for {
+		// handleRemoveInvalidTxs removes at most one tx of each account, so remove the lowest nonce one every round
+		expiredTxs := make(map[string]*internalTransaction[T, Constraint])
+		for _, poolTx := range p.txStore.privateTxs {
+			if poolTx.maxBlock == 0 || poolTx.maxBlock > height {
+				continue
+			}
+			account := poolTx.getAccount()
+			if _, ok := p.txStore.batchedTxs[txPointer{account: account, nonce: poolTx.getNonce()}]; ok {
+				continue
+			}
+			if old, ok := expiredTxs[account]; !ok || old.getNonce() > poolTx.getNonce() {
+				expiredTxs[account] = poolTx
+			}
+		}
+		if len(expiredTxs) == 0 {
+			return
+		}
+		removeCount := p.handleRemoveInvalidTxs(expiredTxs, DropReasonExpired)
+		p.logger.Infof("Successfully remove expired private txs, count: %d", removeCount)
+		traceRemovedTx("expiredPrivate", removeCount)
+		if removeCount == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package txpool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	commonpool "github.com/axiomesh/axiom-kit/txpool"
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+func TestTxPoolImpl_AddPrivateTx(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImpl[types.Transaction, *types.Transaction](t)
+	pool.chainState.ChainMeta = &types.ChainMeta{Height: 10}
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	s, err := types.GenerateSigner()
+	ast.Nil(err)
+	txs := constructTxs(s, 3)
+	err = pool.AddPrivateTx(txs[0], 10)
+	ast.ErrorIs(err, ErrPrivateTxExpired)
+
+	err = pool.AddPrivateTx(txs[0], 12)
+	ast.Nil(err)
+	err = pool.AddPrivateTx(txs[1], 0)
+	ast.Nil(err)
+	err = pool.AddLocalTx(txs[2])
+	ast.Nil(err)
+	ast.Equal(TxStatusPending, pool.GetTxStatus(txs[0].RbftGetTxHash()).Status)
+	ast.Equal(2, len(pool.txStore.privateTxs))
+
+	// the private txs are never broadcast
+	ast.Equal(1, pool.txStore.localTTLIndex.size())
+	ast.Equal(1, len(pool.GetLocalTxs()))
+	forward := pool.FilterOutOfDateRequests(false)
+	ast.Equal(1, len(forward))
+	ast.Equal(txs[2].RbftGetTxHash(), forward[0].RbftGetTxHash())
+
+	// the private tx is dropped once the chain height reaches the max block
+	pool.chainState.ChainMeta = &types.ChainMeta{Height: 12}
+	pool.RemoveStateUpdatingTxs([]*commonpool.WrapperTxPointer{})
+	status := pool.GetTxStatus(txs[0].RbftGetTxHash())
+	ast.Equal(TxStatusDropped, status.Status)
+	ast.Equal(DropReasonExpired, status.Reason)
+	ast.Equal(TxStatusQueued, pool.GetTxStatus(txs[1].RbftGetTxHash()).Status)
+	ast.Equal(1, len(pool.txStore.privateTxs))
+	ast.Equal(uint64(0), pool.GetPendingTxCountByAccount(s.Addr.String()))
+}
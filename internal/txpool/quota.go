@@ -0,0 +1,152 @@
+package txpool
+
+import (
+	"container/heap"
+	"math/big"
+
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+// evictableTxsCompactThreshold is the min number of the stale items which triggers the compaction of evictableTxs.
+const evictableTxsCompactThreshold = 1024
+
+// Quota is the limits of the txpool and the number of txs evicted because of the limits.
+type Quota struct {
+	PoolSize       uint64
+	AccountSlots   uint64
+	AccountQueue   uint64
+	GlobalQueue    uint64
+	EvictedTxCount uint64
+}
+
+func (p *txPoolImpl[T, Constraint]) GetQuota() *Quota {
+	return &Quota{
+		PoolSize:       p.poolMaxSize,
+		AccountSlots:   p.accountSlots,
+		AccountQueue:   p.accountQueue,
+		GlobalQueue:    p.globalQueue,
+		EvictedTxCount: p.evictedTxCount.Load(),
+	}
+}
+
+// checkAccountQuota checks whether the account has free slots for the new remote tx,
+// the pending txs are limited by accountSlots and the queued txs are limited by accountQueue.
+func (p *txPoolImpl[T, Constraint]) checkAccountQuota(account string, txNonce, pendingNonce uint64) error {
+	list, ok := p.txStore.allTxs[account]
+	if !ok {
+		return nil
+	}
+	commitNonce := p.txStore.nonceCache.getCommitNonce(account)
+	var pendingCount uint64
+	if pendingNonce > commitNonce {
+		pendingCount = pendingNonce - commitNonce
+	}
+	if txNonce == pendingNonce {
+		if pendingCount >= p.accountSlots {
+			return ErrAccountSlotsExceeded
+		}
+		return nil
+	}
+
+	var queuedCount uint64
+	if uint64(len(list.items)) > pendingCount {
+		queuedCount = uint64(len(list.items)) - pendingCount
+	}
+	if queuedCount >= p.accountQueue {
+		return ErrAccountQueueExceeded
+	}
+	return nil
+}
+
+// evictableTxs is a min heap of the remote queued txs ordered by gas price, the later arrived one is evicted first
+// if the gas prices are equal. The txs which have been removed or turned into pending are dropped lazily.
+type evictableTxs[T any, Constraint types.TXConstraint[T]] []*internalTransaction[T, Constraint]
+
+func (h evictableTxs[T, Constraint]) Len() int { return len(h) }
+
+func (h evictableTxs[T, Constraint]) Less(i, j int) bool {
+	if cmp := h[i].getGasPrice().Cmp(h[j].getGasPrice()); cmp != 0 {
+		return cmp < 0
+	}
+	return h[i].arrivedTime > h[j].arrivedTime
+}
+
+func (h evictableTxs[T, Constraint]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *evictableTxs[T, Constraint]) Push(x any) {
+	*h = append(*h, x.(*internalTransaction[T, Constraint]))
+}
+
+func (h *evictableTxs[T, Constraint]) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// insertEvictableTx tracks the remote tx which is inserted into the parking lot.
+func (txStore *transactionStore[T, Constraint]) insertEvictableTx(poolTx *internalTransaction[T, Constraint]) {
+	if poolTx.local {
+		return
+	}
+	heap.Push(&txStore.evictableTxs, poolTx)
+
+	// drop the stale items, so that the heap does not grow with the txs which have left the parking lot
+	if uint64(txStore.evictableTxs.Len()) > 2*txStore.parkingLotSize+evictableTxsCompactThreshold {
+		txs := txStore.evictableTxs[:0]
+		for _, tx := range txStore.evictableTxs {
+			if txStore.isEvictable(tx) {
+				txs = append(txs, tx)
+			}
+		}
+		for i := len(txs); i < len(txStore.evictableTxs); i++ {
+			txStore.evictableTxs[i] = nil
+		}
+		txStore.evictableTxs = txs
+		heap.Init(&txStore.evictableTxs)
+	}
+}
+
+// isEvictable returns whether the tx is still a remote queued tx in the pool.
+func (txStore *transactionStore[T, Constraint]) isEvictable(poolTx *internalTransaction[T, Constraint]) bool {
+	account, nonce := poolTx.getAccount(), poolTx.getNonce()
+	return txStore.getPoolTxByTxnPointer(account, nonce) == poolTx && nonce > txStore.nonceCache.getPendingNonce(account)
+}
+
+// evictCheaperTx evicts the cheapest remote queued tx whose gas price is lower than the given gas price,
+// the pending txs are never evicted to avoid nonce gaps of the executable txs.
+func (p *txPoolImpl[T, Constraint]) evictCheaperTx(gasPrice *big.Int) bool {
+	var evictTx *internalTransaction[T, Constraint]
+	for p.txStore.evictableTxs.Len() > 0 {
+		tx := p.txStore.evictableTxs[0]
+		if !p.txStore.isEvictable(tx) {
+			heap.Pop(&p.txStore.evictableTxs)
+			continue
+		}
+		if tx.getGasPrice().Cmp(gasPrice) >= 0 {
+			return false
+		}
+		evictTx = heap.Pop(&p.txStore.evictableTxs).(*internalTransaction[T, Constraint])
+		break
+	}
+	if evictTx == nil {
+		return false
+	}
+
+	account := evictTx.getAccount()
+	if err := p.cleanTxsByAccount(account, p.txStore.allTxs[account], []*internalTransaction[T, Constraint]{evictTx}, false); err != nil {
+		p.logger.Warningf("evict tx %s failed: %v", evictTx.getHash(), err)
+		return false
+	}
+	p.evictedTxCount.Add(1)
+	traceEvictedTx()
+	p.notifyDroppedTxs(DropReasonEvicted, []*internalTransaction[T, Constraint]{evictTx})
+	p.logger.Debugf("evict tx[account: %s, nonce: %d, gas price: %s] for the tx with gas price %s",
+		account, evictTx.getNonce(), evictTx.getGasPrice(), gasPrice)
+	if !p.checkPoolFull() {
+		p.setNotFull()
+	}
+	return true
+}
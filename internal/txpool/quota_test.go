@@ -0,0 +1,126 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/axiomesh/axiom-kit/types"
+)
+
+func TestTxPoolImpl_AccountQuota(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImpl[types.Transaction, *types.Transaction](t)
+	pool.accountSlots = 2
+	pool.accountQueue = 1
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	s, err := types.GenerateSigner()
+	ast.Nil(err)
+	from := s.Addr.String()
+	txs := constructTxs(s, 3)
+	pool.AddRemoteTxs(txs)
+	ast.Equal(uint64(2), pool.GetAccountMeta(from, false).PendingNonce, "the pending txs of account are limited by account slots")
+	ast.Nil(pool.GetPendingTxByHash(txs[2].RbftGetTxHash()))
+
+	// the local txs are not limited
+	err = pool.AddLocalTx(txs[2])
+	ast.Nil(err)
+	ast.Equal(uint64(3), pool.GetAccountMeta(from, false).PendingNonce)
+
+	s2, err := types.GenerateSigner()
+	ast.Nil(err)
+	txs = constructTxs(s2, 3)
+	pool.AddRemoteTxs(txs[1:])
+	ast.NotNil(pool.GetPendingTxByHash(txs[1].RbftGetTxHash()))
+	ast.Nil(pool.GetPendingTxByHash(txs[2].RbftGetTxHash()), "the queued txs of account are limited by account queue")
+}
+
+func TestTxPoolImpl_EvictCheaperTx(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImpl[types.Transaction, *types.Transaction](t)
+	pool.globalQueue = 1
+	pool.poolMaxSize = 3
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	s1, err := types.GenerateSigner()
+	ast.Nil(err)
+	s2, err := types.GenerateSigner()
+	ast.Nil(err)
+	s3, err := types.GenerateSigner()
+	ast.Nil(err)
+
+	// the global queue is full, the cheaper queued tx is evicted
+	cheapTx := constructPoolTxByGas(s1, 1, big.NewInt(1000)).rawTx
+	pool.AddRemoteTxs([]*types.Transaction{cheapTx})
+	ast.NotNil(pool.GetPendingTxByHash(cheapTx.RbftGetTxHash()))
+	queuedTx := constructPoolTxByGas(s2, 1, big.NewInt(2000)).rawTx
+	pool.AddRemoteTxs([]*types.Transaction{queuedTx})
+	ast.NotNil(pool.GetPendingTxByHash(queuedTx.RbftGetTxHash()))
+	ast.Nil(pool.GetPendingTxByHash(cheapTx.RbftGetTxHash()))
+	ast.Equal(uint64(1), pool.GetQuota().EvictedTxCount)
+	ast.Equal(uint64(1), pool.txStore.parkingLotSize)
+
+	// the tx which is not more expensive than the queued txs is rejected
+	pool.AddRemoteTxs([]*types.Transaction{constructPoolTxByGas(s3, 1, big.NewInt(2000)).rawTx})
+	ast.Equal(uint64(1), pool.GetMeta(false).TxCount)
+
+	// the pool is full, the queued tx is evicted by the pending tx with higher gas price
+	pendingTxs := []*types.Transaction{
+		constructPoolTxByGas(s1, 0, big.NewInt(3000)).rawTx,
+		constructPoolTxByGas(s3, 0, big.NewInt(3000)).rawTx,
+	}
+	pool.AddRemoteTxs(pendingTxs)
+	ast.Equal(uint64(3), pool.GetMeta(false).TxCount)
+	ast.True(pool.IsPoolFull())
+	pool.AddRemoteTxs([]*types.Transaction{constructPoolTxByGas(s3, 1, big.NewInt(3000)).rawTx})
+	meta := pool.GetMeta(false)
+	ast.Equal(uint64(3), meta.TxCount)
+	ast.Nil(pool.GetPendingTxByHash(queuedTx.RbftGetTxHash()))
+	ast.Equal(uint64(2), pool.GetQuota().EvictedTxCount)
+
+	// the pending txs are never evicted
+	pool.AddRemoteTxs([]*types.Transaction{constructPoolTxByGas(s2, 0, big.NewInt(4000)).rawTx})
+	ast.Equal(uint64(3), pool.GetMeta(false).TxCount)
+	ast.Equal(uint64(2), pool.GetQuota().EvictedTxCount)
+}
+
+func TestTxPoolImpl_EvictCheapestQueuedTx(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImpl[types.Transaction, *types.Transaction](t)
+	pool.globalQueue = 3
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	signers := make([]*types.Signer, 6)
+	for i := range signers {
+		signers[i], err = types.GenerateSigner()
+		ast.Nil(err)
+	}
+	queuedTxs := make([]*types.Transaction, len(signers))
+	for i, price := range []int64{3000, 1000, 2000, 1500, 2500, 4000} {
+		queuedTxs[i] = constructPoolTxByGas(signers[i], 1, big.NewInt(price)).rawTx
+	}
+
+	// the cheapest queued tx is evicted
+	pool.AddRemoteTxs(queuedTxs[:4])
+	ast.Nil(pool.GetPendingTxByHash(queuedTxs[1].RbftGetTxHash()))
+	ast.Equal(uint64(3), pool.GetMeta(false).NotReadyTxCount)
+	ast.Equal(uint64(1), pool.GetQuota().EvictedTxCount)
+
+	// the queued tx which turns into pending is not evicted any more
+	pool.AddRemoteTxs([]*types.Transaction{constructPoolTxByGas(signers[3], 0, big.NewInt(1000)).rawTx})
+	ast.Equal(uint64(2), pool.GetMeta(false).NotReadyTxCount)
+	pool.AddRemoteTxs(queuedTxs[4:])
+	ast.Equal(uint64(3), pool.GetMeta(false).NotReadyTxCount)
+	ast.Equal(uint64(2), pool.GetQuota().EvictedTxCount)
+	ast.Nil(pool.GetPendingTxByHash(queuedTxs[2].RbftGetTxHash()))
+	ast.NotNil(pool.GetPendingTxByHash(queuedTxs[3].RbftGetTxHash()))
+	ast.NotNil(pool.GetPendingTxByHash(queuedTxs[5].RbftGetTxHash()))
+}
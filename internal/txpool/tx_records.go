@@ -22,6 +22,7 @@ const (
 	TxRecordPrefixLength = 8
 	TxRecordsBatchSize   = 1000
 	TxRecordsFile        = "tx_records.pb"
+	TxRemoteRecordsFile  = "tx_remote_records.pb"
 	DecodeTxRecordsFile  = "decode_tx_records.json"
 )
 
@@ -29,15 +30,19 @@ func (*devNull) Write(p []byte) (n int, err error) { return len(p), nil }
 
 func (*devNull) Close() error { return nil }
 
+// txRecords journals the local or the remote txs of the pool, the local and remote txs are kept
+// in different files so that the txs can be reloaded with their origin.
 type txRecords[T any, Constraint types.TXConstraint[T]] struct {
 	logger   logrus.FieldLogger
 	filePath string
+	local    bool
 	writer   io.WriteCloser
 }
 
-func newTxRecords[T any, Constraint types.TXConstraint[T]](filePath string, logger logrus.FieldLogger) *txRecords[T, Constraint] {
+func newTxRecords[T any, Constraint types.TXConstraint[T]](filePath string, local bool, logger logrus.FieldLogger) *txRecords[T, Constraint] {
 	return &txRecords[T, Constraint]{
 		filePath: filePath,
+		local:    local,
 		logger:   logger,
 	}
 }
@@ -140,7 +145,7 @@ func (r *txRecords[T, Constraint]) rotate(all map[string]*txSortedMap[T, Constra
 	record := 0
 	for _, txMap := range all {
 		for _, internalTx := range txMap.items {
-			if !internalTx.local {
+			if internalTx.local != r.local {
 				continue
 			}
 			tx := internalTx.rawTx
@@ -212,6 +217,42 @@ func GetAllTxRecords(filePath string) ([][]byte, error) {
 	return res, nil
 }
 
+// WriteTxRecords replaces the txrecords file with the given records, the records are written to a
+// temporary file first, so the origin file is kept if the writing fails.
+func WriteTxRecords(filePath string, records [][]byte) error {
+	if !fileutil.ExistDir(path.Dir(filePath)) {
+		if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+			return err
+		}
+	}
+	replacement, err := os.OpenFile(filePath+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(replacement)
+	for _, record := range records {
+		var lengthBytes [TxRecordPrefixLength]byte
+		binary.LittleEndian.PutUint64(lengthBytes[:], uint64(len(record)))
+		if _, err = buf.Write(lengthBytes[:]); err != nil {
+			break
+		}
+		if _, err = buf.Write(record); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+	if closeErr := replacement.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(filePath + ".new")
+		return err
+	}
+	return os.Rename(filePath+".new", filePath)
+}
+
 func (r *txRecords[T, Constraint]) close() error {
 	var err error
 
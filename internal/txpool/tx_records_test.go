@@ -3,12 +3,20 @@ package txpool
 import (
 	"bytes"
 	"context"
+	"math"
+	"math/big"
 	"os"
+	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/axiomesh/axiom-kit/fileutil"
+	"github.com/axiomesh/axiom-kit/txpool"
 	"github.com/axiomesh/axiom-kit/types"
+	"github.com/axiomesh/axiom-ledger/internal/chainstate"
+	"github.com/axiomesh/axiom-ledger/pkg/repo"
 )
 
 func TestTxRecords(t *testing.T) {
@@ -60,6 +68,33 @@ func TestTxRecords(t *testing.T) {
 	<-taskDoneCh
 }
 
+func TestWriteTxRecords(t *testing.T) {
+	s, err := types.GenerateSigner()
+	assert.Nil(t, err)
+	filePath := path.Join(t.TempDir(), "records", TxRecordsFile)
+
+	txs := constructTxs(s, 3)
+	records := make([][]byte, 0, len(txs))
+	for _, tx := range txs {
+		data, err := tx.RbftMarshal()
+		assert.Nil(t, err)
+		records = append(records, data)
+	}
+	err = WriteTxRecords(filePath, records)
+	assert.Nil(t, err)
+	all, err := GetAllTxRecords(filePath)
+	assert.Nil(t, err)
+	assert.Equal(t, records, all)
+
+	// rewrite the file with part of the records
+	err = WriteTxRecords(filePath, records[1:])
+	assert.Nil(t, err)
+	all, err = GetAllTxRecords(filePath)
+	assert.Nil(t, err)
+	assert.Equal(t, records[1:], all)
+	assert.False(t, fileutil.Exist(filePath+".new"))
+}
+
 func TestDevNull(t *testing.T) {
 	devNull := devNull{}
 	n, err := devNull.Write([]byte("test"))
@@ -201,3 +236,62 @@ func TestTxRecords_RotateMoreThanOneBatch(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, len(records) == TxRecordsBatchSize+1)
 }
+
+func TestTxRecords_RemotesPersist(t *testing.T) {
+	s1, err := types.GenerateSigner()
+	assert.Nil(t, err)
+	s2, err := types.GenerateSigner()
+	assert.Nil(t, err)
+
+	poolConf := NewMockTxPoolConfig(t)
+	poolConf.EnableRemotesPersist = true
+	r := repo.MockRepo(t)
+	chainState := chainstate.NewMockChainState(r.GenesisConfig, nil)
+	chainState.EpochInfo.FinanceParams.MinGasPrice = types.CoinNumberByMol(0)
+	newPool := func() *txPoolImpl[types.Transaction, *types.Transaction] {
+		pool, err := newTxPoolImpl[types.Transaction, *types.Transaction](poolConf, chainState)
+		assert.Nil(t, err)
+		pool.Init(txpool.ConsensusConfig{
+			SelfID:                1,
+			NotifyGenerateBatchFn: func(typ int) {},
+		})
+		assert.Nil(t, pool.Start())
+		return pool
+	}
+
+	pool := newPool()
+	pool.AddRemoteTxs(constructTxs(s1, 2))
+	pool.AddRemoteTxs(constructTxs(s2, 1))
+	assert.Eventually(t, func() bool {
+		records, err := GetAllTxRecords(pool.txRemoteRecordsFile)
+		return err == nil && len(records) == 3
+	}, time.Second, 10*time.Millisecond)
+	records, err := GetAllTxRecords(pool.txRecordsFile)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(records), "remote txs should not be recorded as locals")
+	pool.Stop()
+
+	// the first tx of s1 has been executed and s2 can not afford its tx after restart
+	poolConf.GetAccountNonce = func(address string) uint64 {
+		if address == s1.Addr.String() {
+			return 1
+		}
+		return 0
+	}
+	poolConf.GetAccountBalance = func(address string) *big.Int {
+		if address == s2.Addr.String() {
+			return big.NewInt(0)
+		}
+		return big.NewInt(math.MaxInt64)
+	}
+	pool = newPool()
+	defer pool.Stop()
+	assert.Equal(t, uint64(1), pool.GetTotalPendingTxCount())
+	assert.Equal(t, 0, pool.txStore.localTTLIndex.size(), "reloaded remote txs should not be locals")
+	assert.NotNil(t, pool.txStore.getPoolTxByTxnPointer(s1.Addr.String(), 1))
+
+	// the dropped txs are compacted from the records
+	records, err = GetAllTxRecords(pool.txRemoteRecordsFile)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(records))
+}
@@ -28,6 +28,9 @@ type transactionStore[T any, Constraint types.TXConstraint[T]] struct {
 	// only used to help remove some txs if pool is full.
 	parkingLotIndex *btreeIndex[T, Constraint]
 
+	// keeps track of the remote "non-ready" txs ordered by gas price, used to evict the cheapest one.
+	evictableTxs evictableTxs[T, Constraint]
+
 	// keeps track of "ready" txs
 	priorityByPrice *priorityQueue[T, Constraint]
 
@@ -56,6 +59,20 @@ type transactionStore[T any, Constraint types.TXConstraint[T]] struct {
 	// removeTTLIndex based on the remove tolerance time to track all the remained txs
 	// that arrived in txpool and remove these txs from memPoll cache in case these exist too long.
 	removeTTLIndex *btreeIndex[T, Constraint]
+
+	// track all the private txs which are never broadcast, removed when they exceed the max block.
+	privateTxs map[string]*internalTransaction[T, Constraint]
+
+	// track the bundles waiting to be batched in arrived order, the bundle txs are not in allTxs until they are batched.
+	bundles []*txBundle[T, Constraint]
+
+	// track the txs of the bundles waiting to be batched.
+	bundleTxs map[string]*txBundle[T, Constraint]
+}
+
+// poolTxCount returns the number of the txs which take the pool space, including the bundle txs waiting to be batched.
+func (txStore *transactionStore[T, Constraint]) poolTxCount() int {
+	return len(txStore.txHashMap) + len(txStore.bundleTxs)
 }
 
 func newTransactionStore[T any, Constraint types.TXConstraint[T]](f GetAccountNonceFunc, logger logrus.FieldLogger) *transactionStore[T, Constraint] {
@@ -67,6 +84,8 @@ func newTransactionStore[T any, Constraint types.TXConstraint[T]](f GetAccountNo
 		txHashMap:            make(map[string]*txPointer),
 		allTxs:               make(map[string]*txSortedMap[T, Constraint]),
 		batchedTxs:           make(map[txPointer]bool),
+		privateTxs:           make(map[string]*internalTransaction[T, Constraint]),
+		bundleTxs:            make(map[string]*txBundle[T, Constraint]),
 		missingBatch:         make(map[string]map[uint64]string),
 		batchesCache:         make(map[string]*txpool.RequestHashBatch[T, Constraint]),
 		parkingLotIndex:      newBtreeIndex[T, Constraint](Ordered),
@@ -138,6 +157,7 @@ func (txStore *transactionStore[T, Constraint]) deletePoolTx(account string, non
 
 		// delete tx pointer in txHashMap
 		txStore.deletePoolTxPointer(poolTx.getHash())
+		delete(txStore.privateTxs, poolTx.getHash())
 	}
 }
 
@@ -341,6 +361,21 @@ func (tx *internalTransaction[T, Constraint]) getGasPrice() *big.Int {
 	return Constraint(tx.rawTx).RbftGetGasPrice()
 }
 
+// getEffectiveTip returns the tip paid to the miner under the base fee, which is
+// min(gasTipCap, gasFeeCap-baseFee), the result may be negative if gasFeeCap is lower than base fee.
+func (tx *internalTransaction[T, Constraint]) getEffectiveTip(baseFee *big.Int) *big.Int {
+	gasFeeCap := Constraint(tx.rawTx).RbftGetGasFeeCap()
+	gasTipCap := gasFeeCap
+	if tipTx, ok := any(tx.rawTx).(interface{ GetGasTipCap() *big.Int }); ok {
+		gasTipCap = tipTx.GetGasTipCap()
+	}
+	tip := new(big.Int).Sub(gasFeeCap, baseFee)
+	if tip.Cmp(gasTipCap) > 0 {
+		tip.Set(gasTipCap)
+	}
+	return tip
+}
+
 func (tx *internalTransaction[T, Constraint]) clone() *internalTransaction[T, Constraint] {
 	cloneTx := types.CloneTransaction[T, Constraint](tx.rawTx)
 	return &internalTransaction[T, Constraint]{
@@ -348,5 +383,7 @@ func (tx *internalTransaction[T, Constraint]) clone() *internalTransaction[T, Co
 		local:       tx.local,
 		lifeTime:    tx.lifeTime,
 		arrivedTime: tx.arrivedTime,
+		private:     tx.private,
+		maxBlock:    tx.maxBlock,
 	}
 }
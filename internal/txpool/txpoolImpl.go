@@ -11,7 +11,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/google/btree"
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	"github.com/sirupsen/logrus"
@@ -30,12 +32,25 @@ import (
 var _ commonpool.TxPool[types.Transaction, *types.Transaction] = (*txPoolImpl[types.Transaction, *types.Transaction])(nil)
 
 var (
-	ErrTxPoolFull     = errors.New("tx pool full")
-	ErrNonceTooLow    = errors.New("nonce too low")
-	ErrNonceTooHigh   = errors.New("nonce too high")
-	ErrDuplicateTx    = errors.New("duplicate tx")
-	ErrGasPriceTooLow = errors.New("gas price too low")
-	ErrBelowPriceBump = errors.New("replace old tx err, gas price is below price bump")
+	ErrTxPoolFull       = errors.New("tx pool full")
+	ErrNonceTooLow      = errors.New("nonce too low")
+	ErrNonceTooHigh     = errors.New("nonce too high")
+	ErrDuplicateTx      = errors.New("duplicate tx")
+	ErrGasPriceTooLow   = errors.New("gas price too low")
+	ErrBelowPriceBump   = errors.New("replace old tx err, gas price is below price bump")
+	ErrPrivateTxExpired = errors.New("private tx exceeds the max block")
+
+	ErrBundleEmpty     = errors.New("bundle has no tx")
+	ErrBundleExpired   = errors.New("bundle exceeds the max block")
+	ErrBundleTooLarge  = errors.New("bundle txs exceed the block max tx num")
+	ErrBundleNonceGap  = errors.New("bundle txs of the same account are not consecutive")
+	ErrBundleDuplicate = errors.New("bundle tx already exists")
+	ErrBundleTooMany   = errors.New("bundles exceed the limit")
+	ErrBundleTooFar    = errors.New("bundle max block is too far from the current height")
+
+	ErrAccountSlotsExceeded = errors.New("account pending txs exceed the limit")
+	ErrAccountQueueExceeded = errors.New("account queued txs exceed the limit")
+	ErrGlobalQueueFull      = errors.New("global queued txs exceed the limit")
 )
 
 // txPoolImpl contains all currently known transactions.
@@ -50,22 +65,37 @@ type txPoolImpl[T any, Constraint types.TXConstraint[T]] struct {
 	cleanEmptyAccountTime  time.Duration
 	rotateTxLocalsInterval time.Duration
 	poolMaxSize            uint64
+	accountSlots           uint64
+	accountQueue           uint64
+	globalQueue            uint64
+	maxBundles             uint64
+	maxBundleBlockDistance uint64
+	evictedTxCount         atomic.Uint64
 	priceLimit             atomic.Pointer[big.Int] // Minimum gas price to enforce for acceptance into the pool
 	PriceBump              uint64                  // Minimum price bump percentage to replace an already existing transaction (nonce)
 	enableLocalsPersist    bool
 	txRecordsFile          string
+	enableRemotesPersist   bool
+	txRemoteRecordsFile    string
 	enablePricePriority    bool
+	enableTipPriority      bool
+	droppedTxFeed          event.Feed
+	droppedTxCh            chan []*DroppedTx
+	droppedTxs             *lru.Cache[string, *DroppedTx]
+	bundleTxIDs            *lru.Cache[string, string]
 
 	getAccountNonce       GetAccountNonceFunc
 	getAccountBalance     GetAccountBalanceFunc
+	getBaseFee            func() *big.Int
 	notifyGenerateBatch   bool
 	notifyGenerateBatchFn func(typ int)
 	notifyFindNextBatchFn func(completionMissingBatchHashes ...string) // notify consensus that it can find next batch
 
-	timerMgr  timer.Timer
-	statusMgr *status.StatusMgr
-	started   atomic.Bool
-	txRecords *txRecords[T, Constraint]
+	timerMgr        timer.Timer
+	statusMgr       *status.StatusMgr
+	started         atomic.Bool
+	txRecords       *txRecords[T, Constraint]
+	txRemoteRecords *txRecords[T, Constraint]
 
 	revCh  chan txPoolEvent
 	ctx    context.Context
@@ -82,6 +112,7 @@ func (p *txPoolImpl[T, Constraint]) Start() error {
 		return errors.New("txpool already started")
 	}
 	go p.listenEvent()
+	go p.listenDroppedTxEvent()
 
 	err := p.timerMgr.StartTimer(RemoveTx)
 	if err != nil {
@@ -91,7 +122,7 @@ func (p *txPoolImpl[T, Constraint]) Start() error {
 	if err != nil {
 		return err
 	}
-	if p.enableLocalsPersist {
+	if p.enableLocalsPersist || p.enableRemotesPersist {
 		err = p.timerMgr.StartTimer(RotateTxLocals)
 		if err != nil {
 			return err
@@ -104,10 +135,24 @@ func (p *txPoolImpl[T, Constraint]) Start() error {
 }
 
 func (p *txPoolImpl[T, Constraint]) processRecords() error {
+	if p.enableLocalsPersist {
+		if err := p.processTxRecords(p.txRecords); err != nil {
+			return err
+		}
+	}
+	if p.enableRemotesPersist {
+		if err := p.processTxRecords(p.txRemoteRecords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *txPoolImpl[T, Constraint]) processTxRecords(records *txRecords[T, Constraint]) error {
 	taskDoneCh := make(chan struct{}, 1)
 	defer close(taskDoneCh)
 
-	input, err := os.Open(p.txRecords.filePath)
+	input, err := os.Open(records.filePath)
 	if err != nil {
 		p.logger.Errorf("Failed to open tx records file: %v", err)
 		return err
@@ -115,7 +160,7 @@ func (p *txPoolImpl[T, Constraint]) processRecords() error {
 	defer input.Close()
 
 	now := time.Now()
-	txsCh := p.txRecords.load(input, taskDoneCh)
+	txsCh := records.load(input, taskDoneCh)
 	totalInsertCount := 0
 	for {
 		select {
@@ -123,14 +168,15 @@ func (p *txPoolImpl[T, Constraint]) processRecords() error {
 			if !ok {
 				return nil
 			}
-			totalInsertCount += p.processRecordsTask(txs)
+			totalInsertCount += p.processRecordsTask(txs, records.local)
 		case <-taskDoneCh:
 			close(txsCh)
 			for txs := range txsCh {
-				totalInsertCount += p.processRecordsTask(txs)
+				totalInsertCount += p.processRecordsTask(txs, records.local)
 			}
 
 			p.logger.WithFields(logrus.Fields{
+				"file":    records.filePath,
 				"add_num": totalInsertCount,
 				"cost":    time.Since(now),
 			}).Info("End add record txs successfully")
@@ -139,11 +185,16 @@ func (p *txPoolImpl[T, Constraint]) processRecords() error {
 	}
 }
 
-func (p *txPoolImpl[T, Constraint]) processRecordsTask(txs []*T) int {
+func (p *txPoolImpl[T, Constraint]) processRecordsTask(txs []*T, local bool) int {
+	// the records are bounded by the pool size, the left records are dropped once the pool is full
+	if p.statusMgr.In(PoolFull) {
+		return 0
+	}
 	start := time.Now()
 	req := &reqLocalRecordTx[T, Constraint]{
-		txs: txs,
-		ch:  make(chan int, 1),
+		txs:    p.filterRecordTxs(txs),
+		remote: !local,
+		ch:     make(chan int, 1),
 	}
 	p.handleLocalRecordTx(req)
 	insertCount := <-req.ch
@@ -160,6 +211,29 @@ func (p *txPoolImpl[T, Constraint]) processRecordsTask(txs []*T) int {
 	return insertCount
 }
 
+// filterRecordTxs drops the record txs which have been executed or can not be afforded
+// by the sender since they were recorded.
+func (p *txPoolImpl[T, Constraint]) filterRecordTxs(txs []*T) []*T {
+	accountNonces := make(map[string]uint64)
+	return lo.Filter(txs, func(tx *T, _ int) bool {
+		from := Constraint(tx).RbftGetFrom()
+		nonce, ok := accountNonces[from]
+		if !ok {
+			nonce = p.getAccountNonce(from)
+			accountNonces[from] = nonce
+		}
+		if Constraint(tx).RbftGetNonce() < nonce {
+			p.logger.Debugf("drop record tx %s with nonce %d, the account nonce is %d", Constraint(tx).RbftGetTxHash(), Constraint(tx).RbftGetNonce(), nonce)
+			return false
+		}
+		if err := components.VerifyInsufficientBalance[T, Constraint](tx, p.getAccountBalance); err != nil {
+			p.logger.Debugf("drop record tx %s: %v", Constraint(tx).RbftGetTxHash(), err)
+			return false
+		}
+		return true
+	})
+}
+
 func (p *txPoolImpl[T, Constraint]) listenEvent() {
 	p.wg.Add(1)
 	defer p.wg.Done()
@@ -243,7 +317,7 @@ func (p *txPoolImpl[T, Constraint]) dispatchAddTxsEvent(event *addTxsEvent) []tx
 	switch event.EventType {
 	case localTxEvent:
 		req := event.Event.(*reqLocalTx[T, Constraint])
-		if p.statusMgr.In(PoolFull) {
+		if p.statusMgr.In(PoolFull) && !p.evictCheaperTx(Constraint(req.tx).RbftGetGasPrice()) {
 			traceRejectTx(ErrTxPoolFull.Error())
 			req.errCh <- ErrTxPoolFull
 			return nil
@@ -256,8 +330,13 @@ func (p *txPoolImpl[T, Constraint]) dispatchAddTxsEvent(event *addTxsEvent) []tx
 			nextEvents = append(nextEvents, removeEvent)
 		}
 
+		if err == nil && req.private {
+			p.markPrivateTx(req.tx, req.maxBlock)
+		}
+
 		if err == nil {
-			if p.enableLocalsPersist && p.txRecordsFile != "" {
+			// the private txs are not persisted, otherwise they would be broadcast after restart
+			if p.enableLocalsPersist && p.txRecordsFile != "" && !req.private {
 				now := time.Now()
 				err = p.txRecords.insert(req.tx)
 				tracePersistRecords(time.Since(now))
@@ -273,6 +352,14 @@ func (p *txPoolImpl[T, Constraint]) dispatchAddTxsEvent(event *addTxsEvent) []tx
 			}
 		}()
 
+	case bundleTxsEvent:
+		req := event.Event.(*reqBundleTxs[T, Constraint])
+		bundleID, err := p.handleAddBundle(req.txs, req.maxBlock)
+		if err != nil {
+			traceRejectTx(err.Error())
+		}
+		req.respCh <- &respBundleTxs{bundleID: bundleID, err: err}
+
 	case remoteTxsEvent, reBroadcastTxsEvent:
 		nonceTooHighAccounts := make(map[string]bool)
 		req := event.Event.(*reqRemoteTxs[T, Constraint])
@@ -293,15 +380,26 @@ func (p *txPoolImpl[T, Constraint]) dispatchAddTxsEvent(event *addTxsEvent) []tx
 		// rebroadcast txs should not be rejected
 		if event.EventType == remoteTxsEvent {
 			// if tx pool is full, reject the left txs
-			if len(p.txStore.txHashMap)+len(txs) > int(p.poolMaxSize) {
-				if len(p.txStore.txHashMap) < int(p.poolMaxSize) {
-					remainSpace := int(p.poolMaxSize) - len(p.txStore.txHashMap)
+			if p.txStore.poolTxCount()+len(txs) > int(p.poolMaxSize) {
+				if p.txStore.poolTxCount() < int(p.poolMaxSize) {
+					remainSpace := int(p.poolMaxSize) - p.txStore.poolTxCount()
 					overSpaceTxs = txs[remainSpace:]
-					txs = txs[:remainSpace]
+					txs = txs[:remainSpace:remainSpace]
 				}
 			}
 			if p.statusMgr.In(PoolFull) {
 				overSpaceTxs = txs
+				txs = nil
+			}
+			// the over space txs replace the cheaper remote queued txs
+			overSpaceTxs = lo.Filter(overSpaceTxs, func(tx *T, _ int) bool {
+				if p.evictCheaperTx(Constraint(tx).RbftGetGasPrice()) {
+					txs = append(txs, tx)
+					return false
+				}
+				return true
+			})
+			if len(txs) == 0 {
 				return nil
 			}
 		}
@@ -322,6 +420,16 @@ func (p *txPoolImpl[T, Constraint]) dispatchAddTxsEvent(event *addTxsEvent) []tx
 			}
 		})
 
+		if p.enableRemotesPersist && len(validTxs) != 0 {
+			now := time.Now()
+			for _, tx := range validTxs {
+				if err := p.txRemoteRecords.insert(tx); err != nil {
+					p.logger.Errorf("Failed to record remote tx %s: %v", Constraint(tx).RbftGetTxHash(), err)
+				}
+			}
+			tracePersistRecords(time.Since(now))
+		}
+
 		p.postConsensusSignal(validTxs)
 
 	case missingTxsEvent:
@@ -373,9 +481,9 @@ func (p *txPoolImpl[T, Constraint]) handleLocalRecordTx(req *reqLocalRecordTx[T,
 	}()
 
 	// if tx pool is full, reject the left txs
-	if len(p.txStore.txHashMap)+len(txs) > int(p.poolMaxSize) {
-		if len(p.txStore.txHashMap) < int(p.poolMaxSize) {
-			remainSpace := int(p.poolMaxSize) - len(p.txStore.txHashMap)
+	if p.txStore.poolTxCount()+len(txs) > int(p.poolMaxSize) {
+		if p.txStore.poolTxCount() < int(p.poolMaxSize) {
+			remainSpace := int(p.poolMaxSize) - p.txStore.poolTxCount()
 			overSpaceTxs = txs[remainSpace:]
 			txs = txs[:remainSpace]
 		}
@@ -386,7 +494,7 @@ func (p *txPoolImpl[T, Constraint]) handleLocalRecordTx(req *reqLocalRecordTx[T,
 	}
 
 	lo.ForEach(txs, func(tx *T, i int) {
-		replaced, err := p.addTx(tx, true)
+		replaced, err := p.addTx(tx, !req.remote)
 		// omit add record txs error
 		if err == nil {
 			p.updateValidTxs(&validTxs, tx, replaced)
@@ -480,14 +588,18 @@ func (p *txPoolImpl[T, Constraint]) dispatchRemoveTxsEvent(event *removeTxsEvent
 			p.logger.Infof("Successfully remove committed txs, count: %d", removeCount)
 			traceRemovedTx("committed", removeCount)
 		}
+		p.removeExpiredPrivateTxs()
+		p.removeExpiredBundles()
 	case batchedTxsEvent:
 		removeCount = p.handleRemoveBatches(event.Event.(*reqRemoveBatchedTxs).batchHashList)
 		if removeCount > 0 {
 			p.logger.Infof("Successfully remove batched txs, count: %d", removeCount)
 			traceRemovedTx("batched", removeCount)
 		}
+		p.removeExpiredPrivateTxs()
+		p.removeExpiredBundles()
 	case invalidTxsEvent:
-		removeCount = p.handleRemoveInvalidTxs(event.Event.(*reqRemoveInvalidTxs[T, Constraint]).removeTxs)
+		removeCount = p.handleRemoveInvalidTxs(event.Event.(*reqRemoveInvalidTxs[T, Constraint]).removeTxs, DropReasonInvalid)
 		if removeCount > 0 {
 			p.logger.Infof("Successfully remove gas too low txs, count: %d", removeCount)
 			traceRemovedTx("invalid", removeCount)
@@ -501,7 +613,7 @@ func (p *txPoolImpl[T, Constraint]) dispatchRemoveTxsEvent(event *removeTxsEvent
 }
 
 // remove invalid txs(invalid signature or gasPrice too low)
-func (p *txPoolImpl[T, Constraint]) handleRemoveInvalidTxs(removeTxsM map[string]*internalTransaction[T, Constraint]) int {
+func (p *txPoolImpl[T, Constraint]) handleRemoveInvalidTxs(removeTxsM map[string]*internalTransaction[T, Constraint], reason DropReason) int {
 	updateAccounts := make(map[string]uint64)
 	removeCount := 0
 	removePriorityCount := 0
@@ -517,6 +629,7 @@ func (p *txPoolImpl[T, Constraint]) handleRemoveInvalidTxs(removeTxsM map[string
 				p.logger.Errorf("cleanTxsByAccount failed: %s", err)
 			} else {
 				removeCount++
+				p.notifyDroppedTxs(reason, []*internalTransaction[T, Constraint]{revertTx})
 				if !p.enablePricePriority {
 					removePriorityCount++
 				}
@@ -674,6 +787,9 @@ func (p *txPoolImpl[T, Constraint]) dispatchPoolInfoEvent(event *poolInfoEvent)
 	case reqPoolMetaEvent:
 		req := event.Event.(*reqPoolMetaMsg[T, Constraint])
 		req.ch <- p.handleGetMeta(req.full)
+	case reqTxStatusEvent:
+		req := event.Event.(*reqTxStatusMsg)
+		req.ch <- p.handleGetTxStatus(req.hash)
 	}
 }
 
@@ -843,6 +959,13 @@ func (p *txPoolImpl[T, Constraint]) addTx(tx *T, local bool) (bool, error) {
 			}
 		}
 	}
+	// the replacement never takes more slots, and the local txs are not limited by the quota
+	if err == nil && !needReplace && !local {
+		err = p.checkAccountQuota(txAccount, txNonce, currentSeqNo)
+		if err == nil && txNonce > currentSeqNo && p.txStore.parkingLotSize >= p.globalQueue && !p.evictCheaperTx(gasPrice) {
+			err = ErrGlobalQueueFull
+		}
+	}
 	if err != nil {
 		traceRejectTx(err.Error())
 		return false, err
@@ -958,12 +1081,16 @@ func (p *txPoolImpl[T, Constraint]) handleRemoveTimeoutTxs() int {
 		}
 		return false
 	})
+	droppedTxs := make([]*internalTransaction[T, Constraint], 0)
 	for account, txs := range removedTxs {
 		if list, ok := p.txStore.allTxs[account]; ok {
 			// remove index from removedTxs
-			_ = p.cleanTxsByAccount(account, list, txs, readyCount > 0)
+			if err := p.cleanTxsByAccount(account, list, txs, readyCount > 0); err == nil {
+				droppedTxs = append(droppedTxs, txs...)
+			}
 		}
 	}
+	p.notifyDroppedTxs(DropReasonTimeout, droppedTxs)
 
 	return len(removedTxs)
 }
@@ -983,6 +1110,11 @@ func (p *txPoolImpl[T, Constraint]) Stop() {
 			p.logger.Errorf("Failed to close txRecords: %v", err)
 		}
 	}
+	if p.txRemoteRecords != nil {
+		if err := p.txRemoteRecords.close(); err != nil {
+			p.logger.Errorf("Failed to close remote txRecords: %v", err)
+		}
+	}
 	p.started.Store(false)
 	p.logger.Infof("TxPool stopped!!!")
 }
@@ -1006,13 +1138,20 @@ func newTxPoolImpl[T any, Constraint types.TXConstraint[T]](config Config, chain
 		chainState:        chainState,
 		getAccountNonce:   config.GetAccountNonce,
 		getAccountBalance: config.GetAccountBalance,
+		getBaseFee:        components.BaseFee,
 		revCh:             make(chan txPoolEvent, maxChanSize),
+		droppedTxCh:       make(chan []*DroppedTx, droppedTxChanSize),
 
 		toleranceTime:          config.ToleranceTime,
 		toleranceNonceGap:      config.ToleranceNonceGap,
 		toleranceRemoveTime:    config.ToleranceRemoveTime,
 		cleanEmptyAccountTime:  config.CleanEmptyAccountTime,
 		poolMaxSize:            config.PoolSize,
+		accountSlots:           config.AccountSlots,
+		accountQueue:           config.AccountQueue,
+		globalQueue:            config.GlobalQueue,
+		maxBundles:             config.MaxBundles,
+		maxBundleBlockDistance: config.MaxBundleBlockDistance,
 		rotateTxLocalsInterval: config.RotateTxLocalsInterval,
 		PriceBump:              config.PriceBump,
 
@@ -1023,19 +1162,41 @@ func newTxPoolImpl[T any, Constraint types.TXConstraint[T]](config Config, chain
 	}
 
 	txpoolImp.txStore = newTransactionStore[T, Constraint](config.GetAccountNonce, config.Logger)
+	droppedTxs, err := lru.New[string, *DroppedTx](droppedTxCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	txpoolImp.droppedTxs = droppedTxs
+	bundleTxIDs, err := lru.New[string, string](bundleTxCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	txpoolImp.bundleTxIDs = bundleTxIDs
 
 	txpoolImp.enableLocalsPersist = config.EnableLocalsPersist
 	txpoolImp.txRecordsFile = path.Join(repo.GetStoragePath(config.RepoRoot, storagemgr.TxPool), TxRecordsFile)
 	if txpoolImp.enableLocalsPersist {
-		txpoolImp.txRecords = newTxRecords[T, Constraint](txpoolImp.txRecordsFile, config.Logger)
+		txpoolImp.txRecords = newTxRecords[T, Constraint](txpoolImp.txRecordsFile, true, config.Logger)
+	}
+	txpoolImp.enableRemotesPersist = config.EnableRemotesPersist
+	txpoolImp.txRemoteRecordsFile = path.Join(repo.GetStoragePath(config.RepoRoot, storagemgr.TxPool), TxRemoteRecordsFile)
+	if txpoolImp.enableRemotesPersist {
+		txpoolImp.txRemoteRecords = newTxRecords[T, Constraint](txpoolImp.txRemoteRecordsFile, false, config.Logger)
 	}
 	if config.GenerateBatchType == repo.GenerateBatchByGasPrice {
 		txpoolImp.enablePricePriority = true
 	}
+	// the tip priority reuses the price priority queue to keep the nonce order of account,
+	// only the order of the account head txs is changed.
+	if config.GenerateBatchType == repo.GenerateBatchByTip {
+		txpoolImp.enablePricePriority = true
+		txpoolImp.enableTipPriority = true
+		txpoolImp.txStore.priorityByPrice = newTipPriorityQueue[T, Constraint](txpoolImp.txStore.nonceCache.getCommitNonce, txpoolImp.getBaseFee(), config.Logger)
+	}
 
 	// init timer for remove tx
 	txpoolImp.timerMgr = timer.NewTimerManager(txpoolImp.logger)
-	err := txpoolImp.timerMgr.CreateTimer(RemoveTx, txpoolImp.toleranceRemoveTime, txpoolImp.handleRemoveTimeout)
+	err = txpoolImp.timerMgr.CreateTimer(RemoveTx, txpoolImp.toleranceRemoveTime, txpoolImp.handleRemoveTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -1043,7 +1204,7 @@ func newTxPoolImpl[T any, Constraint types.TXConstraint[T]](config Config, chain
 	if err != nil {
 		return nil, err
 	}
-	if txpoolImp.enableLocalsPersist {
+	if txpoolImp.enableLocalsPersist || txpoolImp.enableRemotesPersist {
 		if !fileutil.ExistDir(path.Dir(txpoolImp.txRecordsFile)) {
 			err = os.MkdirAll(filepath.Dir(txpoolImp.txRecordsFile), 0755)
 			if err != nil {
@@ -1051,12 +1212,18 @@ func newTxPoolImpl[T any, Constraint types.TXConstraint[T]](config Config, chain
 			}
 		}
 
-		if !fileutil.Exist(txpoolImp.txRecordsFile) {
+		if txpoolImp.enableLocalsPersist && !fileutil.Exist(txpoolImp.txRecordsFile) {
 			_, err = os.Create(txpoolImp.txRecordsFile)
 			if err != nil {
 				return nil, err
 			}
 		}
+		if txpoolImp.enableRemotesPersist && !fileutil.Exist(txpoolImp.txRemoteRecordsFile) {
+			_, err = os.Create(txpoolImp.txRemoteRecordsFile)
+			if err != nil {
+				return nil, err
+			}
+		}
 		err = txpoolImp.timerMgr.CreateTimer(RotateTxLocals, txpoolImp.rotateTxLocalsInterval, txpoolImp.handleRemoveTimeout)
 		if err != nil {
 			return nil, err
@@ -1066,6 +1233,7 @@ func newTxPoolImpl[T any, Constraint types.TXConstraint[T]](config Config, chain
 	txpoolImp.setPriceLimit(config.PriceLimit)
 
 	txpoolImp.logger.Infof("TxPool pool size = %d", txpoolImp.poolMaxSize)
+	txpoolImp.logger.Infof("TxPool account slots = %d, account queue = %d, global queue = %d", txpoolImp.accountSlots, txpoolImp.accountQueue, txpoolImp.globalQueue)
 	txpoolImp.logger.Infof("TxPool batch size = %d", txpoolImp.chainState.EpochInfo.ConsensusParams.BlockMaxTxNum)
 	txpoolImp.logger.Infof("TxPool enable generate empty batch = %v", txpoolImp.chainState.EpochInfo.ConsensusParams.EnableTimedGenEmptyBlock)
 	txpoolImp.logger.Infof("TxPool tolerance time = %v", txpoolImp.toleranceTime)
@@ -1075,8 +1243,10 @@ func newTxPoolImpl[T any, Constraint types.TXConstraint[T]](config Config, chain
 	txpoolImp.logger.Infof("TxPool rotate tx locals interval = %v", txpoolImp.rotateTxLocalsInterval)
 	txpoolImp.logger.Infof("TxPool enable locals persist = %v", txpoolImp.enableLocalsPersist)
 	txpoolImp.logger.Infof("TxPool tx records file = %s", txpoolImp.txRecordsFile)
+	txpoolImp.logger.Infof("TxPool enable remotes persist = %v", txpoolImp.enableRemotesPersist)
 	txpoolImp.logger.Infof("TxPool price limit = %v, priceBump = %v", txpoolImp.getPriceLimit(), txpoolImp.PriceBump)
 	txpoolImp.logger.Infof("TxPool enable price priority = %v", txpoolImp.enablePricePriority)
+	txpoolImp.logger.Infof("TxPool enable tip priority = %v", txpoolImp.enableTipPriority)
 	return txpoolImp, nil
 }
 
@@ -1092,11 +1262,11 @@ func (p *txPoolImpl[T, Constraint]) Init(conf commonpool.ConsensusConfig) {
 	p.notifyGenerateBatchFn = conf.NotifyGenerateBatchFn
 	p.notifyFindNextBatchFn = conf.NotifyFindNextBatchFn
 
-	if p.enableLocalsPersist {
+	if p.enableLocalsPersist || p.enableRemotesPersist {
 		if err := p.processRecords(); err != nil {
 			p.logger.Errorf("Failed to process records: %v", err)
 		}
-		if err := p.txRecords.rotate(p.txStore.allTxs); err != nil {
+		if err := p.handleRotateTxLocalsEvent(); err != nil {
 			p.logger.Errorf("Failed to rotate records: %v", err)
 		}
 	}
@@ -1154,6 +1324,10 @@ func (p *txPoolImpl[T, Constraint]) popExecutableTxs(size uint64, batch *commonp
 }
 
 func (p *txPoolImpl[T, Constraint]) popExecutableTxsByPrice(size uint64, batch *commonpool.RequestHashBatch[T, Constraint]) map[string]*internalTransaction[T, Constraint] {
+	// the base fee may change between batches, re-sort the txs by the effective tip
+	if p.enableTipPriority {
+		p.txStore.priorityByPrice.setBaseFee(p.getBaseFee())
+	}
 	currentSize := uint64(0)
 	removeInvalidTxs := make(map[string]*internalTransaction[T, Constraint])
 	for p.txStore.priorityByPrice.txsByPrice.length() > 0 && currentSize < size {
@@ -1296,7 +1470,12 @@ func (p *txPoolImpl[T, Constraint]) handleGenerateRequestBatch(typ int) (
 	}
 
 	// get executable txs
-	removeInvalidTxs := p.popExecutableTxs(batchSize, txBatch)
+	removeInvalidTxs := make(map[string]*internalTransaction[T, Constraint])
+	if batchSize > 0 {
+		removeInvalidTxs = p.popExecutableTxs(batchSize, txBatch)
+	}
+	nonBundleSize := txBatch.BatchItemSize()
+	bundleSize := p.fillBundles(txBatch)
 
 	if !p.chainState.EpochInfo.ConsensusParams.EnableTimedGenEmptyBlock && txBatch.BatchItemSize() == 0 && len(removeInvalidTxs) == 0 && p.hasPendingRequestInPool() {
 		err := fmt.Errorf("===== Note!!! Primary generate a batch with 0 txs, "+
@@ -1307,6 +1486,10 @@ func (p *txPoolImpl[T, Constraint]) handleGenerateRequestBatch(typ int) (
 	}
 
 	if typ != commonpool.GenBatchNoTxTimeoutEvent && txBatch.BatchItemSize() == 0 {
+		// the pending request may be set by the bundles which are not executable yet
+		if !p.checkPendingRequestInPool() {
+			p.setNoPendingRequest()
+		}
 		return removeInvalidTxs, nil, errors.New("there is no valid tx to generate batch")
 	}
 	txBatch.Timestamp = time.Now().UnixNano()
@@ -1314,14 +1497,14 @@ func (p *txPoolImpl[T, Constraint]) handleGenerateRequestBatch(typ int) (
 	txBatch.BatchHash = batchHash
 	p.txStore.batchesCache[batchHash] = txBatch
 
-	// reset PriorityNonBatchSize
-	if p.txStore.priorityNonBatchSize <= txBatch.BatchItemSize() {
+	// reset PriorityNonBatchSize, the bundle txs are not counted in it
+	if p.txStore.priorityNonBatchSize <= nonBundleSize {
 		p.setPriorityNonBatchSize(0)
 	} else {
-		p.decreasePriorityNonBatchSize(txBatch.BatchItemSize())
+		p.decreasePriorityNonBatchSize(nonBundleSize)
 	}
-	p.logger.Debugf("Primary generate a batch with %d txs, which hash is %s, and now there are %d "+
-		"pending txs and %d batches in txPool, pending Status: %v", txBatch.BatchItemSize(),
+	p.logger.Debugf("Primary generate a batch with %d txs(%d bundle txs), which hash is %s, and now there are %d "+
+		"pending txs and %d batches in txPool, pending Status: %v", txBatch.BatchItemSize(), bundleSize,
 		batchHash, p.txStore.priorityNonBatchSize, len(p.txStore.batchesCache), p.hasPendingRequestInPool())
 	return removeInvalidTxs, txBatch, nil
 }
@@ -1349,6 +1532,7 @@ func (p *txPoolImpl[T, Constraint]) handleRestoreOneBatch(batchHash string) erro
 	if err := p.putBackBatchedTxs(batch); err != nil {
 		return err
 	}
+	p.removeRestoredBundleTxs()
 
 	p.logger.Debugf("Restore one batch, which hash is %s, now there are %d non-batched txs, "+
 		"%d batches in txPool", batchHash, p.txStore.priorityNonBatchSize, len(p.txStore.batchesCache))
@@ -1846,6 +2030,9 @@ func (p *txPoolImpl[T, Constraint]) replaceTx(tx *T, local bool) bool {
 
 	// 1. insert new tx to pool
 	p.txStore.insertTxInPool(newPoolTx, local)
+	if replaced {
+		p.notifyReplacedTx(oldPoolTx, newPoolTx.getHash())
+	}
 
 	// if tx is the pending tx, update pending nonce
 	if txNonce == pendingNonce {
@@ -1866,6 +2053,7 @@ func (p *txPoolImpl[T, Constraint]) replaceTx(tx *T, local bool) bool {
 	} else if txNonce > pendingNonce {
 		// insert new tx to parking lot
 		p.txStore.parkingLotIndex.insertKey(newPoolTx)
+		p.txStore.insertEvictableTx(newPoolTx)
 		// replace old tx in parking lot is not needed to update parking lot size
 		if !replaced {
 			p.txStore.increaseParkingLotSize(1)
@@ -2034,6 +2222,7 @@ func (p *txPoolImpl[T, Constraint]) handleRestorePool() {
 	// clear missingTxs after abnormal.
 	p.txStore.missingBatch = make(map[string]map[uint64]string)
 	p.txStore.batchedTxs = make(map[txPointer]bool)
+	p.removeRestoredBundleTxs()
 	p.logger.Infof("After restore pool, there are %d non-batched txs, %d batches, "+
 		"priority len: %d, parkingLot len: %d, parkingLot size len: %d, batchedTx len: %d, txHashMap len: %d, local txs: %d", p.txStore.priorityNonBatchSize,
 		len(p.txStore.batchesCache), priorityLen, p.txStore.parkingLotIndex.size(), p.txStore.parkingLotSize,
@@ -2172,6 +2361,7 @@ func (p *txPoolImpl[T, Constraint]) removeHighNonceTxsByAccount(account string,
 		if err := p.cleanTxsByAccount(account, list, removeTxs, false); err != nil {
 			return 0, err
 		}
+		p.notifyDroppedTxs(DropReasonHighNonce, removeTxs)
 	}
 
 	return len(removeTxs), nil
@@ -2208,6 +2398,7 @@ func (p *txPoolImpl[T, Constraint]) processDirtyAccount(account string, tx *inte
 		} else {
 			// if not pending, we should insert tx to the parkingLotIndex
 			p.txStore.parkingLotIndex.insertKey(tx)
+			p.txStore.insertEvictableTx(tx)
 			p.txStore.increaseParkingLotSize(1)
 		}
 	}
@@ -2255,15 +2446,27 @@ func (p *txPoolImpl[T, Constraint]) setPriorityNonBatchSize(txnSize uint64) {
 	readyTxNum.Set(float64(p.txStore.priorityNonBatchSize))
 }
 
+// handleRotateTxLocalsEvent compacts the records into the txs still in the pool.
 func (p *txPoolImpl[T, Constraint]) handleRotateTxLocalsEvent() error {
-	return p.txRecords.rotate(p.txStore.allTxs)
+	if p.enableLocalsPersist {
+		if err := p.txRecords.rotate(p.txStore.allTxs); err != nil {
+			return err
+		}
+	}
+	if p.enableRemotesPersist {
+		if err := p.txRemoteRecords.rotate(p.txStore.allTxs); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (p *txPoolImpl[T, Constraint]) GetLocalTxs() [][]byte {
 	var res [][]byte
 	for _, txs := range p.txStore.allTxs {
 		for _, item := range txs.items {
-			if item.local {
+			// the private txs are never broadcast
+			if item.local && !item.private {
 				marshal, err := Constraint(item.rawTx).RbftMarshal()
 				if err != nil {
 					p.logger.Error("GetLocalTxs: failed to marshal local tx, hash is ", item.getHash())
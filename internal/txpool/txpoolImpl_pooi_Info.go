@@ -213,5 +213,5 @@ func (p *txPoolImpl[T, Constraint]) IsPoolFull() bool {
 }
 
 func (p *txPoolImpl[T, Constraint]) checkPoolFull() bool {
-	return uint64(len(p.txStore.txHashMap)) >= p.poolMaxSize
+	return uint64(p.txStore.poolTxCount()) >= p.poolMaxSize
 }
@@ -1689,6 +1689,56 @@ func TestTxPoolImpl_GenerateRequestBatch(t *testing.T) {
 	})
 }
 
+func TestTxPoolImpl_GenerateRequestBatchByTip(t *testing.T) {
+	ast := assert.New(t)
+	pool := mockTxPoolImplWithTyp[types.Transaction, *types.Transaction](t, repo.GenerateBatchByTip)
+	var baseFee atomic.Int64
+	pool.getBaseFee = func() *big.Int {
+		return big.NewInt(baseFee.Load())
+	}
+	pool.chainState.EpochInfo.ConsensusParams.BlockMaxTxNum = 3
+	ch := make(chan int, 1)
+	pool.notifyGenerateBatchFn = func(typ int) {
+		ch <- typ
+	}
+	err := pool.Start()
+	ast.Nil(err)
+	defer pool.Stop()
+
+	generateBatch := func(txs ...*internalTransaction[types.Transaction, *types.Transaction]) []string {
+		pool.AddRemoteTxs(lo.Map(txs, func(tx *internalTransaction[types.Transaction, *types.Transaction], _ int) *types.Transaction {
+			return tx.rawTx
+		}))
+		typ := <-ch
+		ast.Equal(commonpool.GenBatchSizeEvent, typ)
+		pool.ReplyBatchSignal()
+		batch, err := pool.GenerateRequestBatch(typ)
+		ast.Nil(err)
+		return batch.TxHashList
+	}
+
+	s1, err := types.GenerateSigner()
+	ast.Nil(err)
+	s2, err := types.GenerateSigner()
+	ast.Nil(err)
+	s3, err := types.GenerateSigner()
+	ast.Nil(err)
+
+	// base fee is 0, the effective tip is min(gasTipCap, gasFeeCap)
+	tx10 := constructPoolTxByTip(s1, 0, big.NewInt(300), big.NewInt(150))
+	tx20 := constructPoolTxByTip(s2, 0, big.NewInt(200), big.NewInt(200))
+	tx30 := constructPoolTxByTip(s3, 0, big.NewInt(100), big.NewInt(100))
+	ast.Equal([]string{tx20.getHash(), tx10.getHash(), tx30.getHash()}, generateBatch(tx10, tx20, tx30))
+
+	// base fee is 100, the effective tips are: tx11 150, tx12 900, tx21 100,
+	// tx12 has the highest tip but still follows tx11 of the same account
+	baseFee.Store(100)
+	tx11 := constructPoolTxByTip(s1, 1, big.NewInt(300), big.NewInt(150))
+	tx12 := constructPoolTxByTip(s1, 2, big.NewInt(1000), big.NewInt(1000))
+	tx21 := constructPoolTxByTip(s2, 1, big.NewInt(200), big.NewInt(200))
+	ast.Equal([]string{tx11.getHash(), tx12.getHash(), tx21.getHash()}, generateBatch(tx21, tx12, tx11))
+}
+
 func TestTxPoolImpl_ReConstructBatchByOrder(t *testing.T) {
 	ast := assert.New(t)
 	testcase := map[string]*txPoolImpl[types.Transaction, *types.Transaction]{
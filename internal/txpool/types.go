@@ -26,6 +26,11 @@ const (
 // nolint
 const (
 	DefaultPoolSize               = 50000
+	DefaultAccountSlots           = 1000
+	DefaultAccountQueue           = 256
+	DefaultGlobalQueue            = 10000
+	DefaultMaxBundles             = 1024
+	DefaultMaxBundleBlockDistance = 100
 	DefaultToleranceNonceGap      = 1000
 	DefaultPriceBump              = 10
 	DefaultToleranceTime          = 5 * time.Minute
@@ -48,8 +53,11 @@ type txPointer struct {
 type internalTransaction[T any, Constraint types.TXConstraint[T]] struct {
 	rawTx       *T
 	local       bool
-	lifeTime    int64 // track the local txs' broadcast time
-	arrivedTime int64 // track the local txs' arrived txpool time
+	lifeTime    int64  // track the local txs' broadcast time
+	arrivedTime int64  // track the local txs' arrived txpool time
+	private     bool   // the private tx is never broadcast to other nodes
+	maxBlock    uint64 // the max block height the private tx can be included in, 0 means no limit
+	bundle      bool   // the bundle tx must be batched together with the other txs of the bundle
 }
 
 type txPoolEvent any
@@ -61,6 +69,7 @@ const (
 	reBroadcastTxsEvent
 	missingTxsEvent
 	localRecordTxEvent
+	bundleTxsEvent
 )
 
 var addTxsEventToStr = map[int]string{
@@ -69,6 +78,7 @@ var addTxsEventToStr = map[int]string{
 	reBroadcastTxsEvent: "reBroadcastTxsEvent",
 	missingTxsEvent:     "missingTxsEvent",
 	localRecordTxEvent:  "localRecordTxEvent",
+	bundleTxsEvent:      "bundleTxsEvent",
 }
 
 // LocalEvent represents event sent by local modules
@@ -78,19 +88,33 @@ type addTxsEvent struct {
 }
 
 type reqLocalTx[T any, Constraint types.TXConstraint[T]] struct {
-	tx    *T
-	errCh chan error
+	tx       *T
+	private  bool
+	maxBlock uint64
+	errCh    chan error
 }
 
 type reqLocalRecordTx[T any, Constraint types.TXConstraint[T]] struct {
-	txs []*T
-	ch  chan int
+	txs    []*T
+	remote bool // the txs are reloaded from the remote records
+	ch     chan int
 }
 
 type reqRemoteTxs[T any, Constraint types.TXConstraint[T]] struct {
 	txs []*T
 }
 
+type reqBundleTxs[T any, Constraint types.TXConstraint[T]] struct {
+	txs      []*T
+	maxBlock uint64
+	respCh   chan *respBundleTxs
+}
+
+type respBundleTxs struct {
+	bundleID string
+	err      error
+}
+
 type reqMissingTxs[T any, Constraint types.TXConstraint[T]] struct {
 	batchHash string
 	txs       map[uint64]*T
@@ -242,6 +266,7 @@ const (
 	reqPendingTxCountEvent
 	reqPoolMetaEvent
 	reqAccountMetaEvent
+	reqTxStatusEvent
 )
 
 var poolInfoEventToStr = map[int]string{
@@ -250,6 +275,7 @@ var poolInfoEventToStr = map[int]string{
 	reqPendingTxCountEvent: "reqPendingTxCountEvent",
 	reqPoolMetaEvent:       "reqPoolMetaEvent",
 	reqAccountMetaEvent:    "reqAccountMetaEvent",
+	reqTxStatusEvent:       "reqTxStatusEvent",
 }
 
 // poolInfoEvent represents poolInfo event sent by local api modules
@@ -263,6 +289,11 @@ type reqTxMsg[T any, Constraint types.TXConstraint[T]] struct {
 	ch   chan *T
 }
 
+type reqTxStatusMsg struct {
+	hash string
+	ch   chan *TxStatusInfo
+}
+
 type reqNonceMsg struct {
 	account string
 	ch      chan uint64
@@ -111,6 +111,35 @@ func constructTx(s *types.Signer, nonce uint64) *types.Transaction {
 	return tx
 }
 
+func constructPoolTxByTip(s *types.Signer, nonce uint64, gasFeeCap, gasTipCap *big.Int) *internalTransaction[types.Transaction, *types.Transaction] {
+	t := to.ETHAddress()
+	inner := &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       21000,
+		To:        &t,
+		Value:     big.NewInt(0),
+	}
+	tx := &types.Transaction{
+		Inner: inner,
+		Time:  time.Now(),
+	}
+
+	if err := tx.SignByTxType(s.Sk); err != nil {
+		panic(err)
+	}
+
+	time.Sleep(1 * time.Millisecond)
+	return &internalTransaction[types.Transaction, *types.Transaction]{
+		rawTx:       tx,
+		local:       true,
+		lifeTime:    time.Now().Unix(),
+		arrivedTime: time.Now().Unix(),
+	}
+}
+
 func constructPoolTxByGas(s *types.Signer, nonce uint64, gasPrice *big.Int) *internalTransaction[types.Transaction, *types.Transaction] {
 	inner := &types.LegacyTx{
 		Nonce:    nonce,
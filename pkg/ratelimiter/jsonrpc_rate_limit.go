@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/juju/ratelimit"
 )
 
@@ -17,6 +18,7 @@ const (
 
 type JRateLimiter struct {
 	ratelimit.Bucket
+	fillInterval time.Duration
 }
 
 // returns a new token bucket that fills at the rate of one token every fillInterval,
@@ -28,19 +30,85 @@ func NewJRateLimiter(fillInterval time.Duration, capacity int64) (*JRateLimiter,
 
 // allows the specification of the quantum size - quantum tokens are added every fillInterval.
 func NewJRateLimiterWithQuantum(fillInterval time.Duration, capacity, quantum int64) (*JRateLimiter, error) {
+	if err := checkJRateLimiterParams(fillInterval, capacity, quantum); err != nil {
+		return nil, err
+	}
+
+	return &JRateLimiter{Bucket: *ratelimit.NewBucketWithQuantum(fillInterval, capacity, quantum), fillInterval: fillInterval}, nil
+}
+
+func checkJRateLimiterParams(fillInterval time.Duration, capacity, quantum int64) error {
 	if fillInterval == 0 {
-		return nil, errors.New("invalid interval value to init rate_limit")
+		return errors.New("invalid interval value to init rate_limit")
 	}
 	if capacity <= 0 {
-		return nil, errors.New("invalid capacity value to init rate_limit")
+		return errors.New("invalid capacity value to init rate_limit")
 	}
 	if quantum <= 0 {
-		return nil, errors.New("invalid quantum value to init rate_limit")
+		return errors.New("invalid quantum value to init rate_limit")
 	}
-
-	return &JRateLimiter{Bucket: *ratelimit.NewBucketWithQuantum(fillInterval, capacity, quantum)}, nil
+	return nil
 }
 
 func (l *JRateLimiter) JLimit() bool {
 	return l.TakeAvailable(OnceTakeCount) == 0
 }
+
+// JLimitWithRetryAfter is the same as JLimit, and returns the max duration to wait
+// for the next tokens if it is limited.
+func (l *JRateLimiter) JLimitWithRetryAfter() (bool, time.Duration) {
+	if l.JLimit() {
+		return true, l.fillInterval
+	}
+	return false, 0
+}
+
+// JCheckWithRetryAfter reports whether the count tokens are not available without taking them,
+// and returns the max duration to wait for the next tokens if it is limited.
+func (l *JRateLimiter) JCheckWithRetryAfter(count int64) (bool, time.Duration) {
+	if l.Available() < count {
+		return true, l.fillInterval
+	}
+	return false, 0
+}
+
+// JKeyedRateLimiter keeps a separate token bucket for each key (e.g. client ip),
+// the buckets of the least recently used keys are dropped once the number of keys exceeds maxKeys.
+type JKeyedRateLimiter struct {
+	fillInterval time.Duration
+	capacity     int64
+	quantum      int64
+	limiters     *lru.Cache[string, *JRateLimiter]
+}
+
+func NewJKeyedRateLimiter(fillInterval time.Duration, capacity, quantum int64, maxKeys int) (*JKeyedRateLimiter, error) {
+	if err := checkJRateLimiterParams(fillInterval, capacity, quantum); err != nil {
+		return nil, err
+	}
+	limiters, err := lru.New[string, *JRateLimiter](maxKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &JKeyedRateLimiter{
+		fillInterval: fillInterval,
+		capacity:     capacity,
+		quantum:      quantum,
+		limiters:     limiters,
+	}, nil
+}
+
+func (l *JKeyedRateLimiter) JLimitWithRetryAfter(key string) (bool, time.Duration) {
+	return l.Limiter(key).JLimitWithRetryAfter()
+}
+
+// Limiter returns the token bucket of the key, the bucket is created if it does not exist.
+func (l *JKeyedRateLimiter) Limiter(key string) *JRateLimiter {
+	limiter, ok := l.limiters.Get(key)
+	if !ok {
+		limiter = &JRateLimiter{Bucket: *ratelimit.NewBucketWithQuantum(l.fillInterval, l.capacity, l.quantum), fillInterval: l.fillInterval}
+		if previous, exist, _ := l.limiters.PeekOrAdd(key, limiter); exist {
+			limiter = previous
+		}
+	}
+	return limiter
+}
@@ -34,3 +34,61 @@ func TestNewJRateLimiterWithQuantum(t *testing.T) {
 	_, err = NewJRateLimiterWithQuantum(50*time.Millisecond, 10000, -1)
 	assert.NotNil(t, err)
 }
+
+func TestJRateLimiter_JLimitWithRetryAfter(t *testing.T) {
+	limiter, err := NewJRateLimiter(10*time.Second, 1)
+	assert.Nil(t, err)
+	limited, retryAfter := limiter.JLimitWithRetryAfter()
+	assert.False(t, limited)
+	assert.Equal(t, time.Duration(0), retryAfter)
+
+	limited, retryAfter = limiter.JLimitWithRetryAfter()
+	assert.True(t, limited)
+	assert.Equal(t, 10*time.Second, retryAfter)
+}
+
+func TestJRateLimiter_JCheckWithRetryAfter(t *testing.T) {
+	limiter, err := NewJRateLimiter(10*time.Second, 2)
+	assert.Nil(t, err)
+	limited, retryAfter := limiter.JCheckWithRetryAfter(2)
+	assert.False(t, limited)
+	assert.Equal(t, time.Duration(0), retryAfter)
+
+	limited, retryAfter = limiter.JCheckWithRetryAfter(3)
+	assert.True(t, limited)
+	assert.Equal(t, 10*time.Second, retryAfter)
+
+	// the check does not take the tokens
+	assert.EqualValues(t, 2, limiter.Available())
+}
+
+func TestJKeyedRateLimiter(t *testing.T) {
+	limiter, err := NewJKeyedRateLimiter(10*time.Second, 2, 1, 2)
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		limited, _ := limiter.JLimitWithRetryAfter("a")
+		assert.False(t, limited)
+	}
+	limited, retryAfter := limiter.JLimitWithRetryAfter("a")
+	assert.True(t, limited)
+	assert.Equal(t, 10*time.Second, retryAfter)
+
+	// the buckets of the different keys are independent
+	limited, _ = limiter.JLimitWithRetryAfter("b")
+	assert.False(t, limited)
+
+	// the bucket of the least recently used key is dropped
+	limited, _ = limiter.JLimitWithRetryAfter("c")
+	assert.False(t, limited)
+	limited, _ = limiter.JLimitWithRetryAfter("a")
+	assert.False(t, limited)
+
+	// the limiter of the key shares the bucket
+	assert.Same(t, limiter.Limiter("a"), limiter.Limiter("a"))
+	assert.NotSame(t, limiter.Limiter("a"), limiter.Limiter("b"))
+
+	_, err = NewJKeyedRateLimiter(0, 2, 1, 2)
+	assert.NotNil(t, err)
+	_, err = NewJKeyedRateLimiter(10*time.Second, 2, 1, 0)
+	assert.NotNil(t, err)
+}
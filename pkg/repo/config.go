@@ -41,6 +41,7 @@ type Config struct {
 	Monitor        Monitor        `mapstructure:"monitor" toml:"monitor"`
 	Log            Log            `mapstructure:"log" toml:"log"`
 	Access         Access         `mapstructure:"access" toml:"access"`
+	TxAdmission    TxAdmission    `mapstructure:"tx_admission" toml:"tx_admission"`
 }
 
 type Port struct {
@@ -72,10 +73,43 @@ type JsonRPC struct {
 	WriteLimiter                 JLimiter   `mapstructure:"write_limiter" toml:"write_limiter"`
 	RejectTxsIfConsensusAbnormal bool       `mapstructure:"reject_txs_if_consensus_abnormal" toml:"reject_txs_if_consensus_abnormal"`
 	QueryLimit                   QueryLimit `mapstructure:"query_limit" toml:"query_limit"`
+
+	// limit the calls of each client ip
+	ClientLimiter  JLimiter         `mapstructure:"client_limiter" toml:"client_limiter"`
+	MethodLimiters []JMethodLimiter `mapstructure:"method_limiters" toml:"method_limiters"`
+	// max number of the calls in a batch request, 0 means no limit
+	BatchRequestLimit int `mapstructure:"batch_request_limit" toml:"batch_request_limit"`
+	// max size of the batch response in bytes, 0 means no limit
+	BatchResponseMaxSize int `mapstructure:"batch_response_max_size" toml:"batch_response_max_size"`
+
+	Auth JsonRPCAuth `mapstructure:"auth" toml:"auth"`
+}
+
+type JsonRPCAuth struct {
+	Enable bool `mapstructure:"enable" toml:"enable"`
+	// the namespaces or methods allowed for the requests without credentials, see APIKey.Allowed
+	PublicAllowed []string `mapstructure:"public_allowed" toml:"public_allowed"`
+	// hex encoded HS256 secret to verify the jwt in the Authorization header,
+	// the subject of the jwt is the name of the api key whose permissions are granted
+	JWTSecret string   `mapstructure:"jwt_secret" toml:"jwt_secret"`
+	APIKeys   []APIKey `mapstructure:"api_keys" toml:"api_keys"`
+}
+
+type APIKey struct {
+	Name string `mapstructure:"name" toml:"name"`
+	// passed by the X-API-Key header or the apikey query parameter
+	Key string `mapstructure:"key" toml:"key"`
+	// the allowed namespaces (e.g. eth), methods (e.g. debug_traceTransaction) or method prefixes (e.g. debug_trace*),
+	// * allows all, the websocket connections can only use the allowed namespaces
+	Allowed []string `mapstructure:"allowed" toml:"allowed"`
+	Limiter JLimiter `mapstructure:"limiter" toml:"limiter"`
 }
 
 type QueryLimit struct {
-	GetLogsBlockRangeLimit uint64 `mapstructure:"get_logs_block_range_limit" toml:"get_logs_block_range_limit"`
+	GetLogsBlockRangeLimit     uint64 `mapstructure:"get_logs_block_range_limit" toml:"get_logs_block_range_limit"`
+	TraceFilterBlockRangeLimit uint64 `mapstructure:"trace_filter_block_range_limit" toml:"trace_filter_block_range_limit"`
+	InternalTxBlockRangeLimit  uint64 `mapstructure:"internal_tx_block_range_limit" toml:"internal_tx_block_range_limit"`
+	AddressTxsPageSizeLimit    uint64 `mapstructure:"address_txs_page_size_limit" toml:"address_txs_page_size_limit"`
 }
 
 type P2PPipeGossipsub struct {
@@ -131,6 +165,16 @@ type JLimiter struct {
 	Enable   bool     `mapstructure:"enable" toml:"enable"`
 }
 
+type JMethodLimiter struct {
+	// the trailing '*' matches all the methods with the prefix, e.g. debug_trace*
+	Method   string   `mapstructure:"method" toml:"method"`
+	Interval Duration `mapstructure:"interval" toml:"interval"`
+	Quantum  int64    `mapstructure:"quantum" toml:"quantum"`
+	Capacity int64    `mapstructure:"capacity" toml:"capacity"`
+	// limit the calls of each client ip separately instead of all the clients together
+	PerClient bool `mapstructure:"per_client" toml:"per_client"`
+}
+
 type Log struct {
 	Level            string `mapstructure:"level" toml:"level"`
 	Filename         string `mapstructure:"filename" toml:"filename"`
@@ -168,6 +212,25 @@ type Access struct {
 	EnableWhitelist bool `mapstructure:"enable_whitelist" toml:"enable_whitelist"`
 }
 
+type TxAdmission struct {
+	// senders which are not allowed to deploy contracts
+	ContractCreationDenyList []string `mapstructure:"contract_creation_deny_list" toml:"contract_creation_deny_list"`
+	// max calldata size of the tx from any sender, 0 means no limit
+	MaxCalldataSize uint64 `mapstructure:"max_calldata_size" toml:"max_calldata_size"`
+	// max calldata size of the tx from the given senders, overrides MaxCalldataSize
+	SenderMaxCalldataSize []SenderCalldataLimit `mapstructure:"sender_max_calldata_size" toml:"sender_max_calldata_size"`
+	// if not empty, only the txs to these addresses are allowed, contract creations are not affected
+	ToAllowList []string `mapstructure:"to_allow_list" toml:"to_allow_list"`
+	ToDenyList  []string `mapstructure:"to_deny_list" toml:"to_deny_list"`
+	// min effective priority fee per gas, 0 means no limit
+	MinPriorityFee *types.CoinNumber `mapstructure:"min_priority_fee" toml:"min_priority_fee"`
+}
+
+type SenderCalldataLimit struct {
+	Address string `mapstructure:"address" toml:"address"`
+	MaxSize uint64 `mapstructure:"max_size" toml:"max_size"`
+}
+
 type Sync struct {
 	FullValidation        bool     `mapstructure:"full_validation" toml:"full_validation"`
 	WaitStatesTimeout     Duration `mapstructure:"wait_states_timeout" toml:"wait_states_timeout"`
@@ -175,6 +238,12 @@ type Sync struct {
 	TimeoutCountLimit     uint64   `mapstructure:"timeout_count_limit" toml:"timeout_count_limit"`
 	ConcurrencyLimit      uint64   `mapstructure:"concurrency_limit" toml:"concurrency_limit"`
 	MaxChunkSize          uint64   `mapstructure:"max_chunk_size" toml:"max_chunk_size"`
+
+	// HeaderFirst downloads and verifies the headers against the quorum checkpoints first,
+	// then fetches the bodies from all peers concurrently while committing the verified blocks
+	HeaderFirst     bool   `mapstructure:"header_first" toml:"header_first"`
+	PeerConcurrency uint64 `mapstructure:"peer_concurrency" toml:"peer_concurrency"`
+	PipelineWindow  uint64 `mapstructure:"pipeline_window" toml:"pipeline_window"`
 }
 
 type Consensus struct {
@@ -203,8 +272,11 @@ type Ledger struct {
 	StateLedgerAccountTrieCacheMegabytesLimit int  `mapstructure:"state_ledger_account_trie_cache_megabytes_limit" toml:"state_ledger_account_trie_cache_megabytes_limit"`
 	StateLedgerStorageTrieCacheMegabytesLimit int  `mapstructure:"state_ledger_storage_trie_cache_megabytes_limit" toml:"state_ledger_storage_trie_cache_megabytes_limit"`
 	EnablePrune                               bool `mapstructure:"enable_prune" toml:"enable_prune"`
+	EnableArchive                             bool `mapstructure:"enable_archive" toml:"enable_archive"`
 	EnablePreload                             bool `mapstructure:"enable_preload" toml:"enable_preload"`
 	EnableIndexer                             bool `mapstructure:"enable_indexer" toml:"enable_indexer"`
+	EnableInternalTxIndexer                   bool `mapstructure:"enable_internal_tx_indexer" toml:"enable_internal_tx_indexer"`
+	EnableAddressTxIndexer                    bool `mapstructure:"enable_address_tx_indexer" toml:"enable_address_tx_indexer"`
 	StateLedgerReservedHistoryBlockNum        int  `mapstructure:"state_ledger_reserved_history_block_num" toml:"state_ledger_reserved_history_block_num"`
 }
 
@@ -218,6 +290,18 @@ type Executor struct {
 	DisableRollback bool   `mapstructure:"disable_rollback" toml:"disable_rollback"`
 }
 
+// IsDevMode reports whether the node runs for development, which enables the dev only APIs
+// like the state setters and the account impersonation.
+func (c *Config) IsDevMode() bool {
+	return c.Executor.Type == ExecTypeDev || c.Consensus.Type == ConsensusTypeSoloDev
+}
+
+// IsImpersonationSupported reports whether the unsigned txs of the impersonated accounts are accepted, which is
+// only supported by the solo_dev consensus, the other nodes can not recover the senders from their signatures.
+func (c *Config) IsImpersonationSupported() bool {
+	return c.Consensus.Type == ConsensusTypeSoloDev
+}
+
 var SupportMultiNode = make(map[string]bool)
 var registrationMutex sync.Mutex
 
@@ -282,7 +366,25 @@ func defaultConfig() *Config {
 			},
 			RejectTxsIfConsensusAbnormal: false,
 			QueryLimit: QueryLimit{
-				GetLogsBlockRangeLimit: 2000,
+				GetLogsBlockRangeLimit:     2000,
+				TraceFilterBlockRangeLimit: 100,
+				InternalTxBlockRangeLimit:  2000,
+				AddressTxsPageSizeLimit:    100,
+			},
+			ClientLimiter: JLimiter{
+				Interval: Duration(time.Second),
+				Quantum:  100,
+				Capacity: 200,
+				Enable:   false,
+			},
+			MethodLimiters:       []JMethodLimiter{},
+			BatchRequestLimit:    1000,
+			BatchResponseMaxSize: 25 * 1000 * 1000,
+			Auth: JsonRPCAuth{
+				Enable:        false,
+				PublicAllowed: []string{"eth", "net", "web3", "axm"},
+				JWTSecret:     "",
+				APIKeys:       []APIKey{},
 			},
 		},
 		P2P: P2P{
@@ -315,6 +417,9 @@ func defaultConfig() *Config {
 			ConcurrencyLimit:      100,
 			MaxChunkSize:          1000,
 			FullValidation:        true,
+			HeaderFirst:           false,
+			PeerConcurrency:       20,
+			PipelineWindow:        2000,
 		},
 		Consensus: Consensus{
 			Type:        ConsensusTypeRbft,
@@ -338,8 +443,11 @@ func defaultConfig() *Config {
 			StateLedgerAccountTrieCacheMegabytesLimit: 128,
 			StateLedgerStorageTrieCacheMegabytesLimit: 128,
 			EnablePrune:                        true,
+			EnableArchive:                      false,
 			EnablePreload:                      false,
 			EnableIndexer:                      false,
+			EnableInternalTxIndexer:            false,
+			EnableAddressTxIndexer:             false,
 			StateLedgerReservedHistoryBlockNum: 256,
 		},
 		Snapshot: Snapshot{
@@ -388,6 +496,14 @@ func defaultConfig() *Config {
 		Access: Access{
 			EnableWhitelist: false,
 		},
+		TxAdmission: TxAdmission{
+			ContractCreationDenyList: []string{},
+			MaxCalldataSize:          0,
+			SenderMaxCalldataSize:    []SenderCalldataLimit{},
+			ToAllowList:              []string{},
+			ToDenyList:               []string{},
+			MinPriorityFee:           types.CoinNumberByMol(0),
+		},
 	}
 }
 
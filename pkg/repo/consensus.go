@@ -13,6 +13,13 @@ import (
 const (
 	GenerateBatchByTime     = "fifo" // default
 	GenerateBatchByGasPrice = "price_priority"
+	GenerateBatchByTip      = "tip_priority"
+)
+
+const (
+	DevMiningModeAuto     = "auto" // default
+	DevMiningModeInterval = "interval"
+	DevMiningModeManual   = "manual"
 )
 
 type ReceiveMsgLimiter struct {
@@ -28,6 +35,7 @@ type ConsensusConfig struct {
 	TxCache       TxCache           `mapstructure:"tx_cache" toml:"tx_cache"`
 	Rbft          RBFT              `mapstructure:"rbft" toml:"rbft"`
 	Solo          Solo              `mapstructure:"solo" toml:"solo"`
+	SoloDev       SoloDev           `mapstructure:"solo_dev" toml:"solo_dev"`
 }
 
 type TimedGenBlock struct {
@@ -36,11 +44,17 @@ type TimedGenBlock struct {
 
 type TxPool struct {
 	PoolSize               uint64            `mapstructure:"pool_size" toml:"pool_size"`
+	AccountSlots           uint64            `mapstructure:"account_slots" toml:"account_slots"`
+	AccountQueue           uint64            `mapstructure:"account_queue" toml:"account_queue"`
+	GlobalQueue            uint64            `mapstructure:"global_queue" toml:"global_queue"`
+	MaxBundles             uint64            `mapstructure:"max_bundles" toml:"max_bundles"`
+	MaxBundleBlockDistance uint64            `mapstructure:"max_bundle_block_distance" toml:"max_bundle_block_distance"`
 	ToleranceTime          Duration          `mapstructure:"tolerance_time" toml:"tolerance_time"`
 	ToleranceRemoveTime    Duration          `mapstructure:"tolerance_remove_time" toml:"tolerance_remove_time"`
 	CleanEmptyAccountTime  Duration          `mapstructure:"clean_empty_account_time" toml:"clean_empty_account_time"`
 	ToleranceNonceGap      uint64            `mapstructure:"tolerance_nonce_gap" toml:"tolerance_nonce_gap"`
 	EnableLocalsPersist    bool              `mapstructure:"enable_locals_persist" toml:"enable_locals_persist"`
+	EnableRemotesPersist   bool              `mapstructure:"enable_remotes_persist" toml:"enable_remotes_persist"`
 	RotateTxLocalsInterval Duration          `mapstructure:"rotate_tx_locals_interval" toml:"rotate_tx_locals_interval"`
 	PriceLimit             *types.CoinNumber `mapstructure:"price_limit" toml:"price_limit"`
 	PriceBump              uint64            `mapstructure:"price_bump" toml:"price_bump"`
@@ -75,6 +89,18 @@ type Solo struct {
 	BatchTimeout Duration `mapstructure:"batch_timeout" toml:"batch_timeout"`
 }
 
+type SoloDev struct {
+	// auto mines a block for each tx, interval mines a block with the pending txs every mining interval,
+	// manual only mines blocks by evm_mine
+	MiningMode     string   `mapstructure:"mining_mode" toml:"mining_mode"`
+	MiningInterval Duration `mapstructure:"mining_interval" toml:"mining_interval"`
+
+	// the accounts and storage missing locally are read from the remote JSON-RPC endpoint at the pinned block,
+	// the latest block of the remote is pinned at the first start if the block number is 0
+	ForkURL         string `mapstructure:"fork_url" toml:"fork_url"`
+	ForkBlockNumber uint64 `mapstructure:"fork_block_number" toml:"fork_block_number"`
+}
+
 func DefaultConsensusConfig() *ConsensusConfig {
 	if testNetConsensusConfigBuilder, ok := TestNetConsensusConfigBuilderMap[BuildNet]; ok {
 		return testNetConsensusConfigBuilder()
@@ -95,12 +121,18 @@ func defaultConsensusConfig() *ConsensusConfig {
 		},
 		TxPool: TxPool{
 			PoolSize:               50000,
+			AccountSlots:           1000,
+			AccountQueue:           256,
+			GlobalQueue:            10000,
+			MaxBundles:             1024,
+			MaxBundleBlockDistance: 100,
 			ToleranceTime:          Duration(5 * time.Minute),
 			ToleranceRemoveTime:    Duration(15 * time.Minute),
 			CleanEmptyAccountTime:  Duration(10 * time.Minute),
 			RotateTxLocalsInterval: Duration(1 * time.Hour),
 			ToleranceNonceGap:      1000,
 			EnableLocalsPersist:    true,
+			EnableRemotesPersist:   false,
 			PriceLimit:             GetDefaultMinGasPrice(),
 			PriceBump:              10,
 			GenerateBatchType:      GenerateBatchByTime,
@@ -128,6 +160,10 @@ func defaultConsensusConfig() *ConsensusConfig {
 		Solo: Solo{
 			BatchTimeout: Duration(500 * time.Millisecond),
 		},
+		SoloDev: SoloDev{
+			MiningMode:     DevMiningModeAuto,
+			MiningInterval: Duration(1 * time.Second),
+		},
 	}
 }
 
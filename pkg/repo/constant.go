@@ -34,6 +34,8 @@ const (
 
 	pidFileName = "running.pid"
 
+	snapSyncProgressFileName = "snap-sync-progress.json"
+
 	LogsDirName = "logs"
 )
 
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// SnapSyncProgress is the snap sync state persisted in the repo, so that an interrupted snap sync
+// can resume instead of starting from scratch after restart.
+type SnapSyncProgress struct {
+	TargetHeight uint64 `json:"target_height"`
+	TargetDigest string `json:"target_digest"`
+	TargetEpoch  uint64 `json:"target_epoch"`
+
+	// TrieVerified means the trie of target block has been verified
+	TrieVerified bool `json:"trie_verified"`
+}
+
+// MatchTarget returns whether the progress belongs to the snap sync towards the target block.
+func (p *SnapSyncProgress) MatchTarget(height uint64, digest string) bool {
+	return p.TargetHeight == height && p.TargetDigest == digest
+}
+
+// LoadSnapSyncProgress resumes the persisted progress if it belongs to the snap sync towards the same target,
+// otherwise a fresh progress is persisted. The chain data is persisted in order, so the sync resumes
+// from the latest block of the ledger, the progress only keeps what the ledger can not tell.
+func LoadSnapSyncProgress(rootPath string, height uint64, digest string, epoch uint64) (progress *SnapSyncProgress, resumed bool, err error) {
+	progress, err = ReadSnapSyncProgress(rootPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if progress != nil && progress.MatchTarget(height, digest) && progress.TargetEpoch == epoch {
+		return progress, true, nil
+	}
+
+	progress = &SnapSyncProgress{
+		TargetHeight: height,
+		TargetDigest: digest,
+		TargetEpoch:  epoch,
+	}
+	if err = WriteSnapSyncProgress(rootPath, progress); err != nil {
+		return nil, false, err
+	}
+	return progress, false, nil
+}
+
+// ReadSnapSyncProgress reads the persisted snap sync progress, it returns nil if there is no progress.
+func ReadSnapSyncProgress(rootPath string) (*SnapSyncProgress, error) {
+	data, err := os.ReadFile(filepath.Join(rootPath, snapSyncProgressFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to read snap sync progress file")
+	}
+
+	progress := &SnapSyncProgress{}
+	if err := json.Unmarshal(data, progress); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snap sync progress")
+	}
+	return progress, nil
+}
+
+// WriteSnapSyncProgress persists the snap sync progress, the file is replaced atomically
+// so that a crash during writing never leaves a broken progress.
+func WriteSnapSyncProgress(rootPath string, progress *SnapSyncProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal snap sync progress")
+	}
+
+	filePath := filepath.Join(rootPath, snapSyncProgressFileName)
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return errors.Wrap(err, "failed to write snap sync progress file")
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return errors.Wrap(err, "failed to write snap sync progress file")
+	}
+	return nil
+}
+
+func RemoveSnapSyncProgress(rootPath string) error {
+	if err := os.Remove(filepath.Join(rootPath, snapSyncProgressFileName)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove snap sync progress file")
+	}
+	return nil
+}
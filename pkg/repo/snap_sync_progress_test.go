@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapSyncProgress(t *testing.T) {
+	repoRoot := t.TempDir()
+
+	progress, err := ReadSnapSyncProgress(repoRoot)
+	assert.Nil(t, err)
+	assert.Nil(t, progress)
+
+	progress = &SnapSyncProgress{
+		TargetHeight: 300,
+		TargetDigest: "hash300",
+		TargetEpoch:  3,
+		TrieVerified: true,
+	}
+
+	err = WriteSnapSyncProgress(repoRoot, progress)
+	assert.Nil(t, err)
+
+	loaded, err := ReadSnapSyncProgress(repoRoot)
+	assert.Nil(t, err)
+	assert.Equal(t, progress, loaded)
+	assert.True(t, loaded.MatchTarget(300, "hash300"))
+	assert.False(t, loaded.MatchTarget(300, "hash"))
+	assert.False(t, loaded.MatchTarget(200, "hash300"))
+
+	err = RemoveSnapSyncProgress(repoRoot)
+	assert.Nil(t, err)
+	progress, err = ReadSnapSyncProgress(repoRoot)
+	assert.Nil(t, err)
+	assert.Nil(t, progress)
+
+	// remove a nonexistent progress
+	err = RemoveSnapSyncProgress(repoRoot)
+	assert.Nil(t, err)
+}
+
+func TestLoadSnapSyncProgress(t *testing.T) {
+	repoRoot := t.TempDir()
+
+	// the first snap sync starts with a fresh progress
+	progress, resumed, err := LoadSnapSyncProgress(repoRoot, 300, "hash300", 3)
+	assert.Nil(t, err)
+	assert.False(t, resumed)
+	assert.False(t, progress.TrieVerified)
+	persisted, err := ReadSnapSyncProgress(repoRoot)
+	assert.Nil(t, err)
+	assert.Equal(t, progress, persisted)
+
+	// the snap sync is interrupted after the trie is verified
+	progress.TrieVerified = true
+	err = WriteSnapSyncProgress(repoRoot, progress)
+	assert.Nil(t, err)
+
+	// the restarted snap sync towards the same target resumes the progress
+	progress, resumed, err = LoadSnapSyncProgress(repoRoot, 300, "hash300", 3)
+	assert.Nil(t, err)
+	assert.True(t, resumed)
+	assert.True(t, progress.TrieVerified)
+
+	// the progress of another target is discarded
+	for _, target := range []struct {
+		height uint64
+		digest string
+		epoch  uint64
+	}{
+		{height: 400, digest: "hash300", epoch: 3},
+		{height: 300, digest: "hash", epoch: 3},
+		{height: 300, digest: "hash300", epoch: 4},
+	} {
+		err = WriteSnapSyncProgress(repoRoot, &SnapSyncProgress{TargetHeight: 300, TargetDigest: "hash300", TargetEpoch: 3, TrieVerified: true})
+		assert.Nil(t, err)
+		progress, resumed, err = LoadSnapSyncProgress(repoRoot, target.height, target.digest, target.epoch)
+		assert.Nil(t, err)
+		assert.False(t, resumed)
+		assert.False(t, progress.TrieVerified)
+		persisted, err = ReadSnapSyncProgress(repoRoot)
+		assert.Nil(t, err)
+		assert.Equal(t, progress, persisted)
+	}
+
+	// the finished snap sync removes the progress, the next one starts from scratch
+	err = RemoveSnapSyncProgress(repoRoot)
+	assert.Nil(t, err)
+	_, resumed, err = LoadSnapSyncProgress(repoRoot, 300, "hash300", 3)
+	assert.Nil(t, err)
+	assert.False(t, resumed)
+}